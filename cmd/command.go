@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// runCommand executes the cli command using the initialized application
+func runCommand(ctx context.Context, name string, args []string) error {
+	switch name {
+	case "import":
+		return runImport(ctx, args)
+	case "export":
+		return runExport(ctx, args)
+	case "migrate":
+		return runMigrate(ctx, args)
+	case "schema":
+		return runSchema(ctx, args)
+	case "leaderboard":
+		return runLeaderboard(ctx, args)
+	}
+
+	return fmt.Errorf("unknown command %q", name)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"article-tag/internal/bulk"
+	"article-tag/internal/constant"
+	"article-tag/internal/handler"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// runExport writes the follows and counters of a publication as csv or jsonl
+//
+//	main export -publication AK [-format jsonl|csv] [-out snapshot.jsonl] [-segments 4] [-rate 500]
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	publication := fs.String("publication", "", "publication to export")
+	format := fs.String("format", bulk.FormatJSONL, "output format jsonl or csv")
+	out := fs.String("out", "", "output file, defaults to stdout")
+	segments := fs.Int("segments", constant.ExportSegments, "number of parallel scan segments")
+	rate := fs.Float64("rate", constant.ExportRate, "maximum rows read per second, 0 for unlimited")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *publication == "" {
+		return errors.New("-publication is required")
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	logger := handler.GetLogger(app)
+	exporter := bulk.NewExporter(handler.GetModels(app).Tag, logger, bulk.ExportOptions{Segments: *segments, Rate: *rate})
+
+	result, err := exporter.Export(ctx, *publication, *format, w)
+	if result != nil {
+		logger.Info("export finished", zap.String("publication", *publication), zap.Any("result", result))
+	}
+
+	return err
+}
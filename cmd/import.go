@@ -0,0 +1,63 @@
+package main
+
+import (
+	"article-tag/internal/bulk"
+	"article-tag/internal/constant"
+	"article-tag/internal/handler"
+	"context"
+	"errors"
+	"flag"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// runImport loads follows from a csv or jsonl file
+//
+//	main import -file follows.csv [-format csv|jsonl] [-rejects rejects.csv] [-batch 25] [-rate 100]
+func runImport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := fs.String("file", "", "csv or jsonl file with username, publication, tag_id and tag_name")
+	format := fs.String("format", "", "input format csv or jsonl, detected from the file extension by default")
+	rejects := fs.String("rejects", "", "file to write the rejected rows, defaults to <file>.rejects")
+	batch := fs.Int("batch", constant.BatchWriteLimit, "rows per batch write, at most 25")
+	rate := fs.Float64("rate", 100, "maximum rows written per second, 0 for unlimited")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+
+	if *format == "" {
+		*format = bulk.DetectFormat(*file)
+	}
+
+	if *rejects == "" {
+		*rejects = *file + ".rejects"
+	}
+
+	in, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(*rejects)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	logger := handler.GetLogger(app)
+	importer := bulk.NewImporter(handler.GetModels(app).Tag, logger, bulk.ImportOptions{BatchSize: *batch, Rate: *rate})
+
+	result, err := importer.Import(ctx, in, *format, out)
+	if result != nil {
+		logger.Info("import finished", zap.Any("result", result), zap.String("rejects", *rejects))
+	}
+
+	return err
+}
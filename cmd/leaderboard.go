@@ -0,0 +1,28 @@
+package main
+
+import (
+	"article-tag/internal/handler"
+	"article-tag/internal/leaderboard"
+	"context"
+	"flag"
+)
+
+// runLeaderboard materializes the leaderboard of a publication, or of every publication, once
+//
+//	main leaderboard [-publication AK]
+func runLeaderboard(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("leaderboard", flag.ContinueOnError)
+	publication := fs.String("publication", "", "publication to refresh, defaults to every publication")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	refresher := leaderboard.NewRefresher(handler.GetModels(app).Tag, handler.GetLogger(app))
+
+	if *publication != "" {
+		return refresher.Refresh(ctx, *publication)
+	}
+
+	return refresher.RefreshAll(ctx)
+}
@@ -1,22 +1,39 @@
 package main
 
 import (
+	"article-tag/internal/cache"
+	"article-tag/internal/config"
+	"article-tag/internal/constant"
 	"article-tag/internal/database"
+	"article-tag/internal/grpcapi"
 	"article-tag/internal/handler"
+	"article-tag/internal/leaderboard"
+	"article-tag/internal/migration"
 	"article-tag/internal/model"
+	"article-tag/internal/resilience"
 	"article-tag/internal/routes"
+	"article-tag/internal/schema"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
 	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
 )
 
-var app *handler.Application
+var (
+	app        *handler.Application
+	grpcServer *grpc.Server
+	migrator   *migration.Runner
+	schemaDB   schema.TableUpdater
+)
 
 // init
 func init() {
@@ -29,15 +46,27 @@ func init() {
 	// initialize logger
 	logger := initLogger()
 
-	models := model.NewModel(db, logger)
+	// every dynamodb call of the models, migrations and schema goes through the resilience layer
+	client := resilience.NewClient(db, logger)
+
+	models := model.NewModel(client, logger)
 
-	// check and create table
-	err = checkAndCreateTable(&models)
+	// read-through cache of the popular rankings and followed tags, CACHE_BACKEND=none disables it
+	c, err := cache.New()
 	if err != nil {
 		panic(err)
 	}
 
+	if c != nil {
+		models.CacheMetrics = cache.NewMetrics()
+		models.Tag = model.NewCachedTag(models.Tag, c, models.CacheMetrics, logger)
+	}
+
+	migrator = migration.NewRunner(client, model.TableName(), model.Migrations(client, logger), logger)
+	schemaDB = client
+
 	app = handler.New(db, &models, logger)
+	grpcServer = grpcapi.NewGRPCServer(grpcapi.New(&models, logger))
 }
 
 func initLogger() *zap.Logger {
@@ -67,26 +96,68 @@ func initLogger() *zap.Logger {
 	return logger
 }
 
-// checkAndCreateTable
-func checkAndCreateTable(models *model.Models) error {
+// checkSchemaVersion refuses to start the server when migrations are pending,
+// unless AUTO_MIGRATE is set in which case they are applied.
+// With SCHEMA_RECONCILE the billing mode and capacities are then reconciled with the configuration.
+func checkSchemaVersion(ctx context.Context) error {
 	// wait for some time until docker is up
 	time.Sleep(time.Second * 2)
 
-	// check table exists
-	err := models.Tag.DescribeTable(context.TODO())
-	if err != nil {
-		err = models.Tag.CreateTable(context.TODO())
-		if err != nil {
-			log.Println("error creating table : ", err)
+	if config.Bool("AUTO_MIGRATE", false) {
+		if _, err := migrator.Up(ctx); err != nil {
+			return err
+		}
+	} else {
+		err := migrator.Check(ctx)
+		if errors.Is(err, migration.ErrSchemaBehind) {
+			return fmt.Errorf("%w, run `main migrate up` first", err)
+		}
 
+		if err != nil {
 			return err
 		}
 	}
 
+	if config.Bool("SCHEMA_RECONCILE", false) {
+		return reconcileSchema(ctx)
+	}
+
 	return nil
 }
 
 func main() {
+	// run the cli command when one is passed, e.g. `main import -file follows.csv`
+	if len(os.Args) > 1 {
+		if err := runCommand(context.Background(), os.Args[1], os.Args[2:]); err != nil {
+			log.Fatalf("error running command %v : %v", os.Args[1], err)
+		}
+
+		return
+	}
+
+	if err := checkSchemaVersion(context.Background()); err != nil {
+		log.Fatalf("error checking schema version : %v", err)
+	}
+
+	// leaderboards of the popular tags, refreshed in the background every LEADERBOARD_INTERVAL
+	go leaderboard.NewRefresher(handler.GetModels(app).Tag, handler.GetLogger(app)).Run(context.Background())
+
+	// grpc api on its own port, sharing the models and validation rules of the rest api
+	grpcPort := config.String("GRPC_PORT", constant.GRPCPort)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%v", grpcPort))
+	if err != nil {
+		log.Fatalf("error listening on grpc port : %v", grpcPort)
+	}
+
+	go func() {
+		log.Default().Println("starting grpc server on port :", grpcPort)
+
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("error starting grpc server on port : %v", grpcPort)
+		}
+	}()
+
 	r := routes.InitRouter(app)
 
 	port := "8080"
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+// runMigrate applies the pending schema migrations or prints their status
+//
+//	main migrate up
+//	main migrate status
+func runMigrate(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: migrate up|status")
+	}
+
+	switch args[0] {
+	case "up":
+		status, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("schema is at version %d\n", status.Current)
+
+		return nil
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tDESCRIPTION\tAPPLIED AT")
+
+		for _, val := range status.Applied {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", val.Version, val.Description, val.AppliedAt)
+		}
+
+		for _, val := range status.Pending {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", val.Version, val.Description, "pending")
+		}
+
+		return w.Flush()
+	}
+
+	return fmt.Errorf("unknown migrate command %q", args[0])
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"article-tag/internal/handler"
+	"article-tag/internal/model"
+	"article-tag/internal/schema"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// runSchema compares the table with its definition and optionally applies the online changes
+//
+//	main schema diff [-apply] [-prune]
+func runSchema(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "diff" {
+		return errors.New("usage: schema diff [-apply] [-prune]")
+	}
+
+	fs := flag.NewFlagSet("schema diff", flag.ContinueOnError)
+	apply := fs.Bool("apply", false, "apply the changes which can be made with UpdateTable")
+	prune := fs.Bool("prune", false, "delete global secondary indexes which are not defined, requires -apply")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	definition := model.TableDefinition()
+
+	res, err := schemaDB.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(definition.Name),
+	})
+	if err != nil {
+		return err
+	}
+
+	drifts := schema.Diff(definition, res.Table)
+	if len(drifts) == 0 {
+		fmt.Println("table matches its definition")
+		return nil
+	}
+
+	for _, val := range drifts {
+		fmt.Println(val)
+	}
+
+	if !*apply {
+		return nil
+	}
+
+	applied, err := schema.Apply(ctx, schemaDB, definition.Name, drifts, *prune)
+	fmt.Printf("applied %d of %d changes\n", len(applied), len(drifts))
+
+	return err
+}
+
+// reconcileSchema applies the online drifts at startup, global indexes are never deleted
+// and the drifts which need a new table are only logged
+func reconcileSchema(ctx context.Context) error {
+	logger := handler.GetLogger(app)
+
+	drifts, applied, err := schema.Reconcile(ctx, schemaDB, model.TableDefinition(), false)
+	if err != nil {
+		return err
+	}
+
+	for _, val := range applied {
+		logger.Info("schema drift applied", zap.String("drift", val.String()))
+	}
+
+	for _, val := range drifts {
+		if !val.Online || val.Prune {
+			logger.Warn("schema drift not applied", zap.String("drift", val.String()))
+		}
+	}
+
+	return nil
+}
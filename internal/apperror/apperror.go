@@ -0,0 +1,98 @@
+package apperror
+
+import "errors"
+
+// Kind of the error, the handlers map it to the http status
+type Kind string
+
+const (
+	KindNotFound           Kind = "not_found"
+	KindConflict           Kind = "conflict"
+	KindValidationFailed   Kind = "validation_failed"
+	KindThrottled          Kind = "throttled"
+	KindUnavailable        Kind = "unavailable"
+	KindPreconditionFailed Kind = "precondition_failed"
+)
+
+// Codes are stable and machine readable, clients can rely on them
+const (
+	CodeTagNotFollowed     = "tag_not_followed"
+	CodeTagAlreadyFollowed = "tag_already_followed"
+	CodeTagNameMismatch    = "tag_name_mismatch"
+	CodeUndoWindowExpired  = "undo_window_expired"
+	CodeBatchTooLarge      = "batch_too_large"
+	CodeStoreThrottled     = "store_throttled"
+	CodeStoreUnavailable   = "store_unavailable"
+	CodeVersionMismatch    = "version_mismatch"
+	CodeFollowSetChanged   = "follow_set_changed"
+	CodeFollowLimitReached = "follow_limit_reached"
+	CodeTooManyTags        = "too_many_tags"
+	CodeTagNotFound        = "tag_not_found"
+	CodeTagCycle           = "tag_cycle"
+	CodeAliasNotFound      = "alias_not_found"
+	CodeAliasChain         = "alias_chain"
+)
+
+// Sentinels to match the kind of an error with errors.Is
+var (
+	ErrNotFound           = &Error{Kind: KindNotFound}
+	ErrConflict           = &Error{Kind: KindConflict}
+	ErrValidationFailed   = &Error{Kind: KindValidationFailed}
+	ErrThrottled          = &Error{Kind: KindThrottled}
+	ErrUnavailable        = &Error{Kind: KindUnavailable}
+	ErrPreconditionFailed = &Error{Kind: KindPreconditionFailed}
+)
+
+// Error is a domain error returned by the stores
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Err     error
+}
+
+// New
+func New(kind Kind, code, msg string) *Error {
+	return &Error{Kind: kind, Code: code, Message: msg}
+}
+
+// Wrap keeps the cause of the error, e.g. the dynamodb error
+func Wrap(kind Kind, code, msg string, err error) *Error {
+	return &Error{Kind: kind, Code: code, Message: msg, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+
+	if e.Message == "" {
+		return string(e.Kind)
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches an error of the same kind, and of the same code when the target has one
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	return t.Kind == e.Kind && (t.Code == "" || t.Code == e.Code)
+}
+
+// As returns the domain error of the chain
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,29 @@
+package apperror_test
+
+import (
+	"article-tag/internal/apperror"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Is(t *testing.T) {
+	cause := errors.New("conditional check failed")
+	err := fmt.Errorf("deleting tag: %w", apperror.Wrap(apperror.KindNotFound, apperror.CodeTagNotFollowed, "tag is not followed", cause))
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.ErrorIs(t, err, &apperror.Error{Kind: apperror.KindNotFound, Code: apperror.CodeTagNotFollowed})
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, apperror.ErrConflict)
+	assert.NotErrorIs(t, err, &apperror.Error{Kind: apperror.KindNotFound, Code: apperror.CodeUndoWindowExpired})
+
+	e, ok := apperror.As(err)
+	assert.True(t, ok)
+	assert.Equal(t, apperror.CodeTagNotFollowed, e.Code)
+	assert.Equal(t, "tag is not followed: conditional check failed", e.Error())
+
+	_, ok = apperror.As(cause)
+	assert.False(t, ok)
+}
@@ -0,0 +1,246 @@
+package bulk
+
+import (
+	"article-tag/internal/constant"
+	"article-tag/internal/model"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// Record types
+const (
+	RecordFollow  = "follow"
+	RecordCounter = "counter"
+)
+
+// exportHeader
+var exportHeader = []string{"type", "username", "publication", "tag_id", "tag_name", "created_at", "tag_count"}
+
+// Record is a single exported row, either a follow of a user or the counter of a tag
+type Record struct {
+	Type        string `json:"type"`
+	Username    string `json:"username,omitempty"`
+	Publication string `json:"publication"`
+	TagID       string `json:"tag_id"`
+	TagName     string `json:"tag_name"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	TagCount    int    `json:"tag_count,omitempty"`
+}
+
+// ExportOptions
+type ExportOptions struct {
+	// Segments is the number of parallel scan segments
+	Segments int
+	// Rate is the maximum number of rows read per second, zero means unlimited
+	Rate float64
+}
+
+// ExportResult
+type ExportResult struct {
+	Follows  int `json:"follows"`
+	Counters int `json:"counters"`
+}
+
+// Exporter streams the follow graph and counters of a publication
+type Exporter struct {
+	store    model.UserTagStore
+	logger   *zap.Logger
+	limiter  *rate.Limiter
+	segments int
+}
+
+// NewExporter
+func NewExporter(store model.UserTagStore, logger *zap.Logger, opts ExportOptions) *Exporter {
+	segments := opts.Segments
+	if segments <= 0 {
+		segments = constant.ExportSegments
+	}
+
+	limit := rate.Inf
+	if opts.Rate > 0 {
+		limit = rate.Limit(opts.Rate)
+	}
+
+	return &Exporter{
+		store:    store,
+		logger:   logger,
+		limiter:  rate.NewLimiter(limit, constant.ExportPageSize),
+		segments: segments,
+	}
+}
+
+// Export scans the table in parallel segments and writes every record of the publication to w.
+// The scans share one rate limiter, so that the export does not starve production capacity.
+func (e *Exporter) Export(ctx context.Context, publication, format string, w io.Writer) (*ExportResult, error) {
+	rw, err := newRecordWriter(w, format)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		result   = ExportResult{}
+		pages    = make(chan []*model.UserTag)
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	// the first error cancels the remaining segments
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	getErr := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return firstErr
+	}
+
+	for segment := 0; segment < e.segments; segment++ {
+		wg.Add(1)
+
+		go func(segment int) {
+			defer wg.Done()
+
+			err := e.store.ScanPublication(ctx, publication, segment, e.segments, func(items []*model.UserTag) error {
+				// wait for the capacity before the next page is read
+				if err := e.limiter.WaitN(ctx, len(items)); err != nil {
+					return err
+				}
+
+				select {
+				case pages <- items:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+			if err != nil {
+				e.logger.Error("error scanning segment", zap.Int("segment", segment), zap.Error(err))
+				setErr(err)
+			}
+		}(segment)
+	}
+
+	go func() {
+		wg.Wait()
+		close(pages)
+	}()
+
+	// records are written by a single goroutine
+	for items := range pages {
+		if getErr() != nil {
+			continue
+		}
+
+		for _, val := range items {
+			rec := toRecord(publication, val)
+			if rec.Type == RecordFollow {
+				result.Follows++
+			} else {
+				result.Counters++
+			}
+
+			if err := rw.Write(rec); err != nil {
+				setErr(err)
+				break
+			}
+		}
+	}
+
+	if err := rw.Flush(); err != nil && getErr() == nil {
+		return &result, err
+	}
+
+	return &result, getErr()
+}
+
+// toRecord
+func toRecord(publication string, item *model.UserTag) *Record {
+	if strings.HasPrefix(item.PK, "PUB#") {
+		return &Record{
+			Type:        RecordCounter,
+			Publication: publication,
+			TagID:       item.TagID,
+			TagName:     item.TagName,
+			TagCount:    item.TagCount,
+		}
+	}
+
+	return &Record{
+		Type:        RecordFollow,
+		Username:    item.Username,
+		Publication: item.Publication,
+		TagID:       item.TagID,
+		TagName:     item.TagName,
+		CreatedAt:   item.CreatedAt,
+	}
+}
+
+// recordWriter
+type recordWriter struct {
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+// newRecordWriter
+func newRecordWriter(w io.Writer, format string) (*recordWriter, error) {
+	switch format {
+	case FormatJSONL:
+		return &recordWriter{json: json.NewEncoder(w)}, nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportHeader); err != nil {
+			return nil, err
+		}
+
+		return &recordWriter{csv: cw}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// Write
+func (rw *recordWriter) Write(rec *Record) error {
+	if rw.json != nil {
+		return rw.json.Encode(rec)
+	}
+
+	return rw.csv.Write([]string{
+		rec.Type,
+		rec.Username,
+		rec.Publication,
+		rec.TagID,
+		rec.TagName,
+		rec.CreatedAt,
+		fmt.Sprint(rec.TagCount),
+	})
+}
+
+// Flush
+func (rw *recordWriter) Flush() error {
+	if rw.csv != nil {
+		rw.csv.Flush()
+		return rw.csv.Error()
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package bulk_test
+
+import (
+	"article-tag/internal/bulk"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Export(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name      string
+		format    string
+		mockStore func() *mocks.UserTagStore
+		want      *bulk.ExportResult
+		wantLines []string
+		wantErr   error
+	}{
+		{
+			name:   "success - jsonl",
+			format: bulk.FormatJSONL,
+			mockStore: func() *mocks.UserTagStore {
+				m := mocks.NewUserTagStore(t)
+				m.EXPECT().ScanPublication(mock.Anything, "AK", 0, 2, mock.Anything).
+					RunAndReturn(func(ctx context.Context, pub string, seg, total int, fn func([]*model.UserTag) error) error {
+						return fn([]*model.UserTag{{PK: "user1#AK", Username: "user1", Publication: "AK", TagID: "1", TagName: "tag1"}})
+					}).Once()
+				m.EXPECT().ScanPublication(mock.Anything, "AK", 1, 2, mock.Anything).
+					RunAndReturn(func(ctx context.Context, pub string, seg, total int, fn func([]*model.UserTag) error) error {
+						return fn([]*model.UserTag{{PK: "PUB#AK", TagID: "1", TagName: "tag1", TagCount: 1}})
+					}).Once()
+
+				return m
+			},
+			want: &bulk.ExportResult{Follows: 1, Counters: 1},
+			wantLines: []string{
+				`{"type":"follow","username":"user1","publication":"AK","tag_id":"1","tag_name":"tag1"}`,
+				`{"type":"counter","publication":"AK","tag_id":"1","tag_name":"tag1","tag_count":1}`,
+			},
+		},
+		{
+			name:   "success - csv",
+			format: bulk.FormatCSV,
+			mockStore: func() *mocks.UserTagStore {
+				m := mocks.NewUserTagStore(t)
+				m.EXPECT().ScanPublication(mock.Anything, "AK", mock.Anything, 2, mock.Anything).Return(nil).Twice()
+
+				return m
+			},
+			want:      &bulk.ExportResult{},
+			wantLines: []string{"type,username,publication,tag_id,tag_name,created_at,tag_count"},
+		},
+		{
+			name:   "should fail when scan returns error",
+			format: bulk.FormatJSONL,
+			mockStore: func() *mocks.UserTagStore {
+				m := mocks.NewUserTagStore(t)
+				m.EXPECT().ScanPublication(mock.Anything, "AK", mock.Anything, 2, mock.Anything).Return(errors.New("mock error")).Twice()
+
+				return m
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := bulk.NewExporter(tt.mockStore(), log, bulk.ExportOptions{Segments: 2})
+
+			out := bytes.Buffer{}
+			got, err := exporter.Export(context.TODO(), "AK", tt.format, &out)
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			assert.ElementsMatch(t, tt.wantLines, lines)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package bulk
+
+import (
+	"article-tag/internal/config"
+	"article-tag/internal/constant"
+	"article-tag/internal/i18n"
+	"article-tag/internal/model"
+	"article-tag/internal/types"
+	"article-tag/internal/validation"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// ImportOptions
+type ImportOptions struct {
+	// BatchSize is the number of rows written per BatchWriteItem call, at most 25
+	BatchSize int
+	// Rate is the maximum number of rows written per second, zero means unlimited
+	Rate float64
+}
+
+// ImportResult
+type ImportResult struct {
+	Read         int      `json:"read"`
+	Imported     int      `json:"imported"`
+	Rejected     int      `json:"rejected"`
+	Duplicates   int      `json:"duplicates"`
+	Publications []string `json:"publications"`
+}
+
+// Importer loads follows in bulk, bypassing the per user Store calls
+type Importer struct {
+	store     model.UserTagStore
+	validate  *validator.Validate
+	trans     ut.Translator
+	logger    *zap.Logger
+	limiter   *rate.Limiter
+	batchSize int
+}
+
+// NewImporter
+func NewImporter(store model.UserTagStore, logger *zap.Logger, opts ImportOptions) *Importer {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 || batchSize > constant.BatchWriteLimit {
+		batchSize = constant.BatchWriteLimit
+	}
+
+	limit := rate.Inf
+	if opts.Rate > 0 {
+		limit = rate.Limit(opts.Rate)
+	}
+
+	// reject reasons are the english validation messages of the apis
+	translator, err := i18n.New(config.String("I18N_CATALOG_DIR", ""))
+	if err != nil {
+		logger.Error("error loading translation catalogs, using the embedded ones", zap.Error(err))
+		translator, _ = i18n.New("")
+	}
+
+	return &Importer{
+		store:     store,
+		validate:  validation.New(),
+		trans:     translator.Locale(""),
+		logger:    logger,
+		limiter:   rate.NewLimiter(limit, batchSize),
+		batchSize: batchSize,
+	}
+}
+
+// pendingRow
+type pendingRow struct {
+	row  *Row
+	raw  string
+	line int
+}
+
+// Import streams the rows from r, validates them with the same rules as the store request,
+// writes the valid ones in rate limited batches and rebuilds the counters of every
+// publication touched. Invalid rows, rows over the follow limit and unprocessed rows are written to rejects.
+func (i *Importer) Import(ctx context.Context, r io.Reader, format string, rejects io.Writer) (*ImportResult, error) {
+	reader, err := newRowReader(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	rw := newRejectWriter(rejects, format)
+	defer rw.Flush()
+
+	var (
+		result       = ImportResult{}
+		publications = map[string]bool{}
+		batch        = []*pendingRow{}
+		batchKeys    = map[string]int{}
+	)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := i.limiter.WaitN(ctx, len(batch)); err != nil {
+			return err
+		}
+
+		items := []*model.UserTag{}
+		for _, val := range batch {
+			items = append(items, &model.UserTag{
+				Username:    val.row.Username,
+				Publication: val.row.Publication,
+				TagID:       val.row.TagID,
+				TagName:     val.row.TagName,
+			})
+		}
+
+		rejects, err := i.store.BatchStore(ctx, items)
+		if err != nil {
+			return err
+		}
+
+		for _, val := range rejects {
+			p := batch[batchKeys[rowKey(val.Item.Username, val.Item.Publication, val.Item.TagID)]]
+			if err := rw.Write(p.line, p.raw, val.Reason); err != nil {
+				return err
+			}
+		}
+
+		result.Imported += len(batch) - len(rejects)
+		result.Rejected += len(rejects)
+
+		batch = []*pendingRow{}
+		batchKeys = map[string]int{}
+
+		return nil
+	}
+
+	for {
+		row, raw, line, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rErr *rowError
+		if errors.As(err, &rErr) {
+			result.Read++
+			result.Rejected++
+
+			if err := rw.Write(line, raw, rErr.Error()); err != nil {
+				return &result, err
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return &result, err
+		}
+
+		result.Read++
+
+		if msg := i.validateRow(row); msg != "" {
+			result.Rejected++
+
+			if err := rw.Write(line, raw, msg); err != nil {
+				return &result, err
+			}
+
+			continue
+		}
+
+		publications[row.Publication] = true
+
+		// a batch can not contain the same key twice, the latest row wins
+		key := rowKey(row.Username, row.Publication, row.TagID)
+		if idx, ok := batchKeys[key]; ok {
+			result.Duplicates++
+			batch[idx] = &pendingRow{row: row, raw: raw, line: line}
+
+			continue
+		}
+
+		batchKeys[key] = len(batch)
+		batch = append(batch, &pendingRow{row: row, raw: raw, line: line})
+
+		if len(batch) == i.batchSize {
+			if err := flush(); err != nil {
+				return &result, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return &result, err
+	}
+
+	// counters are not maintained by the batch writes
+	for pub := range publications {
+		result.Publications = append(result.Publications, pub)
+	}
+
+	sort.Strings(result.Publications)
+
+	for _, pub := range result.Publications {
+		i.logger.Info("rebuilding tag counters", zap.String("publication", pub))
+
+		if err := i.store.RebuildCounters(ctx, pub); err != nil {
+			return &result, err
+		}
+	}
+
+	return &result, nil
+}
+
+// validateRow validates the row using the store tag request rules,
+// returns the reject reason or empty string when the row is valid
+func (i *Importer) validateRow(row *Row) string {
+	req := types.StoreTagRequest{
+		Username:    row.Username,
+		Publication: row.Publication,
+		Tags:        []types.Tag{{TagID: row.TagID, TagName: row.TagName}},
+	}
+
+	err := i.validate.Struct(req)
+	if err == nil {
+		return ""
+	}
+
+	msgs := []string{}
+	for _, v := range err.(validator.ValidationErrors) {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", validation.FieldPath(v.Namespace()), i18n.Message(i.trans, v.Field(), v.Tag(), v.Param())))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// rowKey
+func rowKey(username, publication, tagID string) string {
+	return fmt.Sprintf("%s#%s#%s", username, publication, tagID)
+}
+
+// rejectWriter writes the rejected rows along with the line number and reason
+type rejectWriter struct {
+	format string
+	csv    *csv.Writer
+	json   *json.Encoder
+}
+
+// newRejectWriter
+func newRejectWriter(w io.Writer, format string) *rejectWriter {
+	if w == nil {
+		w = io.Discard
+	}
+
+	if format == FormatJSONL {
+		return &rejectWriter{format: format, json: json.NewEncoder(w)}
+	}
+
+	return &rejectWriter{format: format, csv: csv.NewWriter(w)}
+}
+
+// Write
+func (rw *rejectWriter) Write(line int, raw, reason string) error {
+	if rw.json != nil {
+		return rw.json.Encode(map[string]interface{}{
+			"line":  line,
+			"row":   raw,
+			"error": reason,
+		})
+	}
+
+	return rw.csv.Write([]string{fmt.Sprint(line), raw, reason})
+}
+
+// Flush
+func (rw *rejectWriter) Flush() {
+	if rw.csv != nil {
+		rw.csv.Flush()
+	}
+}
@@ -0,0 +1,166 @@
+package bulk_test
+
+import (
+	"article-tag/internal/bulk"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func testSuite() *zap.Logger {
+	rawJSON := []byte(`{
+		"level": "debug",
+		"encoding": "json",
+		"outputPaths": ["stdout", "/tmp/logs"],
+		"errorOutputPaths": ["stderr"],
+		"encoderConfig": {
+		  "messageKey": "message",
+		  "levelKey": "level",
+		  "levelEncoder": "lowercase"
+		}
+	  }`)
+
+	var cfg zap.Config
+	if err := json.Unmarshal(rawJSON, &cfg); err != nil {
+		panic(err)
+	}
+
+	return zap.Must(cfg.Build())
+}
+
+func Test_Import(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name        string
+		format      string
+		input       string
+		mockStore   func() *mocks.UserTagStore
+		want        *bulk.ImportResult
+		wantRejects []string
+		wantErr     error
+	}{
+		{
+			name:   "success - csv with header",
+			format: bulk.FormatCSV,
+			input:  "username,publication,tag_id,tag_name\nuser1,AK,1,tag1\nuser2,AK,2,tag2\n",
+			mockStore: func() *mocks.UserTagStore {
+				m := mocks.NewUserTagStore(t)
+				m.EXPECT().BatchStore(mock.Anything, mock.MatchedBy(func(items []*model.UserTag) bool {
+					return len(items) == 2
+				})).Return([]*model.BatchReject{}, nil).Once()
+				m.EXPECT().RebuildCounters(mock.Anything, "AK").Return(nil).Once()
+
+				return m
+			},
+			want: &bulk.ImportResult{Read: 2, Imported: 2, Publications: []string{"AK"}},
+		},
+		{
+			name:   "invalid rows are rejected - jsonl",
+			format: bulk.FormatJSONL,
+			input: `{"username":"user1","publication":"AK","tag_id":"1","tag_name":"tag1"}
+{"username":"","publication":"AK","tag_id":"1","tag_name":"tag1"}
+{"username":"user1","publication":"XX","tag_id":"abc","tag_name":"tag1"}
+not json
+`,
+			mockStore: func() *mocks.UserTagStore {
+				m := mocks.NewUserTagStore(t)
+				m.EXPECT().BatchStore(mock.Anything, mock.Anything).Return([]*model.BatchReject{}, nil).Once()
+				m.EXPECT().RebuildCounters(mock.Anything, "AK").Return(nil).Once()
+
+				return m
+			},
+			want:        &bulk.ImportResult{Read: 4, Imported: 1, Rejected: 3, Publications: []string{"AK"}},
+			wantRejects: []string{"username: username is required", "publication: publication must be one of", "tags[0].tag_id: tag_id must have a numeric format", "not json"},
+		},
+		{
+			name:   "duplicate rows in a batch are written once",
+			format: bulk.FormatCSV,
+			input:  "user1,AK,1,tag1\nuser1,AK,1,tag1 renamed\n",
+			mockStore: func() *mocks.UserTagStore {
+				m := mocks.NewUserTagStore(t)
+				m.EXPECT().BatchStore(mock.Anything, mock.MatchedBy(func(items []*model.UserTag) bool {
+					return len(items) == 1 && items[0].TagName == "tag1 renamed"
+				})).Return([]*model.BatchReject{}, nil).Once()
+				m.EXPECT().RebuildCounters(mock.Anything, "AK").Return(nil).Once()
+
+				return m
+			},
+			want: &bulk.ImportResult{Read: 2, Imported: 1, Duplicates: 1, Publications: []string{"AK"}},
+		},
+		{
+			name:   "unprocessed items are rejected",
+			format: bulk.FormatCSV,
+			input:  "user1,AK,1,tag1\nuser2,AK,2,tag2\n",
+			mockStore: func() *mocks.UserTagStore {
+				m := mocks.NewUserTagStore(t)
+				m.EXPECT().BatchStore(mock.Anything, mock.Anything).Return([]*model.BatchReject{
+					{Item: &model.UserTag{Username: "user2", Publication: "AK", TagID: "2"}, Reason: "unprocessed after retries"},
+				}, nil).Once()
+				m.EXPECT().RebuildCounters(mock.Anything, "AK").Return(nil).Once()
+
+				return m
+			},
+			want:        &bulk.ImportResult{Read: 2, Imported: 1, Rejected: 1, Publications: []string{"AK"}},
+			wantRejects: []string{"unprocessed after retries"},
+		},
+		{
+			name:   "rows over the follow limit are rejected",
+			format: bulk.FormatCSV,
+			input:  "user1,AK,1,tag1\nuser1,AK,2,tag2\n",
+			mockStore: func() *mocks.UserTagStore {
+				m := mocks.NewUserTagStore(t)
+				m.EXPECT().BatchStore(mock.Anything, mock.Anything).Return([]*model.BatchReject{
+					{Item: &model.UserTag{Username: "user1", Publication: "AK", TagID: "2"}, Reason: "follow limit of 1 is reached"},
+				}, nil).Once()
+				m.EXPECT().RebuildCounters(mock.Anything, "AK").Return(nil).Once()
+
+				return m
+			},
+			want:        &bulk.ImportResult{Read: 2, Imported: 1, Rejected: 1, Publications: []string{"AK"}},
+			wantRejects: []string{"follow limit of 1 is reached"},
+		},
+		{
+			name:   "should fail when batch write returns error",
+			format: bulk.FormatCSV,
+			input:  "user1,AK,1,tag1\n",
+			mockStore: func() *mocks.UserTagStore {
+				m := mocks.NewUserTagStore(t)
+				m.EXPECT().BatchStore(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+
+				return m
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			importer := bulk.NewImporter(tt.mockStore(), log, bulk.ImportOptions{})
+
+			rejects := bytes.Buffer{}
+			got, err := importer.Import(context.TODO(), strings.NewReader(tt.input), tt.format, &rejects)
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+
+			for _, v := range tt.wantRejects {
+				assert.Contains(t, rejects.String(), v)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package bulk
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// csvHeader
+var csvHeader = []string{"username", "publication", "tag_id", "tag_name"}
+
+// Row represents a single follow of a tag by a user
+type Row struct {
+	Username    string `json:"username"`
+	Publication string `json:"publication"`
+	TagID       string `json:"tag_id"`
+	TagName     string `json:"tag_name"`
+}
+
+// DetectFormat returns the format from the file extension, defaults to csv
+func DetectFormat(filename string) string {
+	if strings.HasSuffix(filename, ".jsonl") || strings.HasSuffix(filename, ".ndjson") {
+		return FormatJSONL
+	}
+
+	return FormatCSV
+}
+
+// rowReader reads the rows one at a time along with the raw line and its
+// line number, so that the rejected rows can be written to the reject file
+type rowReader interface {
+	Next() (*Row, string, int, error)
+}
+
+// newRowReader
+func newRowReader(r io.Reader, format string) (rowReader, error) {
+	switch format {
+	case FormatCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		cr.TrimLeadingSpace = true
+
+		return &csvReader{r: cr}, nil
+	case FormatJSONL:
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		return &jsonlReader{s: sc}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// csvReader
+type csvReader struct {
+	r *csv.Reader
+}
+
+// Next
+func (c *csvReader) Next() (*Row, string, int, error) {
+	record, err := c.r.Read()
+	if err != nil {
+		if pErr, ok := err.(*csv.ParseError); ok {
+			return nil, "", pErr.StartLine, &rowError{msg: pErr.Err.Error()}
+		}
+
+		return nil, "", 0, err
+	}
+
+	line, _ := c.r.FieldPos(0)
+	raw := strings.Join(record, ",")
+
+	// skip the optional header row
+	if line == 1 && strings.EqualFold(raw, strings.Join(csvHeader, ",")) {
+		return c.Next()
+	}
+
+	if len(record) != len(csvHeader) {
+		return nil, raw, line, &rowError{msg: fmt.Sprintf("expected %d columns, got %d", len(csvHeader), len(record))}
+	}
+
+	return &Row{
+		Username:    record[0],
+		Publication: record[1],
+		TagID:       record[2],
+		TagName:     record[3],
+	}, raw, line, nil
+}
+
+// jsonlReader
+type jsonlReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+// Next
+func (j *jsonlReader) Next() (*Row, string, int, error) {
+	for j.s.Scan() {
+		j.line++
+		raw := strings.TrimSpace(j.s.Text())
+
+		// skip blank lines
+		if raw == "" {
+			continue
+		}
+
+		var row Row
+		if err := json.Unmarshal([]byte(raw), &row); err != nil {
+			return nil, raw, j.line, &rowError{msg: err.Error()}
+		}
+
+		return &row, raw, j.line, nil
+	}
+
+	if err := j.s.Err(); err != nil {
+		return nil, "", 0, err
+	}
+
+	return nil, "", 0, io.EOF
+}
+
+// rowError is returned for the rows which can not be parsed,
+// the import continues with the next row
+type rowError struct {
+	msg string
+}
+
+func (e *rowError) Error() string {
+	return e.msg
+}
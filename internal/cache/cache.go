@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"article-tag/internal/config"
+	"article-tag/internal/constant"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Backends
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+	BackendNone   = "none"
+)
+
+// Cache stores values by key until their time to live has passed
+type Cache interface {
+	// Get returns false when the key is missing or expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// New reads the backend from the environment:
+//
+//	CACHE_BACKEND memory (default), redis or none which disables the cache
+//	CACHE_SIZE the number of entries of the memory backend
+//	REDIS_ADDR the address of the redis backend, any server speaking the redis protocol
+func New() (Cache, error) {
+	backend := strings.ToLower(config.String("CACHE_BACKEND", BackendMemory))
+
+	switch backend {
+	case BackendMemory:
+		return NewLRU(config.Int("CACHE_SIZE", constant.CacheSize)), nil
+	case BackendRedis:
+		return NewRedis(redis.NewClient(&redis.Options{Addr: config.String("REDIS_ADDR", constant.RedisAddr)})), nil
+	case BackendNone:
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("unknown cache backend %q", backend)
+}
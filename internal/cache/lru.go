@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU is an in process cache, the least recently used entry is evicted once the size is reached
+type LRU struct {
+	size int
+	now  func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRU
+func NewLRU(size int) *LRU {
+	return &LRU{
+		size:    size,
+		now:     time.Now,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// Get
+func (c *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	e := el.Value.(*entry)
+
+	// expired entries are removed when they are read
+	if !c.now().Before(e.expiresAt) {
+		c.remove(el)
+
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(el)
+
+	return e.value, true, nil
+}
+
+// Set
+func (c *LRU) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry)
+		e.value = value
+		e.expiresAt = c.now().Add(ttl)
+		c.order.MoveToFront(el)
+
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: c.now().Add(ttl)})
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+// Delete
+func (c *LRU) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.entries[key]; ok {
+			c.remove(el)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of entries, including the expired ones not read yet
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRU) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*entry).key)
+}
@@ -0,0 +1,61 @@
+package cache_test
+
+import (
+	"article-tag/internal/cache"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LRU(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("the least recently used entry is evicted", func(t *testing.T) {
+		c := cache.NewLRU(2)
+
+		assert.Nil(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+		assert.Nil(t, c.Set(ctx, "b", []byte("2"), time.Minute))
+
+		// reading a makes b the least recently used
+		_, ok, _ := c.Get(ctx, "a")
+		assert.True(t, ok)
+
+		assert.Nil(t, c.Set(ctx, "c", []byte("3"), time.Minute))
+
+		_, ok, _ = c.Get(ctx, "b")
+		assert.False(t, ok)
+
+		got, ok, _ := c.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("1"), got)
+		assert.Equal(t, 2, c.Len())
+	})
+
+	t.Run("expired entries are missing", func(t *testing.T) {
+		c := cache.NewLRU(2)
+
+		assert.Nil(t, c.Set(ctx, "a", []byte("1"), 10*time.Millisecond))
+		time.Sleep(20 * time.Millisecond)
+
+		_, ok, _ := c.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("set replaces the value and delete removes it", func(t *testing.T) {
+		c := cache.NewLRU(2)
+
+		assert.Nil(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+		assert.Nil(t, c.Set(ctx, "a", []byte("2"), time.Minute))
+
+		got, _, _ := c.Get(ctx, "a")
+		assert.Equal(t, []byte("2"), got)
+
+		assert.Nil(t, c.Delete(ctx, "a", "missing"))
+
+		_, ok, _ := c.Get(ctx, "a")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,59 @@
+package cache
+
+import "sync"
+
+// Metrics counts the hits and misses of the cached reads by name
+type Metrics struct {
+	mu     sync.Mutex
+	counts map[string]*Counts
+}
+
+// Counts of a cached read
+type Counts struct {
+	Hits   int64
+	Misses int64
+}
+
+// NewMetrics
+func NewMetrics() *Metrics {
+	return &Metrics{counts: map[string]*Counts{}}
+}
+
+// Hit
+func (m *Metrics) Hit(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.get(name).Hits++
+}
+
+// Miss
+func (m *Metrics) Miss(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.get(name).Misses++
+}
+
+// Snapshot returns a copy of the counts
+func (m *Metrics) Snapshot() map[string]Counts {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := map[string]Counts{}
+	for name, val := range m.counts {
+		res[name] = *val
+	}
+
+	return res
+}
+
+func (m *Metrics) get(name string) *Counts {
+	c, ok := m.counts[name]
+	if !ok {
+		c = &Counts{}
+		m.counts[name] = c
+	}
+
+	return c
+}
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a cache shared by the instances of the service
+type Redis struct {
+	client redis.Cmdable
+}
+
+// NewRedis
+func NewRedis(client redis.Cmdable) *Redis {
+	return &Redis{client: client}
+}
+
+// Get
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+// Set
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Delete
+func (c *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return c.client.Del(ctx, keys...).Err()
+}
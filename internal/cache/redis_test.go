@@ -0,0 +1,51 @@
+package cache_test
+
+import (
+	"article-tag/internal/cache"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Redis(t *testing.T) {
+	ctx := context.Background()
+
+	// miniredis is a local stand-in speaking the redis protocol
+	server := miniredis.RunT(t)
+	c := cache.NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+
+	_, ok, err := c.Get(ctx, "a")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	assert.Nil(t, c.Set(ctx, "b", []byte("2"), time.Minute))
+
+	got, ok, err := c.Get(ctx, "a")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), got)
+
+	// the time to live is kept by the server
+	server.FastForward(2 * time.Minute)
+
+	_, ok, _ = c.Get(ctx, "a")
+	assert.False(t, ok)
+
+	assert.Nil(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	assert.Nil(t, c.Delete(ctx, "a"))
+	assert.Nil(t, c.Delete(ctx))
+
+	_, ok, _ = c.Get(ctx, "a")
+	assert.False(t, ok)
+
+	// errors of the server are returned
+	server.Close()
+
+	_, _, err = c.Get(ctx, "a")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// String returns the value of the environment variable or the default when it is not set
+func String(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+
+	return def
+}
+
+// Int returns the environment variable as int, the default is returned when it is not set or invalid
+func Int(key string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// Float returns the environment variable as float64, the default is returned when it is not set or invalid
+func Float(key string, def float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// Bool returns the environment variable as bool, the default is returned when it is not set or invalid
+func Bool(key string, def bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// Duration returns the environment variable as duration (e.g. 24h), the default is returned when it is not set or invalid
+func Duration(key string, def time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+
+	return v
+}
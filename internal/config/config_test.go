@@ -0,0 +1,29 @@
+package config_test
+
+import (
+	"article-tag/internal/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Config(t *testing.T) {
+	t.Setenv("TEST_STRING", "value")
+	t.Setenv("TEST_INT", "10")
+	t.Setenv("TEST_FLOAT", "1.5")
+	t.Setenv("TEST_BOOL", "true")
+	t.Setenv("TEST_DURATION", "2h")
+	t.Setenv("TEST_INVALID", "invalid")
+
+	assert.Equal(t, "value", config.String("TEST_STRING", "default"))
+	assert.Equal(t, "default", config.String("TEST_MISSING", "default"))
+	assert.Equal(t, 10, config.Int("TEST_INT", 1))
+	assert.Equal(t, 1, config.Int("TEST_INVALID", 1))
+	assert.Equal(t, 1.5, config.Float("TEST_FLOAT", 1))
+	assert.Equal(t, 1.0, config.Float("TEST_INVALID", 1))
+	assert.Equal(t, true, config.Bool("TEST_BOOL", false))
+	assert.Equal(t, false, config.Bool("TEST_INVALID", false))
+	assert.Equal(t, 2*time.Hour, config.Duration("TEST_DURATION", time.Hour))
+	assert.Equal(t, time.Hour, config.Duration("TEST_INVALID", time.Hour))
+}
@@ -1,10 +1,89 @@
 package constant
 
+import "time"
+
 // PopularTagLimit
 const (
 	PopularTagLimit = 2
 )
 
+// BatchWrite
+const (
+	BatchWriteLimit   = 25
+	BatchWriteRetries = 3
+)
+
+// BatchGetLimit is the maximum number of keys of a BatchGetItem call
+const BatchGetLimit = 100
+
+// TransactWriteLimit is the maximum number of actions of a TransactWriteItems call
+const TransactWriteLimit = 100
+
+// Export
+const (
+	ExportSegments = 4
+	ExportRate     = 500
+	ExportPageSize = 100
+)
+
+// Audit
+const (
+	AuditRetention  = 90 * 24 * time.Hour
+	AuditQueryLimit = 100
+)
+
+// Capacity, default provisioned throughput when the table is not billed per request
+const (
+	TableReadCapacity     = 10
+	TableWriteCapacity    = 5
+	TagIndexReadCapacity  = 5
+	TagIndexWriteCapacity = 5
+)
+
+// Resilience, retries and circuit breaker of the dynamodb calls
+const (
+	RetryMaxAttempts = 4
+	RetryBaseDelay   = 50 * time.Millisecond
+	RetryMaxDelay    = time.Second
+	CallTimeout      = 3 * time.Second
+	BreakerThreshold = 5
+	BreakerCooldown  = 10 * time.Second
+)
+
+// Cache, read-through cache of the popular rankings and followed tags
+const (
+	CacheSize = 10000
+	CacheTTL  = time.Minute
+	RedisAddr = "localhost:6379"
+)
+
+// Leaderboard, top tags materialized per publication. A user left with fewer than
+// LeaderboardMinimum popular tags once the followed ones are excluded gets the full ranking.
+const (
+	LeaderboardSize     = 50
+	LeaderboardInterval = time.Minute
+	LeaderboardMinimum  = 10
+)
+
+// Cache-Control of the read routes, the responses carry an etag to revalidate them
+const (
+	CacheControlTags    = "private, no-cache"
+	CacheControlPopular = "public, max-age=60"
+)
+
+// FollowLimit is the default maximum number of tags a user follows per publication
+const FollowLimit = 500
+
+// GRPCPort is the default port of the grpc api
+const GRPCPort = "9090"
+
+// UndoWindow is the default duration an unfollow can be undone
+const UndoWindow = 24 * time.Hour
+
+// TTLAttribute is the time to live attribute of the table, items are
+// removed by dynamodb once the epoch time stored in it has passed
+const TTLAttribute = "TTL"
+
 // AllowdedPublications
 var AllowdedPublications = []string{
 	"AK",
@@ -36,7 +36,10 @@ func InitDB() (*dynamodb.Client, error) {
 		log.Fatalf("Cannot load the AWS configs: %s", err)
 	}
 
-	dynamoDBClient := dynamodb.NewFromConfig(awsCfg, func(o *dynamodb.Options) {})
+	// retries are made by the resilience client, the sdk retryer would multiply them
+	dynamoDBClient := dynamodb.NewFromConfig(awsCfg, func(o *dynamodb.Options) {
+		o.Retryer = aws.NopRetryer{}
+	})
 
 	return dynamoDBClient, nil
 }
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/i18n"
+	"article-tag/internal/response"
+	"article-tag/internal/validation"
+	"context"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Error of a resolver, the code and the invalid parameters are returned as extensions of the graphql error
+type Error struct {
+	Message       string
+	Code          string
+	InvalidParams []response.InvalidParam
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions of the graphql error
+func (e *Error) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{"code": e.Code}
+	if len(e.InvalidParams) > 0 {
+		ext["invalid_params"] = e.InvalidParams
+	}
+
+	return ext
+}
+
+// storeError keeps the code of a domain error, any other error is an internal error with the message
+func storeError(err error, msg string) error {
+	if e, ok := apperror.As(err); ok && e.Code != "" {
+		return &Error{Message: e.Message, Code: e.Code}
+	}
+
+	return &Error{Message: msg, Code: "internal_error"}
+}
+
+// invalidRequest lists the invalid parameters with their message in the language of the request
+func (s *Server) invalidRequest(ctx context.Context, err error) error {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return &Error{Message: "invalid request", Code: "invalid_request"}
+	}
+
+	trans := s.translator.Locale(requestFrom(ctx).acceptLanguage)
+
+	params := []response.InvalidParam{}
+	for _, v := range errs {
+		params = append(params, response.InvalidParam{
+			Name:   validation.FieldPath(v.Namespace()),
+			Reason: i18n.Message(trans, v.Field(), v.Tag(), v.Param()),
+			Rule:   v.Tag(),
+			Field:  v.StructField(),
+		})
+	}
+
+	return &Error{Message: "one or more request parameters are invalid", Code: "invalid_request", InvalidParams: params}
+}
@@ -0,0 +1,155 @@
+package graph
+
+import (
+	"article-tag/internal/config"
+	"article-tag/internal/constant"
+	"article-tag/internal/i18n"
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"article-tag/internal/types"
+	"article-tag/internal/validation"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+// Path of the graphql endpoint
+const Path = "/graphql"
+
+// loaderWait is the window in which the keys of a request are batched
+const loaderWait = 2 * time.Millisecond
+
+//go:embed schema.graphql
+var schemaString string
+
+// Server resolves the graphql schema with the models of the rest api
+type Server struct {
+	model      model.Models
+	validate   *validator.Validate
+	translator *i18n.Translator
+	logger     *zap.Logger
+	schema     *graphql.Schema
+}
+
+// counterKey of the counter loader
+type counterKey struct {
+	publication string
+	tagID       string
+}
+
+type contextKey int
+
+const (
+	loadersKey contextKey = iota
+	requestKey
+)
+
+// loaders of a request
+type loaders struct {
+	counters *Loader[counterKey, *model.UserTag]
+}
+
+// requestInfo of the http request, used by the audit trail and the translation of validation messages
+type requestInfo struct {
+	acceptLanguage string
+	requestID      string
+	sourceIP       string
+}
+
+// New
+func New(models *model.Models, logger *zap.Logger) *Server {
+	// validation messages, catalogs of I18N_CATALOG_DIR are loaded over the embedded ones
+	translator, err := i18n.New(config.String("I18N_CATALOG_DIR", ""))
+	if err != nil {
+		logger.Error("error loading translation catalogs, using the embedded ones", zap.Error(err))
+		translator, _ = i18n.New("")
+	}
+
+	s := &Server{
+		model:      *models,
+		validate:   validation.New(),
+		translator: translator,
+		logger:     logger,
+	}
+
+	s.schema = graphql.MustParseSchema(schemaString, &resolver{s: s})
+
+	return s
+}
+
+// Handler executes the graphql request of the body, every request gets its own loaders
+func (s *Server) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.GraphQLRequest
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			s.logger.Error("error decoding graphql request body", zap.Error(err))
+			response.BadRequest(w, "invalid request", nil)
+
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), loadersKey, s.newLoaders())
+		ctx = context.WithValue(ctx, requestKey, requestInfo{
+			acceptLanguage: r.Header.Get("Accept-Language"),
+			requestID:      middleware.GetReqID(r.Context()),
+			sourceIP:       r.RemoteAddr,
+		})
+
+		res := s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// newLoaders of a request
+func (s *Server) newLoaders() *loaders {
+	return &loaders{
+		counters: NewLoader(s.counters, loaderWait, constant.BatchGetLimit),
+	}
+}
+
+// counters fetches the counters of the keys, with one call per publication
+func (s *Server) counters(ctx context.Context, keys []counterKey) (map[counterKey]*model.UserTag, error) {
+	tagIDs := map[string][]string{}
+	for _, val := range keys {
+		tagIDs[val.publication] = append(tagIDs[val.publication], val.tagID)
+	}
+
+	res := map[counterKey]*model.UserTag{}
+	for publication, ids := range tagIDs {
+		counters, err := s.model.Tag.GetCounters(ctx, publication, ids)
+		if err != nil {
+			s.logger.Error("error fetching tag counters from db", zap.Error(err), zap.String("publication", publication))
+
+			return nil, err
+		}
+
+		for _, val := range counters {
+			res[counterKey{publication: publication, tagID: val.TagID}] = val
+		}
+	}
+
+	return res, nil
+}
+
+// loadersFrom the context of the request
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+}
+
+// requestFrom the context of the request
+func requestFrom(ctx context.Context) requestInfo {
+	info, _ := ctx.Value(requestKey).(requestInfo)
+
+	return info
+}
@@ -0,0 +1,229 @@
+package graph_test
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/graph"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type graphResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// execute posts the query to the graphql handler
+func execute(t *testing.T, m model.Models, query string, variables map[string]interface{}, acceptLanguage string) graphResponse {
+	body, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+
+	req := httptest.NewRequest(http.MethodPost, graph.Path, bytes.NewReader(body))
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+
+	rr := httptest.NewRecorder()
+	graph.New(&m, zap.NewNop()).Handler().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var res graphResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	return res
+}
+
+func auditStoreMock(t *testing.T) *mocks.AuditStore {
+	auditStoreMock := mocks.NewAuditStore(t)
+	auditStoreMock.EXPECT().Record(mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return auditStoreMock
+}
+
+func Test_Query(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		mockDB     func() model.Models
+		want       string
+		wantErrors []string
+	}{
+		{
+			name:  "follower counts of the followed tags are fetched with a single batch",
+			query: `{ publication(code: "AK") { user(username: "john") { username tags(order: "tagname") { id name followerCount } } } }`,
+			mockDB: func() model.Models {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Get(mock.Anything, "john", "AK", "tagname").Return([]*model.UserTag{
+					{TagID: "1", TagName: "cricket"},
+					{TagID: "2", TagName: "football"},
+					{TagID: "3", TagName: "golf"},
+				}, nil).Once()
+				tagStoreMock.EXPECT().GetCounters(mock.Anything, "AK", mock.MatchedBy(func(ids []string) bool {
+					return assert.ElementsMatch(t, []string{"1", "2", "3"}, ids)
+				})).Return([]*model.UserTag{{TagID: "1", TagCount: 5}, {TagID: "2", TagCount: 2}}, nil).Once()
+
+				return model.Models{Tag: tagStoreMock}
+			},
+			want: `{"publication":{"user":{"username":"john","tags":[
+				{"id":"1","name":"cricket","followerCount":5},
+				{"id":"2","name":"football","followerCount":2},
+				{"id":"3","name":"golf","followerCount":0}]}}}`,
+		},
+		{
+			name:  "tags of several publications are fetched with a call per publication",
+			query: `{ ak: publication(code: "AK") { tag(id: "1") { name followerCount } } rs: publication(code: "RS") { tag(id: "2") { name } } }`,
+			mockDB: func() model.Models {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().GetCounters(mock.Anything, "AK", []string{"1"}).
+					Return([]*model.UserTag{{TagID: "1", TagName: "cricket", TagCount: 5}}, nil).Once()
+				tagStoreMock.EXPECT().GetCounters(mock.Anything, "RS", []string{"2"}).Return([]*model.UserTag{}, nil).Once()
+
+				return model.Models{Tag: tagStoreMock}
+			},
+			want: `{"ak":{"tag":{"name":"cricket","followerCount":5}},"rs":{"tag":null}}`,
+		},
+		{
+			name:  "popular tags",
+			query: `{ publication(code: "AK") { popularTags(username: "john") } }`,
+			mockDB: func() model.Models {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().GetPopularTags(mock.Anything, "john", "AK").Return([]string{"cricket"}, nil).Once()
+
+				return model.Models{Tag: tagStoreMock}
+			},
+			want: `{"publication":{"popularTags":["cricket"]}}`,
+		},
+		{
+			name:  "should fail when publication is invalid",
+			query: `{ publication(code: "XX") { code } }`,
+			mockDB: func() model.Models {
+				return model.Models{}
+			},
+			want:       `null`,
+			wantErrors: []string{"invalid_request"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := execute(t, tt.mockDB(), tt.query, nil, "")
+
+			got, _ := json.Marshal(res.Data)
+			assert.JSONEq(t, tt.want, string(got))
+
+			codes := []string{}
+			for _, val := range res.Errors {
+				codes = append(codes, val.Extensions["code"].(string))
+			}
+
+			if tt.wantErrors == nil {
+				assert.Empty(t, codes)
+			} else {
+				assert.Equal(t, tt.wantErrors, codes)
+			}
+		})
+	}
+}
+
+func Test_Mutation(t *testing.T) {
+	follow := `mutation($tags: [TagInput!]!) { follow(publication: "AK", username: "john", tags: $tags) { username } }`
+	unfollow := `mutation($tags: [TagInput!]!) { unfollow(publication: "AK", username: "john", tags: $tags) { username } }`
+
+	tests := []struct {
+		name           string
+		query          string
+		tags           []map[string]string
+		acceptLanguage string
+		mockDB         func() model.Models
+		wantCode       string
+		wantParams     map[string]string
+	}{
+		{
+			name:  "follow",
+			query: follow,
+			tags:  []map[string]string{{"id": "1", "name": "cricket"}},
+			mockDB: func() model.Models {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "john", "AK", "cricket", "1", false).Return(&model.UserTag{TagID: "1", TagName: "cricket"}, nil).Once()
+
+				auditMock := mocks.NewAuditStore(t)
+				auditMock.EXPECT().Record(mock.Anything, mock.MatchedBy(func(r *model.AuditRecord) bool {
+					return r.Action == model.AuditActionFollow && r.TagID == "1"
+				})).Return(nil).Once()
+
+				return model.Models{Tag: tagStoreMock, Audit: auditMock}
+			},
+		},
+		{
+			name:  "should fail with the rules of the rest api",
+			query: follow,
+			tags:  []map[string]string{{"id": "abc", "name": "cricket"}},
+			mockDB: func() model.Models {
+				return model.Models{}
+			},
+			wantCode:   "invalid_request",
+			wantParams: map[string]string{"tags[0].tag_id": "tag_id must have a numeric format"},
+		},
+		{
+			name:           "should translate the invalid parameters",
+			query:          follow,
+			tags:           []map[string]string{},
+			acceptLanguage: "es",
+			mockDB: func() model.Models {
+				return model.Models{}
+			},
+			wantCode:   "invalid_request",
+			wantParams: map[string]string{"tags": "se requiere al menos una etiqueta"},
+		},
+		{
+			name:  "unfollow should fail with the code of the store error",
+			query: unfollow,
+			tags:  []map[string]string{{"id": "1", "name": "cricket"}},
+			mockDB: func() model.Models {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Delete(mock.Anything, "john", "AK", "1", "cricket").
+					Return(apperror.New(apperror.KindNotFound, apperror.CodeTagNotFollowed, "tag is not followed")).Once()
+
+				return model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}
+			},
+			wantCode: apperror.CodeTagNotFollowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := execute(t, tt.mockDB(), tt.query, map[string]interface{}{"tags": tt.tags}, tt.acceptLanguage)
+
+			if tt.wantCode == "" {
+				assert.Empty(t, res.Errors)
+				return
+			}
+
+			assert.Len(t, res.Errors, 1)
+			assert.Equal(t, tt.wantCode, res.Errors[0].Extensions["code"])
+
+			if tt.wantParams != nil {
+				params := map[string]string{}
+				for _, val := range res.Errors[0].Extensions["invalid_params"].([]interface{}) {
+					p := val.(map[string]interface{})
+					params[p["name"].(string)] = p["reason"].(string)
+				}
+
+				assert.Equal(t, tt.wantParams, params)
+			}
+		})
+	}
+}
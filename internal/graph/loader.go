@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Thunk returns the value of a key once its batch is fetched
+type Thunk[V any] func() (V, error)
+
+// Loader batches the keys loaded within the wait window into a single fetch, at most maxBatch keys per fetch.
+// Values are cached for the lifetime of the loader, which is a single request.
+type Loader[K comparable, V any] struct {
+	fetch    func(ctx context.Context, keys []K) (map[K]V, error)
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*result[V]
+	batch *batch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	results []*result[V]
+	once    sync.Once
+}
+
+// NewLoader, keys missing from the fetched values resolve to the zero value
+func NewLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error), wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    map[K]*result[V]{},
+	}
+}
+
+// Load queues the key and returns without waiting, so that the keys of a list are fetched together
+func (l *Loader[K, V]) Load(ctx context.Context, key K) Thunk[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if r, ok := l.cache[key]; ok {
+		return r.thunk
+	}
+
+	r := &result[V]{done: make(chan struct{})}
+	l.cache[key] = r
+
+	if l.batch == nil {
+		b := &batch[K, V]{}
+		l.batch = b
+
+		time.AfterFunc(l.wait, func() { l.dispatch(ctx, b) })
+	}
+
+	l.batch.keys = append(l.batch.keys, key)
+	l.batch.results = append(l.batch.results, r)
+
+	// a full batch is fetched right away
+	if len(l.batch.keys) == l.maxBatch {
+		b := l.batch
+		l.batch = nil
+
+		go l.dispatch(ctx, b)
+	}
+
+	return r.thunk
+}
+
+// dispatch fetches the batch once, either when the wait window ends or when it is full
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.batch == b {
+			l.batch = nil
+		}
+		l.mu.Unlock()
+
+		values, err := l.fetch(ctx, b.keys)
+
+		for i, key := range b.keys {
+			b.results[i].value, b.results[i].err = values[key], err
+			close(b.results[i].done)
+		}
+	})
+}
+
+func (r *result[V]) thunk() (V, error) {
+	<-r.done
+
+	return r.value, r.err
+}
@@ -0,0 +1,82 @@
+package graph_test
+
+import (
+	"article-tag/internal/graph"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Loader(t *testing.T) {
+	t.Run("keys loaded within the window are fetched together", func(t *testing.T) {
+		var mu sync.Mutex
+		batches := [][]int{}
+
+		l := graph.NewLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+			mu.Lock()
+			batches = append(batches, keys)
+			mu.Unlock()
+
+			return map[int]string{1: "one", 2: "two"}, nil
+		}, 10*time.Millisecond, 100)
+
+		one := l.Load(context.Background(), 1)
+		two := l.Load(context.Background(), 2)
+		three := l.Load(context.Background(), 3)
+		again := l.Load(context.Background(), 1)
+
+		got, err := one()
+		assert.Nil(t, err)
+		assert.Equal(t, "one", got)
+
+		got, _ = two()
+		assert.Equal(t, "two", got)
+
+		got, _ = three()
+		assert.Equal(t, "", got)
+
+		got, _ = again()
+		assert.Equal(t, "one", got)
+
+		assert.Equal(t, [][]int{{1, 2, 3}}, batches)
+	})
+
+	t.Run("a full batch is fetched right away", func(t *testing.T) {
+		var mu sync.Mutex
+		sizes := []int{}
+
+		l := graph.NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+			mu.Lock()
+			sizes = append(sizes, len(keys))
+			mu.Unlock()
+
+			return nil, nil
+		}, time.Hour, 2)
+
+		l.Load(context.Background(), 1)
+		two := l.Load(context.Background(), 2)
+
+		_, err := two()
+		assert.Nil(t, err)
+		assert.Equal(t, []int{2}, sizes)
+	})
+
+	t.Run("the error of the fetch is returned for every key", func(t *testing.T) {
+		l := graph.NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+			return nil, errors.New("db error")
+		}, time.Millisecond, 100)
+
+		one := l.Load(context.Background(), 1)
+		two := l.Load(context.Background(), 2)
+
+		_, err := one()
+		assert.EqualError(t, err, "db error")
+
+		_, err = two()
+		assert.EqualError(t, err, "db error")
+	})
+}
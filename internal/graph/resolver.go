@@ -0,0 +1,300 @@
+package graph
+
+import (
+	"article-tag/internal/model"
+	"article-tag/internal/types"
+	"context"
+
+	"github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// resolver of the query and mutation roots
+type resolver struct {
+	s *Server
+}
+
+type publicationResolver struct {
+	s    *Server
+	code string
+}
+
+type userResolver struct {
+	s           *Server
+	username    string
+	publication string
+}
+
+type tagResolver struct {
+	s           *Server
+	id          string
+	name        string
+	publication string
+	counter     Thunk[*model.UserTag]
+}
+
+type tagInput struct {
+	ID          graphql.ID
+	Name        string
+	Descendants *bool
+}
+
+type followArgs struct {
+	Publication string
+	Username    string
+	Tags        []tagInput
+}
+
+// Publication
+func (r *resolver) Publication(ctx context.Context, args struct{ Code string }) (*publicationResolver, error) {
+	req := types.GetPopularTagRequest{Publication: args.Code}
+
+	// validate request
+	if err := r.s.validate.Struct(req); err != nil {
+		return nil, r.s.invalidRequest(ctx, err)
+	}
+
+	return &publicationResolver{s: r.s, code: req.Publication}, nil
+}
+
+// Follow
+func (r *resolver) Follow(ctx context.Context, args followArgs) (*userResolver, error) {
+	req := types.StoreTagRequest{
+		Username:    args.Username,
+		Publication: args.Publication,
+		Tags:        tags(args.Tags),
+	}
+
+	// validate request
+	if err := r.s.validate.Struct(req); err != nil {
+		return nil, r.s.invalidRequest(ctx, err)
+	}
+
+	// store follow tag
+	for _, val := range req.Tags {
+		// an alias is followed as its canonical tag
+		followed, err := r.s.model.Tag.Store(ctx, req.Username, req.Publication, val.TagName, val.TagID, val.Descendants)
+		if err != nil {
+			r.s.logger.Error("error while storing item", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return nil, storeError(err, "error while storing user tag")
+		}
+
+		r.s.audit(ctx, req.Username, model.AuditActionFollow, req.Username, req.Publication,
+			types.Tag{TagID: followed.TagID, TagName: followed.TagName}, "")
+	}
+
+	return &userResolver{s: r.s, username: req.Username, publication: req.Publication}, nil
+}
+
+// Unfollow
+func (r *resolver) Unfollow(ctx context.Context, args followArgs) (*userResolver, error) {
+	req := types.DeleteTagRequest{
+		Username:    args.Username,
+		Publication: args.Publication,
+		Tags:        tags(args.Tags),
+	}
+
+	// validate request
+	if err := r.s.validate.Struct(req); err != nil {
+		return nil, r.s.invalidRequest(ctx, err)
+	}
+
+	for _, val := range req.Tags {
+		// delete user tag
+		err := r.s.model.Tag.Delete(ctx, req.Username, req.Publication, val.TagID, val.TagName)
+		if err != nil {
+			r.s.logger.Error("error deleting user tags", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return nil, storeError(err, "error while deleting user followed tags")
+		}
+
+		r.s.audit(ctx, req.Username, model.AuditActionUnfollow, req.Username, req.Publication, val, "")
+	}
+
+	return &userResolver{s: r.s, username: req.Username, publication: req.Publication}, nil
+}
+
+// Code
+func (p *publicationResolver) Code() string {
+	return p.code
+}
+
+// User
+func (p *publicationResolver) User(ctx context.Context, args struct{ Username string }) (*userResolver, error) {
+	req := types.GetTagRequest{Username: args.Username, Publication: p.code}
+
+	// validate request
+	if err := p.s.validate.Struct(req); err != nil {
+		return nil, p.s.invalidRequest(ctx, err)
+	}
+
+	return &userResolver{s: p.s, username: req.Username, publication: req.Publication}, nil
+}
+
+// Tag is resolved from the popular tag counter, through the counter loader
+func (p *publicationResolver) Tag(ctx context.Context, args struct{ ID graphql.ID }) (*tagResolver, error) {
+	req := types.PublicationTagRequest{Publication: p.code, TagID: string(args.ID)}
+
+	// validate request
+	if err := p.s.validate.Struct(req); err != nil {
+		return nil, p.s.invalidRequest(ctx, err)
+	}
+
+	counter := loadersFrom(ctx).counters.Load(ctx, counterKey{publication: req.Publication, tagID: req.TagID})
+
+	m, err := counter()
+	if err != nil {
+		return nil, storeError(err, "error while fetching tag")
+	}
+
+	if m == nil {
+		return nil, nil
+	}
+
+	return &tagResolver{s: p.s, id: m.TagID, name: m.TagName, publication: req.Publication, counter: counter}, nil
+}
+
+// PopularTags
+func (p *publicationResolver) PopularTags(ctx context.Context, args struct{ Username *string }) ([]string, error) {
+	username := ""
+	if args.Username != nil {
+		username = *args.Username
+	}
+
+	// fetch popularTags
+	tagNames, err := p.s.model.Tag.GetPopularTags(ctx, username, p.code)
+	if err != nil {
+		p.s.logger.Error("error fetching popular tags from db", zap.Error(err), zap.String("publication", p.code))
+
+		return nil, storeError(err, "error while fetching popular tags")
+	}
+
+	if tagNames == nil {
+		tagNames = []string{}
+	}
+
+	return tagNames, nil
+}
+
+// Username
+func (u *userResolver) Username() string {
+	return u.username
+}
+
+// Publication
+func (u *userResolver) Publication() *publicationResolver {
+	return &publicationResolver{s: u.s, code: u.publication}
+}
+
+// Tags queues the counters of every followed tag, so that they are fetched with a single batch
+func (u *userResolver) Tags(ctx context.Context, args struct{ Order *string }) ([]*tagResolver, error) {
+	req := types.GetTagRequest{Username: u.username, Publication: u.publication}
+	if args.Order != nil {
+		req.Order = *args.Order
+	}
+
+	// validate request
+	if err := u.s.validate.Struct(req); err != nil {
+		return nil, u.s.invalidRequest(ctx, err)
+	}
+
+	// fetch tags using username and publication
+	userTags, err := u.s.model.Tag.Get(ctx, req.Username, req.Publication, req.Order)
+	if err != nil {
+		u.s.logger.Error("error fetching user tags from db", zap.Error(err), zap.Field{Key: "request",
+			Type: zapcore.ReflectType, Interface: req})
+
+		return nil, storeError(err, "error while fetching user tags")
+	}
+
+	counters := loadersFrom(ctx).counters
+
+	res := []*tagResolver{}
+	for _, val := range userTags {
+		res = append(res, &tagResolver{
+			s:           u.s,
+			id:          val.TagID,
+			name:        val.TagName,
+			publication: req.Publication,
+			counter:     counters.Load(ctx, counterKey{publication: req.Publication, tagID: val.TagID}),
+		})
+	}
+
+	return res, nil
+}
+
+// ID
+func (t *tagResolver) ID() graphql.ID {
+	return graphql.ID(t.id)
+}
+
+// Name
+func (t *tagResolver) Name() string {
+	return t.name
+}
+
+// Publication
+func (t *tagResolver) Publication() *publicationResolver {
+	return &publicationResolver{s: t.s, code: t.publication}
+}
+
+// FollowerCount of the popular tag counter, 0 when the tag has no counter
+func (t *tagResolver) FollowerCount() (int32, error) {
+	m, err := t.counter()
+	if err != nil {
+		return 0, storeError(err, "error while fetching tag counters")
+	}
+
+	if m == nil {
+		return 0, nil
+	}
+
+	return int32(m.TagCount), nil
+}
+
+// tags converts the tags of a mutation to the validated type
+func tags(in []tagInput) []types.Tag {
+	if len(in) == 0 {
+		return nil
+	}
+
+	res := []types.Tag{}
+	for _, val := range in {
+		res = append(res, types.Tag{TagID: string(val.ID), TagName: val.Name, Descendants: val.Descendants != nil && *val.Descendants})
+	}
+
+	return res
+}
+
+// newAuditRecord of the mutation
+func newAuditRecord(ctx context.Context, actor, action string) *model.AuditRecord {
+	info := requestFrom(ctx)
+
+	return &model.AuditRecord{
+		Actor:     actor,
+		Action:    action,
+		RequestID: info.requestID,
+		SourceIP:  info.sourceIP,
+	}
+}
+
+// audit records the action of the actor, username and tag are empty when the action does not concern them.
+// Failures are only logged so that the audit trail never blocks the mutation.
+func (s *Server) audit(ctx context.Context, actor, action, username, publication string, tag types.Tag, detail string) {
+	record := newAuditRecord(ctx, actor, action)
+	record.Username = username
+	record.Publication = publication
+	record.TagID = tag.TagID
+	record.TagName = tag.TagName
+	record.Detail = detail
+
+	err := s.model.Audit.Record(ctx, record)
+	if err != nil {
+		s.logger.Error("error recording audit", zap.Error(err), zap.Field{Key: "record",
+			Type: zapcore.ReflectType, Interface: record})
+	}
+}
@@ -0,0 +1,85 @@
+package grpcapi
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/i18n"
+	"article-tag/internal/validation"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// errorDomain of the error details
+const errorDomain = "article-tag"
+
+// retryDelay clients are asked to wait when the store is overloaded, as the Retry-After header of the rest api
+const retryDelay = time.Second
+
+// kindCode
+var kindCode = map[apperror.Kind]codes.Code{
+	apperror.KindNotFound:           codes.NotFound,
+	apperror.KindConflict:           codes.FailedPrecondition,
+	apperror.KindValidationFailed:   codes.InvalidArgument,
+	apperror.KindThrottled:          codes.ResourceExhausted,
+	apperror.KindUnavailable:        codes.Unavailable,
+	apperror.KindPreconditionFailed: codes.FailedPrecondition,
+}
+
+// storeError maps a domain error to its status, the error code is set as the reason of the error info.
+// Any other error is an internal error with the message.
+func storeError(err error, msg string) error {
+	e, ok := apperror.As(err)
+	if !ok {
+		return status.Error(codes.Internal, msg)
+	}
+
+	code, ok := kindCode[e.Kind]
+	if !ok {
+		return status.Error(codes.Internal, msg)
+	}
+
+	st, _ := status.New(code, e.Message).WithDetails(&errdetails.ErrorInfo{Reason: e.Code, Domain: errorDomain})
+
+	if code == codes.ResourceExhausted || code == codes.Unavailable {
+		st, _ = st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryDelay)})
+	}
+
+	return st.Err()
+}
+
+// invalidArgument lists the invalid fields with their message in the language of the accept-language metadata
+func (s *Server) invalidArgument(ctx context.Context, err error) error {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return status.Error(codes.InvalidArgument, "invalid request")
+	}
+
+	acceptLanguage := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if val := md.Get("accept-language"); len(val) > 0 {
+			acceptLanguage = val[0]
+		}
+	}
+
+	trans := s.translator.Locale(acceptLanguage)
+
+	bad := errdetails.BadRequest{}
+	for _, v := range errs {
+		bad.FieldViolations = append(bad.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       validation.FieldPath(v.Namespace()),
+			Description: i18n.Message(trans, v.Field(), v.Tag(), v.Param()),
+		})
+	}
+
+	st, _ := status.New(codes.InvalidArgument, "one or more request parameters are invalid").
+		WithDetails(&bad, &errdetails.ErrorInfo{Reason: "invalid_request", Domain: errorDomain})
+
+	return st.Err()
+}
@@ -0,0 +1,36 @@
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// logUnary logs every call with its status code, as LogRequest of the rest api
+func logUnary(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+
+		logger.Debug("grpc call", zap.String("method", info.FullMethod), zap.Any("request", req),
+			zap.String("code", status.Code(err).String()), zap.Duration("duration", time.Since(start)))
+
+		return res, err
+	}
+}
+
+// logStream logs every stream with its status code
+func logStream(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+
+		logger.Debug("grpc stream", zap.String("method", info.FullMethod),
+			zap.String("code", status.Code(err).String()), zap.Duration("duration", time.Since(start)))
+
+		return err
+	}
+}
@@ -0,0 +1,232 @@
+package grpcapi
+
+import (
+	"article-tag/internal/config"
+	"article-tag/internal/grpcapi/tagpb"
+	"article-tag/internal/i18n"
+	"article-tag/internal/model"
+	"article-tag/internal/types"
+	"article-tag/internal/validation"
+	"context"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Server implements the tag service with the models of the rest api
+type Server struct {
+	tagpb.UnimplementedTagServiceServer
+
+	model      model.Models
+	validate   *validator.Validate
+	translator *i18n.Translator
+	logger     *zap.Logger
+}
+
+// New
+func New(models *model.Models, logger *zap.Logger) *Server {
+	// validation messages, catalogs of I18N_CATALOG_DIR are loaded over the embedded ones
+	translator, err := i18n.New(config.String("I18N_CATALOG_DIR", ""))
+	if err != nil {
+		logger.Error("error loading translation catalogs, using the embedded ones", zap.Error(err))
+		translator, _ = i18n.New("")
+	}
+
+	return &Server{
+		model:      *models,
+		validate:   validation.New(),
+		translator: translator,
+		logger:     logger,
+	}
+}
+
+// NewGRPCServer returns a grpc server serving the tag service
+func NewGRPCServer(s *Server) *grpc.Server {
+	g := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logUnary(s.logger)),
+		grpc.ChainStreamInterceptor(logStream(s.logger)),
+	)
+
+	tagpb.RegisterTagServiceServer(g, s)
+
+	return g
+}
+
+// Store
+func (s *Server) Store(ctx context.Context, in *tagpb.StoreRequest) (*tagpb.StoreResponse, error) {
+	req := types.StoreTagRequest{
+		Username:    in.GetUsername(),
+		Publication: in.GetPublication(),
+		Tags:        tags(in.GetTags()),
+	}
+
+	// validate request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, s.invalidArgument(ctx, err)
+	}
+
+	// store follow tag
+	for _, val := range req.Tags {
+		// an alias is followed as its canonical tag
+		followed, err := s.model.Tag.Store(ctx, req.Username, req.Publication, val.TagName, val.TagID, val.Descendants)
+		if err != nil {
+			s.logger.Error("error while storing item", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return nil, storeError(err, "error while storing user tag")
+		}
+
+		s.audit(ctx, req.Username, model.AuditActionFollow, req.Username, req.Publication,
+			types.Tag{TagID: followed.TagID, TagName: followed.TagName}, "")
+	}
+
+	return &tagpb.StoreResponse{}, nil
+}
+
+// Get
+func (s *Server) Get(ctx context.Context, in *tagpb.GetRequest) (*tagpb.GetResponse, error) {
+	userTags, err := s.get(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tagpb.GetResponse{Tags: userTags}, nil
+}
+
+// Delete
+func (s *Server) Delete(ctx context.Context, in *tagpb.DeleteRequest) (*tagpb.DeleteResponse, error) {
+	req := types.DeleteTagRequest{
+		Username:    in.GetUsername(),
+		Publication: in.GetPublication(),
+		Tags:        tags(in.GetTags()),
+	}
+
+	// validate request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, s.invalidArgument(ctx, err)
+	}
+
+	for _, val := range req.Tags {
+		// delete user tag
+		err := s.model.Tag.Delete(ctx, req.Username, req.Publication, val.TagID, val.TagName)
+		if err != nil {
+			s.logger.Error("error deleting user tags", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return nil, storeError(err, "error while deleting user followed tags")
+		}
+
+		s.audit(ctx, req.Username, model.AuditActionUnfollow, req.Username, req.Publication, val, "")
+	}
+
+	return &tagpb.DeleteResponse{}, nil
+}
+
+// GetPopularTags
+func (s *Server) GetPopularTags(ctx context.Context, in *tagpb.GetPopularTagsRequest) (*tagpb.GetPopularTagsResponse, error) {
+	req := types.GetPopularTagRequest{
+		Username:    in.GetUsername(),
+		Publication: in.GetPublication(),
+	}
+
+	// validate request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, s.invalidArgument(ctx, err)
+	}
+
+	// fetch popularTags
+	tagNames, err := s.model.Tag.GetPopularTags(ctx, req.Username, req.Publication)
+	if err != nil {
+		s.logger.Error("error fetching popular tags from db", zap.Error(err), zap.Field{Key: "request",
+			Type: zapcore.ReflectType, Interface: req})
+
+		return nil, storeError(err, "error while fetching popular tags")
+	}
+
+	return &tagpb.GetPopularTagsResponse{TagNames: tagNames}, nil
+}
+
+// get validates the request and fetches the followed tags, used by Get and GetStream
+func (s *Server) get(ctx context.Context, in *tagpb.GetRequest) ([]*tagpb.Tag, error) {
+	req := types.GetTagRequest{
+		Username:    in.GetUsername(),
+		Publication: in.GetPublication(),
+		Order:       in.GetOrder(),
+	}
+
+	// validate request
+	if err := s.validate.Struct(req); err != nil {
+		return nil, s.invalidArgument(ctx, err)
+	}
+
+	// fetch tags using username and publication
+	userTags, err := s.model.Tag.Get(ctx, req.Username, req.Publication, req.Order)
+	if err != nil {
+		s.logger.Error("error fetching user tags from db", zap.Error(err), zap.Field{Key: "request",
+			Type: zapcore.ReflectType, Interface: req})
+
+		return nil, storeError(err, "error while fetching user tags")
+	}
+
+	res := []*tagpb.Tag{}
+	for _, val := range userTags {
+		res = append(res, &tagpb.Tag{TagId: val.TagID, TagName: val.TagName, Descendants: val.IncludeDescendants})
+	}
+
+	return res, nil
+}
+
+// tags converts the tags of a request to the validated type
+func tags(in []*tagpb.Tag) []types.Tag {
+	if len(in) == 0 {
+		return nil
+	}
+
+	res := []types.Tag{}
+	for _, val := range in {
+		res = append(res, types.Tag{TagID: val.GetTagId(), TagName: val.GetTagName(), Descendants: val.GetDescendants()})
+	}
+
+	return res
+}
+
+// newAuditRecord of the call, the request id is read from the x-request-id metadata
+func newAuditRecord(ctx context.Context, actor, action string) *model.AuditRecord {
+	record := model.AuditRecord{
+		Actor:  actor,
+		Action: action,
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if val := md.Get("x-request-id"); len(val) > 0 {
+			record.RequestID = val[0]
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		record.SourceIP = p.Addr.String()
+	}
+
+	return &record
+}
+
+// audit records the action of the actor, username and tag are empty when the action does not concern them.
+// Failures are only logged so that the audit trail never blocks the call.
+func (s *Server) audit(ctx context.Context, actor, action, username, publication string, tag types.Tag, detail string) {
+	record := newAuditRecord(ctx, actor, action)
+	record.Username = username
+	record.Publication = publication
+	record.TagID = tag.TagID
+	record.TagName = tag.TagName
+	record.Detail = detail
+
+	err := s.model.Audit.Record(ctx, record)
+	if err != nil {
+		s.logger.Error("error recording audit", zap.Error(err), zap.Field{Key: "record",
+			Type: zapcore.ReflectType, Interface: record})
+	}
+}
@@ -0,0 +1,260 @@
+package grpcapi_test
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/grpcapi"
+	"article-tag/internal/grpcapi/tagpb"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"article-tag/internal/resilience"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newClient serves the models on an in memory listener and returns a client of it
+func newClient(t *testing.T, m model.Models) tagpb.TagServiceClient {
+	lis := bufconn.Listen(1024 * 1024)
+
+	s := grpcapi.NewGRPCServer(grpcapi.New(&m, zap.NewNop()))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	return tagpb.NewTagServiceClient(conn)
+}
+
+func auditStoreMock(t *testing.T) *mocks.AuditStore {
+	auditStoreMock := mocks.NewAuditStore(t)
+	auditStoreMock.EXPECT().Record(mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return auditStoreMock
+}
+
+func Test_Store(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            *tagpb.StoreRequest
+		acceptLanguage string
+		mockDB         func() model.Models
+		wantCode       codes.Code
+		wantViolations map[string]string
+	}{
+		{
+			name: "success",
+			req:  &tagpb.StoreRequest{Username: "john", Publication: "AK", Tags: []*tagpb.Tag{{TagId: "1", TagName: "cricket"}}},
+			mockDB: func() model.Models {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "john", "AK", "cricket", "1", false).Return(&model.UserTag{TagID: "1", TagName: "cricket"}, nil).Once()
+
+				return model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "success - descendants are followed too",
+			req:  &tagpb.StoreRequest{Username: "john", Publication: "AK", Tags: []*tagpb.Tag{{TagId: "1", TagName: "cricket", Descendants: true}}},
+			mockDB: func() model.Models {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "john", "AK", "cricket", "1", true).Return(&model.UserTag{TagID: "1", TagName: "cricket", IncludeDescendants: true}, nil).Once()
+
+				return model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "should fail with the rules of the rest api",
+			req:  &tagpb.StoreRequest{Username: "john", Publication: "XX", Tags: []*tagpb.Tag{{TagId: "abc", TagName: "cricket"}}},
+			mockDB: func() model.Models {
+				return model.Models{}
+			},
+			wantCode: codes.InvalidArgument,
+			wantViolations: map[string]string{
+				"publication":    "publication must be one of RS AK BC ST",
+				"tags[0].tag_id": "tag_id must have a numeric format",
+			},
+		},
+		{
+			name:           "should translate the violations",
+			req:            &tagpb.StoreRequest{Username: "john", Publication: "AK"},
+			acceptLanguage: "es",
+			mockDB: func() model.Models {
+				return model.Models{}
+			},
+			wantCode:       codes.InvalidArgument,
+			wantViolations: map[string]string{"tags": "se requiere al menos una etiqueta"},
+		},
+		{
+			name: "should fail with resource exhausted when the store is throttled",
+			req:  &tagpb.StoreRequest{Username: "john", Publication: "AK", Tags: []*tagpb.Tag{{TagId: "1", TagName: "cricket"}}},
+			mockDB: func() model.Models {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, resilience.ErrThrottled).Once()
+
+				return model.Models{Tag: tagStoreMock}
+			},
+			wantCode: codes.ResourceExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClient(t, tt.mockDB())
+
+			ctx := context.Background()
+			if tt.acceptLanguage != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "accept-language", tt.acceptLanguage)
+			}
+
+			_, err := client.Store(ctx, tt.req)
+			st := status.Convert(err)
+
+			assert.Equal(t, tt.wantCode, st.Code())
+
+			if tt.wantViolations != nil {
+				got := map[string]string{}
+				for _, val := range st.Details() {
+					if bad, ok := val.(*errdetails.BadRequest); ok {
+						for _, v := range bad.GetFieldViolations() {
+							got[v.GetField()] = v.GetDescription()
+						}
+					}
+				}
+
+				assert.Equal(t, tt.wantViolations, got)
+			}
+
+			if tt.wantCode == codes.ResourceExhausted {
+				assert.Contains(t, detailTypes(st), "RetryInfo")
+			}
+		})
+	}
+}
+
+func Test_Delete(t *testing.T) {
+	tagStoreMock := mocks.NewUserTagStore(t)
+	tagStoreMock.EXPECT().Delete(mock.Anything, "john", "AK", "1", "cricket").
+		Return(apperror.New(apperror.KindNotFound, apperror.CodeTagNotFollowed, "tag is not followed")).Once()
+
+	client := newClient(t, model.Models{Tag: tagStoreMock})
+
+	_, err := client.Delete(context.Background(), &tagpb.DeleteRequest{
+		Username: "john", Publication: "AK", Tags: []*tagpb.Tag{{TagId: "1", TagName: "cricket"}},
+	})
+	st := status.Convert(err)
+
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "tag is not followed", st.Message())
+
+	reason := ""
+	for _, val := range st.Details() {
+		if info, ok := val.(*errdetails.ErrorInfo); ok {
+			reason = info.GetReason()
+		}
+	}
+
+	assert.Equal(t, apperror.CodeTagNotFollowed, reason)
+}
+
+func Test_GetPopularTags(t *testing.T) {
+	tagStoreMock := mocks.NewUserTagStore(t)
+	tagStoreMock.EXPECT().GetPopularTags(mock.Anything, "john", "AK").Return([]string{"cricket", "football"}, nil).Once()
+	tagStoreMock.EXPECT().GetPopularTags(mock.Anything, "", "AK").Return(nil, errors.New("db error")).Once()
+
+	client := newClient(t, model.Models{Tag: tagStoreMock})
+
+	res, err := client.GetPopularTags(context.Background(), &tagpb.GetPopularTagsRequest{Username: "john", Publication: "AK"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"cricket", "football"}, res.GetTagNames())
+
+	_, err = client.GetPopularTags(context.Background(), &tagpb.GetPopularTagsRequest{Publication: "AK"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "error while fetching popular tags", status.Convert(err).Message())
+}
+
+func Test_StoreStream(t *testing.T) {
+	tagStoreMock := mocks.NewUserTagStore(t)
+	tagStoreMock.EXPECT().Store(mock.Anything, "john", "AK", "cricket", "1", false).Return(&model.UserTag{TagID: "1", TagName: "cricket"}, nil).Once()
+	tagStoreMock.EXPECT().Store(mock.Anything, "jane", "AK", "football", "2", false).Return(&model.UserTag{TagID: "2", TagName: "football"}, nil).Once()
+
+	client := newClient(t, model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)})
+
+	stream, err := client.StoreStream(context.Background())
+	assert.Nil(t, err)
+
+	reqs := []*tagpb.StoreRequest{
+		{Username: "john", Publication: "AK", Tags: []*tagpb.Tag{{TagId: "1", TagName: "cricket"}}},
+		{Username: "", Publication: "AK", Tags: []*tagpb.Tag{{TagId: "1", TagName: "cricket"}}},
+		{Username: "jane", Publication: "AK", Tags: []*tagpb.Tag{{TagId: "2", TagName: "football"}}},
+	}
+	for _, val := range reqs {
+		assert.Nil(t, stream.Send(val))
+	}
+
+	res, err := stream.CloseAndRecv()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), res.GetProcessed())
+	assert.Len(t, res.GetFailed(), 1)
+	assert.Equal(t, int64(1), res.GetFailed()[0].GetIndex())
+	assert.Equal(t, codes.InvalidArgument.String(), res.GetFailed()[0].GetCode())
+}
+
+func Test_GetStream(t *testing.T) {
+	tagStoreMock := mocks.NewUserTagStore(t)
+	tagStoreMock.EXPECT().Get(mock.Anything, "john", "AK", "tagname").Return([]*model.UserTag{
+		{TagID: "1", TagName: "cricket", IncludeDescendants: true},
+		{TagID: "2", TagName: "football"},
+	}, nil).Once()
+
+	client := newClient(t, model.Models{Tag: tagStoreMock})
+
+	stream, err := client.GetStream(context.Background(), &tagpb.GetRequest{Username: "john", Publication: "AK", Order: "tagname"})
+	assert.Nil(t, err)
+
+	got := []string{}
+	for {
+		tag, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		assert.Nil(t, err)
+		got = append(got, fmt.Sprintf("%s:%s:%t", tag.GetTagId(), tag.GetTagName(), tag.GetDescendants()))
+	}
+
+	assert.Equal(t, []string{"1:cricket:true", "2:football:false"}, got)
+}
+
+// detailTypes returns the message names of the details of the status
+func detailTypes(st *status.Status) []string {
+	names := []string{}
+	for _, val := range st.Proto().GetDetails() {
+		name := val.GetTypeUrl()
+		names = append(names, name[len("type.googleapis.com/google.rpc."):])
+	}
+
+	return names
+}
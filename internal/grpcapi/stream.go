@@ -0,0 +1,80 @@
+package grpcapi
+
+import (
+	"article-tag/internal/grpcapi/tagpb"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc/status"
+)
+
+// StoreStream stores every request of the stream, a failed request does not stop the stream
+func (s *Server) StoreStream(stream tagpb.TagService_StoreStreamServer) error {
+	res := tagpb.BulkResponse{}
+
+	for {
+		in, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&res)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.Store(stream.Context(), in); err != nil {
+			res.Failed = append(res.Failed, failure(res.Processed, err))
+		}
+
+		res.Processed++
+	}
+}
+
+// DeleteStream deletes the tags of every request of the stream, a failed request does not stop the stream
+func (s *Server) DeleteStream(stream tagpb.TagService_DeleteStreamServer) error {
+	res := tagpb.BulkResponse{}
+
+	for {
+		in, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&res)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.Delete(stream.Context(), in); err != nil {
+			res.Failed = append(res.Failed, failure(res.Processed, err))
+		}
+
+		res.Processed++
+	}
+}
+
+// GetStream sends the followed tags one by one
+func (s *Server) GetStream(in *tagpb.GetRequest, stream tagpb.TagService_GetStreamServer) error {
+	userTags, err := s.get(stream.Context(), in)
+	if err != nil {
+		return err
+	}
+
+	for _, val := range userTags {
+		if err := stream.Send(val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// failure of the request of the stream at index
+func failure(index int64, err error) *tagpb.BulkFailure {
+	st := status.Convert(err)
+
+	return &tagpb.BulkFailure{
+		Index:   index,
+		Code:    st.Code().String(),
+		Message: st.Message(),
+	}
+}
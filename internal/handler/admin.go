@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"article-tag/internal/bulk"
+	"article-tag/internal/constant"
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"article-tag/internal/types"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+)
+
+// exportContentType
+var exportContentType = map[string]string{
+	bulk.FormatJSONL: "application/x-ndjson",
+	bulk.FormatCSV:   "text/csv",
+}
+
+func (app *Application) Export() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		publication := chi.URLParam(r, "publication")
+		if err := app.validate.Var(publication, "required,oneof=RS AK ST BC"); err != nil {
+			response.BadRequest(w, "", []response.InvalidParam{app.invalidParam(r, "publication", "Publication", "oneof", "RS AK ST BC")})
+
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = bulk.FormatJSONL
+		}
+
+		contentType, ok := exportContentType[format]
+		if !ok {
+			response.BadRequest(w, "invalid format, should be either jsonl or csv", nil)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+
+		// records are streamed, an error after this point can only be logged
+		exporter := bulk.NewExporter(app.model.Tag, app.logger, bulk.ExportOptions{Rate: constant.ExportRate})
+
+		result, err := exporter.Export(ctx, publication, format, w)
+		if err != nil {
+			app.logger.Error("error exporting publication", zap.Error(err), zap.String("publication", publication))
+
+			return
+		}
+
+		app.logger.Info("export finished", zap.String("publication", publication), zap.Any("result", result))
+
+		app.audit(r, auditActorAdmin, model.AuditActionExport, "", publication, types.Tag{},
+			fmt.Sprintf("exported %d follows and %d counters", result.Follows, result.Counters))
+	}
+}
+
+// CacheStats returns the hits and misses of the cached reads since the start of the instance
+func (app *Application) CacheStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := types.CacheStatsResponse{Caches: map[string]types.CacheStats{}}
+
+		if app.model.CacheMetrics != nil {
+			res.Enabled = true
+
+			for name, val := range app.model.CacheMetrics.Snapshot() {
+				stats := types.CacheStats{Hits: val.Hits, Misses: val.Misses}
+				if total := val.Hits + val.Misses; total > 0 {
+					stats.HitRatio = float64(val.Hits) / float64(total)
+				}
+
+				res.Caches[name] = stats
+			}
+		}
+
+		response.Success(w, res, "")
+	}
+}
+
+// FollowLimit returns the follows of the user and the limit in effect for the user
+func (app *Application) FollowLimit() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		req := types.FollowLimitRequest{Username: chi.URLParam(r, "username"), Publication: chi.URLParam(r, "publication")}
+
+		// validate request
+		err := app.validate.Struct(req)
+		if err != nil {
+			response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+			return
+		}
+
+		res, err := app.followLimit(ctx, req.Username, req.Publication)
+		if err != nil {
+			app.logger.Error("error fetching follow limit from db", zap.Error(err), zap.String("username", req.Username))
+			response.Error(w, err, "error while fetching follow limit")
+
+			return
+		}
+
+		response.Success(w, res, "")
+	}
+}
+
+// SetFollowLimit overrides the limit of the publication for the user, a limit of 0 removes the override
+func (app *Application) SetFollowLimit() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req types.FollowLimitRequest
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			app.logger.Error("error decoding follow limit request body", zap.Error(err))
+			response.BadRequest(w, "invalid request", nil)
+
+			return
+		}
+
+		// fetch params from urlParams
+		req.Username = chi.URLParam(r, "username")
+		req.Publication = chi.URLParam(r, "publication")
+
+		// validate request
+		err = app.validate.Struct(req)
+		if err != nil {
+			response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+			return
+		}
+
+		err = app.model.Tag.SetFollowLimit(ctx, req.Username, req.Publication, req.Limit)
+		if err != nil {
+			app.logger.Error("error updating follow limit", zap.Error(err), zap.String("username", req.Username))
+			response.Error(w, err, "error while updating follow limit")
+
+			return
+		}
+
+		app.audit(r, auditActorAdmin, model.AuditActionLimit, req.Username, req.Publication, types.Tag{},
+			fmt.Sprintf("follow limit set to %d", req.Limit))
+
+		res, err := app.followLimit(ctx, req.Username, req.Publication)
+		if err != nil {
+			app.logger.Error("error fetching follow limit from db", zap.Error(err), zap.String("username", req.Username))
+			response.Error(w, err, "error while fetching follow limit")
+
+			return
+		}
+
+		response.Success(w, res, "")
+	}
+}
+
+// followLimit of the user, the override of the user or the limit of the publication
+func (app *Application) followLimit(ctx context.Context, username, publication string) (types.FollowLimitResponse, error) {
+	meta, err := app.model.Tag.GetMeta(ctx, username, publication)
+	if err != nil {
+		return types.FollowLimitResponse{}, err
+	}
+
+	res := types.FollowLimitResponse{
+		Username:    username,
+		Publication: publication,
+		Follows:     meta.FollowCount,
+		Limit:       meta.FollowLimit,
+		Overridden:  meta.FollowLimit > 0,
+	}
+
+	if !res.Overridden {
+		res.Limit = app.model.Tag.DefaultFollowLimit(publication)
+	}
+
+	return res, nil
+}
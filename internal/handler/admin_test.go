@@ -0,0 +1,293 @@
+package handler_test
+
+import (
+	"article-tag/internal/cache"
+	"article-tag/internal/handler"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Export(t *testing.T) {
+	log := testSuite()
+
+	type args struct {
+		urlParams   map[string]string
+		queryParams map[string]string
+	}
+
+	tests := []struct {
+		name            string
+		args            args
+		mockDB          func() *handler.Application
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name: "success",
+			args: args{
+				urlParams: map[string]string{"publication": "AK"},
+			},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().ScanPublication(mock.Anything, "AK", 0, mock.Anything, mock.Anything).
+					RunAndReturn(func(ctx context.Context, pub string, seg, total int, fn func([]*model.UserTag) error) error {
+						return fn([]*model.UserTag{{PK: "user1#AK", Username: "user1", Publication: "AK", TagID: "1", TagName: "tag1"}})
+					}).Once()
+				tagStoreMock.EXPECT().ScanPublication(mock.Anything, "AK", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+				m := model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}
+
+				return handler.New(nil, &m, log)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody:        `{"type":"follow","username":"user1","publication":"AK","tag_id":"1","tag_name":"tag1"}` + "\n",
+		},
+		{
+			name: "should fail when invalid publication is passed",
+			args: args{
+				urlParams: map[string]string{"publication": "XX"},
+			},
+			mockDB: func() *handler.Application {
+				m := model.Models{}
+
+				return handler.New(nil, &m, log)
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: "application/json",
+		},
+		{
+			name: "should fail when invalid format is passed",
+			args: args{
+				urlParams:   map[string]string{"publication": "AK"},
+				queryParams: map[string]string{"format": "xml"},
+			},
+			mockDB: func() *handler.Application {
+				m := model.Models{}
+
+				return handler.New(nil, &m, log)
+			},
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/admin/publications/export", nil)
+			r = setURLParams(r, tt.args.urlParams)
+			r = setQueryParams(r, tt.args.queryParams)
+
+			app.Export().ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantContentType, w.Header().Get("Content-Type"))
+
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func Test_CacheStats(t *testing.T) {
+	log := testSuite()
+
+	metrics := cache.NewMetrics()
+	metrics.Hit(model.CachePopular)
+	metrics.Hit(model.CachePopular)
+	metrics.Hit(model.CachePopular)
+	metrics.Miss(model.CachePopular)
+
+	tests := []struct {
+		name     string
+		models   model.Models
+		wantData map[string]interface{}
+	}{
+		{
+			name:   "success",
+			models: model.Models{CacheMetrics: metrics},
+			wantData: map[string]interface{}{
+				"enabled": true,
+				"caches": map[string]interface{}{
+					"popular": map[string]interface{}{"hits": float64(3), "misses": float64(1), "hit_ratio": 0.75},
+				},
+			},
+		},
+		{
+			name:     "cache disabled",
+			models:   model.Models{},
+			wantData: map[string]interface{}{"enabled": false, "caches": map[string]interface{}{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := handler.New(nil, &tt.models, log)
+
+			got, gotErr := callEndpoint(t, nil, app.CacheStats(), nil, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, http.StatusOK, got.Status)
+			assert.Equal(t, tt.wantData, got.Data)
+		})
+	}
+}
+
+func Test_FollowLimit(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+	}{
+		{
+			name:      "success",
+			urlParams: map[string]string{"username": "Test", "publication": "AK"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().GetMeta(mock.Anything, "Test", "AK").Return(&model.UserMeta{FollowCount: 12}, nil).Once()
+				tagStoreMock.EXPECT().DefaultFollowLimit("AK").Return(500).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"username": "Test", "publication": "AK", "follows": float64(12), "limit": float64(500), "overridden": false,
+			}},
+		},
+		{
+			name:      "success - limit is overridden",
+			urlParams: map[string]string{"username": "Test", "publication": "AK"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().GetMeta(mock.Anything, "Test", "AK").Return(&model.UserMeta{FollowCount: 12, FollowLimit: 1000}, nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"username": "Test", "publication": "AK", "follows": float64(12), "limit": float64(1000), "overridden": true,
+			}},
+		},
+		{
+			name:      "should fail when invalid publication is passed",
+			urlParams: map[string]string{"username": "Test", "publication": "XX"},
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, nil, app.FollowLimit(), tt.urlParams, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+
+			if tt.wantRespBody.Code != "" {
+				assert.Equal(t, tt.wantRespBody.Code, got.Code)
+			}
+
+			if tt.wantRespBody.Data != nil {
+				assert.Equal(t, tt.wantRespBody.Data, got.Data)
+			}
+		})
+	}
+}
+
+func Test_SetFollowLimit(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		req          []byte
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+	}{
+		{
+			name: "success",
+			req:  []byte(`{"limit":1000}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().SetFollowLimit(mock.Anything, "Test", "AK", 1000).Return(nil).Once()
+				tagStoreMock.EXPECT().GetMeta(mock.Anything, "Test", "AK").Return(&model.UserMeta{FollowCount: 12, FollowLimit: 1000}, nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"username": "Test", "publication": "AK", "follows": float64(12), "limit": float64(1000), "overridden": true,
+			}},
+		},
+		{
+			name: "success - override is removed",
+			req:  []byte(`{"limit":0}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().SetFollowLimit(mock.Anything, "Test", "AK", 0).Return(nil).Once()
+				tagStoreMock.EXPECT().GetMeta(mock.Anything, "Test", "AK").Return(&model.UserMeta{FollowCount: 12}, nil).Once()
+				tagStoreMock.EXPECT().DefaultFollowLimit("AK").Return(500).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"username": "Test", "publication": "AK", "follows": float64(12), "limit": float64(500), "overridden": false,
+			}},
+		},
+		{
+			name: "should fail when limit is negative",
+			req:  []byte(`{"limit":-1}`),
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+		},
+		{
+			name: "should fail when received error in setFollowLimit call",
+			req:  []byte(`{"limit":1000}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().SetFollowLimit(mock.Anything, "Test", "AK", 1000).Return(errors.New("mock error")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, tt.req, app.SetFollowLimit(), map[string]string{"username": "Test", "publication": "AK"}, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+
+			if tt.wantRespBody.Code != "" {
+				assert.Equal(t, tt.wantRespBody.Code, got.Code)
+			}
+
+			if tt.wantRespBody.Data != nil {
+				assert.Equal(t, tt.wantRespBody.Data, got.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"article-tag/internal/types"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// PutAliases maps the alias ids and names of the body to the tag of the path
+func (app *Application) PutAliases() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req types.AliasRequest
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			app.logger.Error("error decoding alias request body", zap.Error(err))
+			response.BadRequest(w, "invalid request", nil)
+
+			return
+		}
+
+		// fetch params from urlParams, they take precedence over the body
+		req.Publication = chi.URLParam(r, "publication")
+		req.TagID = chi.URLParam(r, "tagID")
+
+		// validate request
+		err = app.validate.Struct(req)
+		if err != nil {
+			response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+			return
+		}
+
+		if len(req.AliasIDs) == 0 && len(req.AliasNames) == 0 {
+			response.BadRequest(w, "alias_ids or alias_names is required", nil)
+
+			return
+		}
+
+		err = app.model.Tag.PutAliases(ctx, req.Publication, req.TagID, req.TagName, req.AliasIDs, req.AliasNames)
+		if err != nil {
+			app.logger.Error("error storing tag aliases", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while storing tag aliases")
+
+			return
+		}
+
+		app.audit(r, auditActorAdmin, model.AuditActionAlias, "", req.Publication, types.Tag{TagID: req.TagID, TagName: req.TagName},
+			fmt.Sprintf("alias ids %v, alias names %q", req.AliasIDs, req.AliasNames))
+
+		res := types.AliasResponse{TagID: req.TagID, TagName: req.TagName, AliasIDs: req.AliasIDs, AliasNames: req.AliasNames}
+		if res.AliasIDs == nil {
+			res.AliasIDs = []string{}
+		}
+
+		if res.AliasNames == nil {
+			res.AliasNames = []string{}
+		}
+
+		response.Success(w, res, "")
+	}
+}
+
+// Merge moves the follows and the counter of the alias tag of the path to its canonical tag
+func (app *Application) Merge() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		req := types.MergeRequest{Publication: chi.URLParam(r, "publication"), TagID: chi.URLParam(r, "tagID")}
+
+		// validate request
+		err := app.validate.Struct(req)
+		if err != nil {
+			response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+			return
+		}
+
+		res, err := app.model.Tag.Merge(ctx, req.Publication, req.TagID)
+		if err != nil {
+			app.logger.Error("error merging alias tag", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while merging alias tag")
+
+			return
+		}
+
+		app.audit(r, auditActorAdmin, model.AuditActionMerge, "", req.Publication, types.Tag{TagID: req.TagID},
+			fmt.Sprintf("merged into %s, %d moved, %d duplicates", res.CanonicalID, res.Moved, res.Duplicates))
+
+		response.Success(w, types.MergeResponse{
+			TagID:         req.TagID,
+			CanonicalID:   res.CanonicalID,
+			CanonicalName: res.CanonicalName,
+			Moved:         res.Moved,
+			Duplicates:    res.Duplicates,
+		}, "")
+	}
+}
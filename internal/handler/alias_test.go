@@ -0,0 +1,188 @@
+package handler_test
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/handler"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_PutAliases(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		req          []byte
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+	}{
+		{
+			name: "success",
+			req:  []byte(`{"tag_name":"artificial intelligence","alias_ids":["7"],"alias_names":["AI","A.I."]}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().PutAliases(mock.Anything, "AK", "5", "artificial intelligence", []string{"7"}, []string{"AI", "A.I."}).
+					Return(nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"tag_id":      "5",
+				"tag_name":    "artificial intelligence",
+				"alias_ids":   []interface{}{"7"},
+				"alias_names": []interface{}{"AI", "A.I."},
+			}},
+		},
+		{
+			name: "should fail when there is no alias",
+			req:  []byte(`{"tag_name":"artificial intelligence"}`),
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+		},
+		{
+			name: "should fail when alias id is not numeric",
+			req:  []byte(`{"tag_name":"artificial intelligence","alias_ids":["ai"]}`),
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+		},
+		{
+			name: "should fail when canonical tag is an alias",
+			req:  []byte(`{"tag_name":"artificial intelligence","alias_ids":["7"]}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().PutAliases(mock.Anything, "AK", "5", "artificial intelligence", []string{"7"}, []string(nil)).
+					Return(apperror.New(apperror.KindValidationFailed, apperror.CodeAliasChain, "tag 5 is an alias of tag 3")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusUnprocessableEntity, Code: apperror.CodeAliasChain},
+		},
+		{
+			name: "should fail when received error in putAliases call",
+			req:  []byte(`{"tag_name":"artificial intelligence","alias_names":["AI"]}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().PutAliases(mock.Anything, "AK", "5", "artificial intelligence", []string(nil), []string{"AI"}).
+					Return(errors.New("mock error")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, tt.req, app.PutAliases(), map[string]string{"publication": "AK", "tagID": "5"}, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+
+			if tt.wantRespBody.Code != "" {
+				assert.Equal(t, tt.wantRespBody.Code, got.Code)
+			}
+
+			if tt.wantRespBody.Data != nil {
+				assert.Equal(t, tt.wantRespBody.Data, got.Data)
+			}
+		})
+	}
+}
+
+func Test_Merge(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+	}{
+		{
+			name:      "success",
+			urlParams: map[string]string{"publication": "AK", "tagID": "7"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Merge(mock.Anything, "AK", "7").Return(&model.MergeResult{
+					CanonicalID:   "5",
+					CanonicalName: "artificial intelligence",
+					Moved:         2,
+					Duplicates:    1,
+					Usernames:     []string{"john", "jane", "jack"},
+				}, nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"tag_id":         "7",
+				"canonical_id":   "5",
+				"canonical_name": "artificial intelligence",
+				"moved":          float64(2),
+				"duplicates":     float64(1),
+			}},
+		},
+		{
+			name:      "should fail when tag is not an alias",
+			urlParams: map[string]string{"publication": "AK", "tagID": "9"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Merge(mock.Anything, "AK", "9").
+					Return(nil, apperror.New(apperror.KindNotFound, apperror.CodeAliasNotFound, "tag 9 is not an alias")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusNotFound, Code: apperror.CodeAliasNotFound},
+		},
+		{
+			name:      "should fail when publication is invalid",
+			urlParams: map[string]string{"publication": "XX", "tagID": "7"},
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+		},
+		{
+			name:      "should fail when received error in merge call",
+			urlParams: map[string]string{"publication": "AK", "tagID": "7"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Merge(mock.Anything, "AK", "7").Return(nil, errors.New("mock error")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, nil, app.Merge(), tt.urlParams, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+
+			if tt.wantRespBody.Code != "" {
+				assert.Equal(t, tt.wantRespBody.Code, got.Code)
+			}
+
+			if tt.wantRespBody.Data != nil {
+				assert.Equal(t, tt.wantRespBody.Data, got.Data)
+			}
+		})
+	}
+}
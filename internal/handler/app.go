@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"article-tag/internal/config"
+	"article-tag/internal/i18n"
 	"article-tag/internal/model"
+	"article-tag/internal/validation"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/go-playground/validator/v10"
@@ -9,20 +12,29 @@ import (
 )
 
 type Application struct {
-	db       *dynamodb.Client
-	model    model.Models
-	validate *validator.Validate
-	logger   *zap.Logger
+	db         *dynamodb.Client
+	model      model.Models
+	validate   *validator.Validate
+	translator *i18n.Translator
+	logger     *zap.Logger
 }
 
 // New
 func New(db *dynamodb.Client, models *model.Models, logger *zap.Logger) *Application {
+	// validation messages, catalogs of I18N_CATALOG_DIR are loaded over the embedded ones
+	translator, err := i18n.New(config.String("I18N_CATALOG_DIR", ""))
+	if err != nil {
+		logger.Error("error loading translation catalogs, using the embedded ones", zap.Error(err))
+		translator, _ = i18n.New("")
+	}
+
 	// return app object
 	return &Application{
-		db:       db,
-		model:    *models,
-		validate: validator.New(),
-		logger:   logger,
+		db:         db,
+		model:      *models,
+		validate:   validation.New(),
+		translator: translator,
+		logger:     logger,
 	}
 }
 
@@ -31,3 +43,9 @@ func GetLogger(app *Application) *zap.Logger {
 	// return logger object
 	return app.logger
 }
+
+// GetModels
+func GetModels(app *Application) model.Models {
+	// return models object
+	return app.model
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"article-tag/internal/types"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// auditActorAdmin
+const auditActorAdmin = "admin"
+
+// newAuditRecord creates the audit record of the request
+func newAuditRecord(r *http.Request, actor, action string) *model.AuditRecord {
+	return &model.AuditRecord{
+		Actor:     actor,
+		Action:    action,
+		RequestID: middleware.GetReqID(r.Context()),
+		SourceIP:  r.RemoteAddr,
+	}
+}
+
+// audit records the action of the actor, username and tag are empty when the action does not concern them.
+// Failures are only logged so that the audit trail never blocks the request.
+func (app *Application) audit(r *http.Request, actor, action, username, publication string, tag types.Tag, detail string) {
+	record := newAuditRecord(r, actor, action)
+	record.Username = username
+	record.Publication = publication
+	record.TagID = tag.TagID
+	record.TagName = tag.TagName
+	record.Detail = detail
+
+	err := app.model.Audit.Record(r.Context(), record)
+	if err != nil {
+		app.logger.Error("error recording audit", zap.Error(err), zap.Field{Key: "record",
+			Type: zapcore.ReflectType, Interface: record})
+	}
+}
+
+func (app *Application) AuditLog() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req types.AuditQueryRequest
+
+		// validate request
+		err := app.validateAuditQueryRequest(w, r, &req)
+		if err != nil {
+			app.logger.Error("error validating audit query request", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		filter := model.AuditFilter{
+			Username:    req.Username,
+			Publication: req.Publication,
+			TagID:       req.TagID,
+			Limit:       req.Limit,
+		}
+
+		// validator ensures the timestamps are valid
+		if req.From != "" {
+			filter.From, _ = time.Parse(time.RFC3339, req.From)
+		}
+
+		if req.To != "" {
+			filter.To, _ = time.Parse(time.RFC3339, req.To)
+		}
+
+		records, err := app.model.Audit.Query(ctx, filter)
+		if err != nil {
+			app.logger.Error("error fetching audit records from db", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while fetching audit records")
+
+			return
+		}
+
+		response.Success(w, types.AuditQueryResponse{Records: auditEntries(records)}, "")
+	}
+}
+
+// auditEntries
+func auditEntries(records []*model.AuditRecord) []types.AuditEntry {
+	entries := []types.AuditEntry{}
+	for _, val := range records {
+		entries = append(entries, types.AuditEntry{
+			Actor:       val.Actor,
+			Action:      val.Action,
+			Username:    val.Username,
+			Publication: val.Publication,
+			TagID:       val.TagID,
+			TagName:     val.TagName,
+			RequestID:   val.RequestID,
+			SourceIP:    val.SourceIP,
+			Detail:      val.Detail,
+			CreatedAt:   val.CreatedAt,
+		})
+	}
+
+	return entries
+}
+
+func (app *Application) validateAuditQueryRequest(w http.ResponseWriter, r *http.Request, req *types.AuditQueryRequest) error {
+	var err error
+
+	// fetch filters from queryParams
+	q := r.URL.Query()
+	req.Username = q.Get("username")
+	req.Publication = q.Get("publication")
+	req.TagID = q.Get("tag_id")
+	req.From = q.Get("from")
+	req.To = q.Get("to")
+
+	if limit := q.Get("limit"); limit != "" {
+		req.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			response.BadRequest(w, "", []response.InvalidParam{app.invalidParam(r, "limit", "Limit", "numeric", "")})
+
+			return err
+		}
+	}
+
+	err = app.validate.Struct(req)
+	if err != nil {
+		response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"article-tag/internal/handler"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_AuditLog(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		queryParams  map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+		wantErrors   map[string]string
+	}{
+		{
+			name:        "success - by user and time range",
+			queryParams: map[string]string{"username": "Test", "from": "2023-01-01T00:00:00Z", "to": "2023-02-01T00:00:00Z"},
+			mockDB: func() *handler.Application {
+				auditStoreMock := mocks.NewAuditStore(t)
+				auditStoreMock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(f model.AuditFilter) bool {
+					return f.Username == "Test" && f.From.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+				})).Return([]*model.AuditRecord{{Actor: "Test", Action: model.AuditActionFollow}}, nil)
+
+				m := model.Models{Audit: auditStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK},
+		},
+		{
+			name:        "success - by tag",
+			queryParams: map[string]string{"publication": "AK", "tag_id": "1"},
+			mockDB: func() *handler.Application {
+				auditStoreMock := mocks.NewAuditStore(t)
+				auditStoreMock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(f model.AuditFilter) bool {
+					return f.Publication == "AK" && f.TagID == "1"
+				})).Return([]*model.AuditRecord{}, nil)
+
+				m := model.Models{Audit: auditStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK},
+		},
+		{
+			name:        "success - by publication",
+			queryParams: map[string]string{"publication": "AK"},
+			mockDB: func() *handler.Application {
+				auditStoreMock := mocks.NewAuditStore(t)
+				auditStoreMock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(f model.AuditFilter) bool {
+					return f.Publication == "AK" && f.Username == "" && f.TagID == ""
+				})).Return([]*model.AuditRecord{}, nil)
+
+				m := model.Models{Audit: auditStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK},
+		},
+		{
+			name:        "should fail when neither username nor publication is passed",
+			queryParams: map[string]string{},
+			mockDB: func() *handler.Application {
+				m := model.Models{}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest},
+			wantErrors:   map[string]string{"Username": "username or publication is required"},
+		},
+		{
+			name:        "should fail when invalid time range is passed",
+			queryParams: map[string]string{"username": "Test", "from": "yesterday"},
+			mockDB: func() *handler.Application {
+				m := model.Models{}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest},
+			wantErrors:   map[string]string{"From": "from must be a RFC3339 timestamp"},
+		},
+		{
+			name:        "should fail when got error while fetching audit records",
+			queryParams: map[string]string{"username": "Test"},
+			mockDB: func() *handler.Application {
+				auditStoreMock := mocks.NewAuditStore(t)
+				auditStoreMock.EXPECT().Query(mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+				m := model.Models{Audit: auditStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while fetching audit records"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, nil, app.AuditLog(), nil, tt.queryParams)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+			assert.Equal(t, tt.wantRespBody.Message, got.Message)
+
+			if tt.wantErrors != nil {
+				gotErrors := []map[string]string{}
+				errJSON, _ := json.Marshal(got.Errors)
+				json.Unmarshal(errJSON, &gotErrors)
+
+				for k, v := range tt.wantErrors {
+					assert.Equal(t, v, gotErrors[0][k])
+				}
+			}
+		})
+	}
+}
@@ -1,11 +1,19 @@
 package handler
 
 import (
-	"article-tag/internal/constant"
+	"article-tag/internal/apperror"
+	"article-tag/internal/i18n"
+	"article-tag/internal/model"
 	"article-tag/internal/response"
 	"article-tag/internal/types"
+	"article-tag/internal/validation"
+	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi"
 	"github.com/go-playground/validator/v10"
@@ -27,16 +35,28 @@ func (app *Application) Store() http.HandlerFunc {
 			return
 		}
 
+		ctx, err = app.matchVersion(w, r)
+		if err != nil {
+			app.logger.Error("error matching follow set version", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
 		// store follow tag
 		for _, val := range req.Tags {
-			err = app.model.Tag.Store(ctx, req.Username, req.Publication, val.TagName, val.TagID)
+			// an alias is followed as its canonical tag
+			followed, err := app.model.Tag.Store(ctx, req.Username, req.Publication, val.TagName, val.TagID, val.Descendants)
 			if err != nil {
 				app.logger.Error("error while storing item", zap.Error(err), zap.Field{Key: "request",
 					Type: zapcore.ReflectType, Interface: req})
-				response.InternalServerError(w, "error while storing user tag")
+				response.Error(w, err, "error while storing user tag")
 
 				return
 			}
+
+			app.audit(r, req.Username, model.AuditActionFollow, req.Username, req.Publication,
+				types.Tag{TagID: followed.TagID, TagName: followed.TagName}, "")
 		}
 
 		response.Created(w, "")
@@ -57,12 +77,23 @@ func (app *Application) Get() http.HandlerFunc {
 			return
 		}
 
+		// version of the follow set, sent back as If-Match by the writes. It is read
+		// before the tags so a concurrent write can only make the ETag older than them
+		version, err := app.model.Tag.Version(ctx, req.Username, req.Publication)
+		if err != nil {
+			app.logger.Error("error fetching follow set version from db", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while fetching user tags")
+
+			return
+		}
+
 		// fetch tags using username and publication
 		userTags, err := app.model.Tag.Get(ctx, req.Username, req.Publication, req.Order)
 		if err != nil {
 			app.logger.Error("error fetching user tags from db", zap.Error(err), zap.Field{Key: "request",
 				Type: zapcore.ReflectType, Interface: req})
-			response.InternalServerError(w, "error while fetching user tags")
+			response.Error(w, err, "error while fetching user tags")
 
 			return
 		}
@@ -70,14 +101,25 @@ func (app *Application) Get() http.HandlerFunc {
 		var tags []types.Tag
 		for _, val := range userTags {
 			tags = append(tags, types.Tag{
-				TagID:   val.TagID,
-				TagName: val.TagName,
+				TagID:       val.TagID,
+				TagName:     val.TagName,
+				Descendants: val.IncludeDescendants,
 			})
 		}
 
+		implicit, err := app.implicitTags(ctx, req, userTags)
+		if err != nil {
+			app.logger.Error("error fetching implicit user tags from db", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while fetching user tags")
+
+			return
+		}
+
 		// prepare response
-		resp := types.GetTagResponse{Tags: tags}
+		resp := types.GetTagResponse{Tags: tags, Version: version, Implicit: implicit}
 
+		setVersionETag(w, version, implicit)
 		response.Success(w, resp, "")
 	}
 }
@@ -96,16 +138,137 @@ func (app *Application) Delete() http.HandlerFunc {
 			return
 		}
 
+		ctx, err = app.matchVersion(w, r)
+		if err != nil {
+			app.logger.Error("error matching follow set version", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
 		for _, val := range req.Tags {
 			// delete user tag
 			err = app.model.Tag.Delete(ctx, req.Username, req.Publication, val.TagID, val.TagName)
 			if err != nil {
 				app.logger.Error("error deleting user tags", zap.Error(err), zap.Field{Key: "request",
 					Type: zapcore.ReflectType, Interface: req})
-				response.InternalServerError(w, "error while deleting user followed tags")
+				response.Error(w, err, "error while deleting user followed tags")
+
+				return
+			}
+
+			app.audit(r, req.Username, model.AuditActionUnfollow, req.Username, req.Publication, val, "")
+		}
+
+		response.Success(w, nil, "")
+	}
+}
+
+// Replace makes the tags of the request the followed tags of the user and returns the changes
+func (app *Application) Replace() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req types.ReplaceTagRequest
+
+		// validate request
+		err := app.validateReplaceRequest(w, r, &req)
+		if err != nil {
+			app.logger.Error("error validating replace request", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		ctx, err = app.matchVersion(w, r)
+		if err != nil {
+			app.logger.Error("error matching follow set version", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		tags := []*model.UserTag{}
+		for _, val := range req.Tags {
+			tags = append(tags, &model.UserTag{TagID: val.TagID, TagName: val.TagName, IncludeDescendants: val.Descendants})
+		}
+
+		added, removed, updated, err := app.model.Tag.Replace(ctx, req.Username, req.Publication, tags)
+		if err != nil {
+			app.logger.Error("error replacing user tags", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while replacing user tags")
+
+			return
+		}
+
+		resp := types.ReplaceTagResponse{Added: []types.Tag{}, Removed: []types.Tag{}, Updated: []types.Tag{}}
+		for _, val := range added {
+			tag := types.Tag{TagID: val.TagID, TagName: val.TagName, Descendants: val.IncludeDescendants}
+			resp.Added = append(resp.Added, tag)
+			app.audit(r, req.Username, model.AuditActionFollow, req.Username, req.Publication, tag, "")
+		}
+
+		for _, val := range removed {
+			tag := types.Tag{TagID: val.TagID, TagName: val.TagName}
+			resp.Removed = append(resp.Removed, tag)
+			app.audit(r, req.Username, model.AuditActionUnfollow, req.Username, req.Publication, tag, "")
+		}
+
+		for _, val := range updated {
+			tag := types.Tag{TagID: val.TagID, TagName: val.TagName, Descendants: val.IncludeDescendants}
+			resp.Updated = append(resp.Updated, tag)
+			app.audit(r, req.Username, model.AuditActionFollow, req.Username, req.Publication, tag,
+				fmt.Sprintf("descendants set to %t", val.IncludeDescendants))
+		}
+
+		// version of the follow set after the changes
+		resp.Version, err = app.model.Tag.Version(ctx, req.Username, req.Publication)
+		if err != nil {
+			app.logger.Error("error fetching follow set version from db", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while replacing user tags")
+
+			return
+		}
+
+		response.Success(w, resp, "")
+	}
+}
+
+func (app *Application) Undo() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req types.UndoTagRequest
+
+		// validate request
+		err := app.validateUndoRequest(w, r, &req)
+		if err != nil {
+			app.logger.Error("error validating undo request", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		ctx, err = app.matchVersion(w, r)
+		if err != nil {
+			app.logger.Error("error matching follow set version", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		for _, val := range req.Tags {
+			// restore unfollowed user tag
+			err = app.model.Tag.Restore(ctx, req.Username, req.Publication, val.TagID)
+			if err != nil {
+				app.logger.Error("error restoring user tags", zap.Error(err), zap.Field{Key: "request",
+					Type: zapcore.ReflectType, Interface: req})
+				response.Error(w, err, "error while restoring user unfollowed tags")
 
 				return
 			}
+
+			app.audit(r, req.Username, model.AuditActionUndo, req.Username, req.Publication, val, "")
 		}
 
 		response.Success(w, nil, "")
@@ -131,7 +294,7 @@ func (app *Application) PopularTag() http.HandlerFunc {
 		if err != nil {
 			app.logger.Error("error fetching popular tags from db", zap.Error(err), zap.Field{Key: "request",
 				Type: zapcore.ReflectType, Interface: req})
-			response.InternalServerError(w, "error while fetching popular tags")
+			response.Error(w, err, "error while fetching popular tags")
 
 			return
 		}
@@ -155,15 +318,7 @@ func (app *Application) validateStoreRequest(w http.ResponseWriter, r *http.Requ
 
 	err = app.validate.Struct(req)
 	if err != nil {
-
-		var errorBag []map[string]interface{}
-		for _, v := range err.(validator.ValidationErrors) {
-			errorBag = append(errorBag, map[string]interface{}{
-				v.Field(): constant.TagError[v.Field()],
-			})
-		}
-
-		response.BadRequest(w, "", errorBag)
+		response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
 
 		return err
 	}
@@ -178,21 +333,14 @@ func (app *Application) validateGetRequest(w http.ResponseWriter, r *http.Reques
 	req.Username = r.URL.Query().Get("username")
 
 	req.Order = r.URL.Query().Get("order")
+	req.Include = r.URL.Query().Get("include")
 
 	// fetch params from urlParams
 	req.Publication = chi.URLParam(r, "publication")
 
 	err = app.validate.Struct(req)
 	if err != nil {
-
-		var errorBag []map[string]interface{}
-		for _, v := range err.(validator.ValidationErrors) {
-			errorBag = append(errorBag, map[string]interface{}{
-				v.Field(): constant.TagError[v.Field()],
-			})
-		}
-
-		response.BadRequest(w, "", errorBag)
+		response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
 
 		return err
 	}
@@ -214,15 +362,51 @@ func (app *Application) validateDeleteRequest(w http.ResponseWriter, r *http.Req
 
 	err = app.validate.Struct(req)
 	if err != nil {
+		response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
 
-		var errorBag []map[string]interface{}
-		for _, v := range err.(validator.ValidationErrors) {
-			errorBag = append(errorBag, map[string]interface{}{
-				v.Field(): constant.TagError[v.Field()],
-			})
-		}
+		return err
+	}
 
-		response.BadRequest(w, "", errorBag)
+	return nil
+}
+
+func (app *Application) validateReplaceRequest(w http.ResponseWriter, r *http.Request, req *types.ReplaceTagRequest) error {
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		app.logger.Error("error decoding replace request body", zap.Error(err))
+		response.BadRequest(w, "invalid request", nil)
+
+		return err
+	}
+
+	// fetch params from urlParams
+	req.Publication = chi.URLParam(r, "publication")
+
+	err = app.validate.Struct(req)
+	if err != nil {
+		response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+		return err
+	}
+
+	return nil
+}
+
+func (app *Application) validateUndoRequest(w http.ResponseWriter, r *http.Request, req *types.UndoTagRequest) error {
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		app.logger.Error("error decoding undo request body", zap.Error(err))
+		response.BadRequest(w, "invalid request", nil)
+
+		return err
+	}
+
+	// fetch params from urlParams
+	req.Publication = chi.URLParam(r, "publication")
+
+	err = app.validate.Struct(req)
+	if err != nil {
+		response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
 
 		return err
 	}
@@ -241,18 +425,99 @@ func (app *Application) validateGetPopularTagRequest(w http.ResponseWriter, r *h
 
 	err = app.validate.Struct(req)
 	if err != nil {
-
-		var errorBag []map[string]interface{}
-		for _, v := range err.(validator.ValidationErrors) {
-			errorBag = append(errorBag, map[string]interface{}{
-				v.Field(): constant.TagError[v.Field()],
-			})
-		}
-
-		response.BadRequest(w, "", errorBag)
+		response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
 
 		return err
 	}
 
 	return nil
 }
+
+// validationErrorBag maps every failed field to its message in the language of the request,
+// the name is the json path of the field (e.g. tags[0].tag_id) and the rule the failed validation tag
+func (app *Application) validationErrorBag(r *http.Request, errs validator.ValidationErrors) []response.InvalidParam {
+	trans := app.translator.Locale(r.Header.Get("Accept-Language"))
+
+	var errorBag []response.InvalidParam
+	for _, v := range errs {
+		errorBag = append(errorBag, response.InvalidParam{
+			Name:   validation.FieldPath(v.Namespace()),
+			Reason: i18n.Message(trans, v.Field(), v.Tag(), v.Param()),
+			Rule:   v.Tag(),
+			Field:  v.StructField(),
+		})
+	}
+
+	return errorBag
+}
+
+// invalidParam of a parameter validated without the validator
+func (app *Application) invalidParam(r *http.Request, name, field, rule, param string) response.InvalidParam {
+	trans := app.translator.Locale(r.Header.Get("Accept-Language"))
+
+	return response.InvalidParam{
+		Name:   name,
+		Reason: i18n.Message(trans, name, rule, param),
+		Rule:   rule,
+		Field:  field,
+	}
+}
+
+// implicitTags returns the tags followed through an ancestor when the request includes them, nil otherwise
+func (app *Application) implicitTags(ctx context.Context, req types.GetTagRequest, userTags []*model.UserTag) ([]types.Tag, error) {
+	if req.Include != "implicit" {
+		return nil, nil
+	}
+
+	implicit, err := app.model.Tag.ImplicitTags(ctx, req.Publication, userTags)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []types.Tag{}
+	for _, val := range implicit {
+		tags = append(tags, types.Tag{TagID: val.TagID, TagName: val.TagName})
+	}
+
+	return tags, nil
+}
+
+// matchVersion reads the If-Match header of a write, the ETag of the followed tags returned by Get. The returned
+// context makes the write fail unless the follow set is still at that version, the version is incremented by the
+// write so a concurrent write holding the same ETag fails. Writes without the header are not checked.
+func (app *Application) matchVersion(w http.ResponseWriter, r *http.Request) (context.Context, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return r.Context(), nil
+	}
+
+	// the digest of the implicit tags is not part of the version
+	etag, _, _ := strings.Cut(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), ".")
+
+	version, err := strconv.ParseInt(etag, 10, 64)
+	if err != nil {
+		err = apperror.Wrap(apperror.KindPreconditionFailed, apperror.CodeVersionMismatch,
+			"If-Match is not the ETag of the followed tags, read them again to get it", err)
+		response.Error(w, err, "")
+
+		return nil, err
+	}
+
+	return model.WithVersion(r.Context(), version), nil
+}
+
+// setVersionETag sets the ETag of the followed tags, the version of the follow set so it is sent back as If-Match
+// by the writes. The implicit tags depend on the taxonomy as well, a digest of them is appended when included.
+func setVersionETag(w http.ResponseWriter, version int64, implicit []types.Tag) {
+	etag := strconv.FormatInt(version, 10)
+	if implicit != nil {
+		h := sha256.New()
+		for _, val := range implicit {
+			fmt.Fprintf(h, "%s\x00%s\x00", val.TagID, val.TagName)
+		}
+
+		etag = fmt.Sprintf("%s.%x", etag, h.Sum(nil)[:8])
+	}
+
+	w.Header().Set("ETag", `"`+etag+`"`)
+}
@@ -1,15 +1,18 @@
 package handler_test
 
 import (
+	"article-tag/internal/apperror"
 	"article-tag/internal/handler"
 	"article-tag/internal/mocks"
 	"article-tag/internal/model"
+	"article-tag/internal/resilience"
 	"article-tag/internal/response"
 	"article-tag/internal/types"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -65,10 +68,14 @@ func Test_Store(t *testing.T) {
 			},
 			mockDB: func() *handler.Application {
 				tagStoreMock := mocks.NewUserTagStore(t)
-				tagStoreMock.EXPECT().Store(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+				tagStoreMock.EXPECT().Store(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					RunAndReturn(func(ctx context.Context, username, publication, tagName, tagID string, descendants bool) (*model.UserTag, error) {
+						return &model.UserTag{TagID: tagID, TagName: tagName, IncludeDescendants: descendants}, nil
+					})
 
 				m := model.Models{
-					Tag: tagStoreMock,
+					Tag:   tagStoreMock,
+					Audit: auditStoreMock(t),
 				}
 
 				return handler.New(nil, &m, log)
@@ -87,7 +94,7 @@ func Test_Store(t *testing.T) {
 				return handler.New(nil, &m, log)
 			},
 			wantRespBody: &response.Body{Status: http.StatusBadRequest},
-			wantErrors:   map[string]string{"Username": "field is required"},
+			wantErrors:   map[string]string{"Username": "username is required"},
 		},
 		{
 			name: "should fail when invalid request is passed - empty publication",
@@ -101,7 +108,7 @@ func Test_Store(t *testing.T) {
 				return handler.New(nil, &m, log)
 			},
 			wantRespBody: &response.Body{Status: http.StatusBadRequest},
-			wantErrors:   map[string]string{"Publication": "field is required, and must be a valid publications"},
+			wantErrors:   map[string]string{"Publication": "publication is required"},
 		},
 		{
 			name: "should fail when invalid request is passed - empty tags",
@@ -115,7 +122,7 @@ func Test_Store(t *testing.T) {
 				return handler.New(nil, &m, log)
 			},
 			wantRespBody: &response.Body{Status: http.StatusBadRequest},
-			wantErrors:   map[string]string{"Tags": "atleast one tag is required"},
+			wantErrors:   map[string]string{"Tags": "at least one tag is required"},
 		},
 		{
 			name: "should fail when got error while storing user tags",
@@ -126,7 +133,7 @@ func Test_Store(t *testing.T) {
 			mockDB: func() *handler.Application {
 				tagStoreMock := mocks.NewUserTagStore(t)
 				// tagStoreMock.EXPECT().DescribeTable(mock.Anything).Return(nil)
-				tagStoreMock.EXPECT().Store(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
+				tagStoreMock.EXPECT().Store(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
 
 				m := model.Models{Tag: tagStoreMock}
 
@@ -134,6 +141,40 @@ func Test_Store(t *testing.T) {
 			},
 			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while storing user tag"},
 		},
+		{
+			name: "should fail with too many requests when the store is throttled",
+			args: args{
+				req:       types.StoreTagRequest{Username: "Test", Tags: []types.Tag{{TagID: "1", TagName: "tag100"}}},
+				urlParams: map[string]string{"publication": "AK"},
+			},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("%w: db error", resilience.ErrThrottled))
+
+				m := model.Models{Tag: tagStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusTooManyRequests, Message: "too many requests, retry later"},
+		},
+		{
+			name: "should fail with service unavailable when the store is unavailable",
+			args: args{
+				req:       types.StoreTagRequest{Username: "Test", Tags: []types.Tag{{TagID: "1", TagName: "tag100"}}},
+				urlParams: map[string]string{"publication": "AK"},
+			},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("%w: circuit breaker is open", resilience.ErrUnavailable))
+
+				m := model.Models{Tag: tagStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusServiceUnavailable, Message: "service unavailable, retry later"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -162,6 +203,84 @@ func Test_Store(t *testing.T) {
 	}
 }
 
+func Test_StoreProblemDetails(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		req            types.StoreTagRequest
+		mockDB         func() *handler.Application
+		wantStatus     int
+		wantType       string
+		wantParams     []response.InvalidParam
+	}{
+		{
+			name: "invalid params with the path and rule",
+			req:  types.StoreTagRequest{Username: "", Tags: []types.Tag{{TagID: "1", TagName: "tag1"}, {TagID: "abc", TagName: "tag2"}}},
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantType:   "/problems/invalid_request",
+			wantParams: []response.InvalidParam{
+				{Name: "username", Reason: "username is required", Rule: "required"},
+				{Name: "tags[1].tag_id", Reason: "tag_id must have a numeric format", Rule: "numeric"},
+			},
+		},
+		{
+			name:           "invalid params in the language of the request",
+			acceptLanguage: "es-ES,es;q=0.9,en;q=0.8",
+			req:            types.StoreTagRequest{Username: "Test", Tags: nil},
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantType:   "/problems/invalid_request",
+			wantParams: []response.InvalidParam{
+				{Name: "tags", Reason: "se requiere al menos una etiqueta", Rule: "required"},
+			},
+		},
+		{
+			name: "domain error",
+			req:  types.StoreTagRequest{Username: "Test", Tags: []types.Tag{{TagID: "1", TagName: "tag1"}}},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, fmt.Errorf("%w: db error", resilience.ErrThrottled))
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantStatus: http.StatusTooManyRequests,
+			wantType:   "/problems/store_throttled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			rawReq, _ := json.Marshal(tt.req)
+			r := httptest.NewRequest(http.MethodPost, "/tags/AK", bytes.NewBuffer(rawReq))
+			r = setURLParams(r, map[string]string{"publication": "AK"})
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			rec := httptest.NewRecorder()
+			app.Store().ServeHTTP(response.ProblemWriter(rec, r.URL.Path), r)
+
+			got := response.Problem{}
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &got))
+
+			assert.Equal(t, response.ProblemContentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantType, got.Type)
+			assert.Equal(t, http.StatusText(tt.wantStatus), got.Title)
+			assert.Equal(t, "/tags/AK", got.Instance)
+			assert.Equal(t, tt.wantParams, got.InvalidParams)
+		})
+	}
+}
+
 func Test_Get(t *testing.T) {
 	log := testSuite()
 
@@ -186,7 +305,8 @@ func Test_Get(t *testing.T) {
 			},
 			mockDB: func() *handler.Application {
 				tagStoreMock := mocks.NewUserTagStore(t)
-				tagStoreMock.EXPECT().Get(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*model.UserTag{}, nil)
+				version := tagStoreMock.EXPECT().Version(mock.Anything, "Test", "AK").Return(int64(3), nil).Once()
+				tagStoreMock.EXPECT().Get(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*model.UserTag{}, nil).NotBefore(version)
 
 				m := model.Models{
 					Tag: tagStoreMock,
@@ -196,6 +316,22 @@ func Test_Get(t *testing.T) {
 			},
 			wantRespBody: &response.Body{Status: http.StatusOK, Message: ""},
 		},
+		{
+			name: "should fail when got error while fetching the version",
+			args: args{
+				urlParams:   map[string]string{"publication": "AK"},
+				queryParams: map[string]string{"username": "Test"},
+			},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Version(mock.Anything, "Test", "AK").Return(int64(0), errors.New("db error")).Once()
+
+				m := model.Models{Tag: tagStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while fetching user tags"},
+		},
 		{
 			name: "should fail when invalid request is passed - empty username",
 			args: args{
@@ -208,7 +344,7 @@ func Test_Get(t *testing.T) {
 				return handler.New(nil, &m, log)
 			},
 			wantRespBody: &response.Body{Status: http.StatusBadRequest},
-			wantErrors:   map[string]string{"Username": "field is required"},
+			wantErrors:   map[string]string{"Username": "username is required"},
 		},
 		{
 			name: "should fail when invalid request is passed - empty publication",
@@ -222,7 +358,7 @@ func Test_Get(t *testing.T) {
 				return handler.New(nil, &m, log)
 			},
 			wantRespBody: &response.Body{Status: http.StatusBadRequest},
-			wantErrors:   map[string]string{"Publication": "field is required, and must be a valid publications"},
+			wantErrors:   map[string]string{"Publication": "publication is required"},
 		},
 		{
 			name: "should fail when got error while storing user tags",
@@ -232,6 +368,7 @@ func Test_Get(t *testing.T) {
 			},
 			mockDB: func() *handler.Application {
 				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Version(mock.Anything, "Test", "AK").Return(int64(3), nil).Once()
 				tagStoreMock.EXPECT().Get(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*model.UserTag{}, errors.New("db error"))
 
 				m := model.Models{Tag: tagStoreMock}
@@ -267,6 +404,305 @@ func Test_Get(t *testing.T) {
 	}
 }
 
+func Test_Replace(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		req          []byte
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+	}{
+		{
+			name: "success",
+			req:  []byte(`{"username":"Test","tags":[{"tag_id":"2","tag_name":"tag2"},{"tag_id":"3","tag_name":"tag3"}]}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Replace(mock.Anything, "Test", "AK", []*model.UserTag{{TagID: "2", TagName: "tag2"}, {TagID: "3", TagName: "tag3"}}).
+					Return([]*model.UserTag{{TagID: "3", TagName: "tag3"}}, []*model.UserTag{{TagID: "1", TagName: "tag1"}}, []*model.UserTag{}, nil).Once()
+				tagStoreMock.EXPECT().Version(mock.Anything, "Test", "AK").Return(int64(4), nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"added":   []interface{}{map[string]interface{}{"tag_id": "3", "tag_name": "tag3"}},
+				"removed": []interface{}{map[string]interface{}{"tag_id": "1", "tag_name": "tag1"}},
+				"updated": []interface{}{},
+				"version": float64(4),
+			}},
+		},
+		{
+			name: "success - descendants of the tags are followed as requested",
+			req:  []byte(`{"username":"Test","tags":[{"tag_id":"2","tag_name":"tag2","descendants":true},{"tag_id":"3","tag_name":"tag3","descendants":true}]}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Replace(mock.Anything, "Test", "AK", []*model.UserTag{
+					{TagID: "2", TagName: "tag2", IncludeDescendants: true},
+					{TagID: "3", TagName: "tag3", IncludeDescendants: true},
+				}).Return(
+					[]*model.UserTag{{TagID: "3", TagName: "tag3", IncludeDescendants: true}},
+					[]*model.UserTag{},
+					[]*model.UserTag{{TagID: "2", TagName: "tag2", IncludeDescendants: true}},
+					nil,
+				).Once()
+				tagStoreMock.EXPECT().Version(mock.Anything, "Test", "AK").Return(int64(6), nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"added":   []interface{}{map[string]interface{}{"tag_id": "3", "tag_name": "tag3", "descendants": true}},
+				"removed": []interface{}{},
+				"updated": []interface{}{map[string]interface{}{"tag_id": "2", "tag_name": "tag2", "descendants": true}},
+				"version": float64(6),
+			}},
+		},
+		{
+			name: "success - an empty list unfollows every tag",
+			req:  []byte(`{"username":"Test","tags":[]}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Replace(mock.Anything, "Test", "AK", []*model.UserTag{}).
+					Return([]*model.UserTag{}, []*model.UserTag{{TagID: "1", TagName: "tag1"}}, []*model.UserTag{}, nil).Once()
+				tagStoreMock.EXPECT().Version(mock.Anything, "Test", "AK").Return(int64(5), nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"added":   []interface{}{},
+				"removed": []interface{}{map[string]interface{}{"tag_id": "1", "tag_name": "tag1"}},
+				"updated": []interface{}{},
+				"version": float64(5),
+			}},
+		},
+		{
+			name: "should fail when tags are missing",
+			req:  []byte(`{"username":"Test"}`),
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+		},
+		{
+			name: "should fail with conflict when follow set changed concurrently",
+			req:  []byte(`{"username":"Test","tags":[]}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Replace(mock.Anything, "Test", "AK", mock.Anything).
+					Return(nil, nil, nil, apperror.New(apperror.KindConflict, apperror.CodeFollowSetChanged, "follow set has changed while it was replaced")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusConflict, Code: apperror.CodeFollowSetChanged},
+		},
+		{
+			name: "should fail when tags exceed the follow limit",
+			req:  []byte(`{"username":"Test","tags":[{"tag_id":"2","tag_name":"tag2"}]}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Replace(mock.Anything, "Test", "AK", mock.Anything).
+					Return(nil, nil, nil, apperror.New(apperror.KindValidationFailed, apperror.CodeTooManyTags, "2 tags exceed the follow limit of 1")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusUnprocessableEntity, Code: apperror.CodeTooManyTags},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, tt.req, app.Replace(), map[string]string{"publication": "AK"}, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+
+			if tt.wantRespBody.Code != "" {
+				assert.Equal(t, tt.wantRespBody.Code, got.Code)
+			}
+
+			if tt.wantRespBody.Data != nil {
+				assert.Equal(t, tt.wantRespBody.Data, got.Data)
+			}
+		})
+	}
+}
+
+func Test_IfMatch(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name       string
+		ifMatch    string
+		mockDB     func() *handler.Application
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "success without If-Match",
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "Test", "AK", "tag1", "1", false).Return(&model.UserTag{TagID: "1", TagName: "tag1"}, nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:    "success when version matches",
+			ifMatch: `"3"`,
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "Test", "AK", "tag1", "1", false).Return(&model.UserTag{TagID: "1", TagName: "tag1"}, nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:    "success with the ETag of the followed tags including implicit tags",
+			ifMatch: `W/"3.5f2b1c0d9e8a7b6c"`,
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "Test", "AK", "tag1", "1", false).Return(&model.UserTag{TagID: "1", TagName: "tag1"}, nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:    "success with any version",
+			ifMatch: "*",
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "Test", "AK", "tag1", "1", false).Return(&model.UserTag{TagID: "1", TagName: "tag1"}, nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:    "should fail when version is stale",
+			ifMatch: "2",
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "Test", "AK", "tag1", "1", false).
+					Return(nil, apperror.New(apperror.KindPreconditionFailed, apperror.CodeVersionMismatch, "follow set has changed since it was read")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+			wantCode:   apperror.CodeVersionMismatch,
+		},
+		{
+			name:    "should fail when If-Match is not the ETag of the followed tags",
+			ifMatch: `"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`,
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+			wantCode:   apperror.CodeVersionMismatch,
+		},
+		{
+			name:    "should fail when If-Match is not a version",
+			ifMatch: `"abc"`,
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+			wantCode:   apperror.CodeVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			rawReq, _ := json.Marshal(types.StoreTagRequest{Username: "Test", Tags: []types.Tag{{TagID: "1", TagName: "tag1"}}})
+			r := httptest.NewRequest(http.MethodPost, "/tags/AK", bytes.NewBuffer(rawReq))
+			r = setURLParams(r, map[string]string{"publication": "AK"})
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			rec := httptest.NewRecorder()
+			app.Store().ServeHTTP(rec, r)
+
+			got := response.Body{}
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &got))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantCode, got.Code)
+		})
+	}
+}
+
+func Test_UndoIfMatch(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name       string
+		ifMatch    string
+		mockDB     func() *handler.Application
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:    "success when version matches",
+			ifMatch: `"3"`,
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Restore(mock.Anything, "Test", "AK", "1").Return(nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:    "should fail when version is stale",
+			ifMatch: `"2"`,
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Restore(mock.Anything, "Test", "AK", "1").
+					Return(apperror.New(apperror.KindPreconditionFailed, apperror.CodeVersionMismatch, "follow set has changed since it was read")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+			wantCode:   apperror.CodeVersionMismatch,
+		},
+		{
+			name:    "should fail when If-Match is not a version",
+			ifMatch: `"abc"`,
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+			wantCode:   apperror.CodeVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			rawReq, _ := json.Marshal(types.UndoTagRequest{Username: "Test", Tags: []types.Tag{{TagID: "1", TagName: "tag1"}}})
+			r := httptest.NewRequest(http.MethodPost, "/tags/AK/undo", bytes.NewBuffer(rawReq))
+			r = setURLParams(r, map[string]string{"publication": "AK"})
+			r.Header.Set("If-Match", tt.ifMatch)
+
+			rec := httptest.NewRecorder()
+			app.Undo().ServeHTTP(rec, r)
+
+			got := response.Body{}
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &got))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantCode, got.Code)
+		})
+	}
+}
+
 func Test_Delete(t *testing.T) {
 	log := testSuite()
 
@@ -294,7 +730,8 @@ func Test_Delete(t *testing.T) {
 				tagStoreMock.EXPECT().Delete(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 				m := model.Models{
-					Tag: tagStoreMock,
+					Tag:   tagStoreMock,
+					Audit: auditStoreMock(t),
 				}
 
 				return handler.New(nil, &m, log)
@@ -313,7 +750,7 @@ func Test_Delete(t *testing.T) {
 				return handler.New(nil, &m, log)
 			},
 			wantRespBody: &response.Body{Status: http.StatusBadRequest},
-			wantErrors:   map[string]string{"Username": "field is required"},
+			wantErrors:   map[string]string{"Username": "username is required"},
 		},
 		{
 			name: "should fail when invalid request is passed - empty publication",
@@ -327,7 +764,7 @@ func Test_Delete(t *testing.T) {
 				return handler.New(nil, &m, log)
 			},
 			wantRespBody: &response.Body{Status: http.StatusBadRequest},
-			wantErrors:   map[string]string{"Publication": "field is required, and must be a valid publications"},
+			wantErrors:   map[string]string{"Publication": "publication is required"},
 		},
 		{
 			name: "should fail when invalid request is passed - empty tags",
@@ -341,7 +778,7 @@ func Test_Delete(t *testing.T) {
 				return handler.New(nil, &m, log)
 			},
 			wantRespBody: &response.Body{Status: http.StatusBadRequest},
-			wantErrors:   map[string]string{"Tags": "atleast one tag is required"},
+			wantErrors:   map[string]string{"Tags": "at least one tag is required"},
 		},
 		{
 			name: "Should fail when receive error from database while deleting userTag",
@@ -359,7 +796,45 @@ func Test_Delete(t *testing.T) {
 
 				return handler.New(nil, &m, log)
 			},
-			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while deleting user followed tags"},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while deleting user followed tags", Code: "internal_error"},
+		},
+		{
+			name: "Should fail with not found when tag is not followed",
+			args: args{
+				types.DeleteTagRequest{Username: "Test", Tags: []types.Tag{{TagID: "1", TagName: "tag101"}}},
+				map[string]string{"publication": "AK"},
+			},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Delete(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(apperror.New(apperror.KindNotFound, apperror.CodeTagNotFollowed, "tag is not followed"))
+
+				m := model.Models{
+					Tag: tagStoreMock,
+				}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusNotFound, Message: "tag is not followed", Code: apperror.CodeTagNotFollowed},
+		},
+		{
+			name: "Should fail with conflict when tag name does not match",
+			args: args{
+				types.DeleteTagRequest{Username: "Test", Tags: []types.Tag{{TagID: "1", TagName: "tag101"}}},
+				map[string]string{"publication": "AK"},
+			},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Delete(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(apperror.New(apperror.KindConflict, apperror.CodeTagNameMismatch, "tag name does not match the followed tag"))
+
+				m := model.Models{
+					Tag: tagStoreMock,
+				}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusConflict, Message: "tag name does not match the followed tag", Code: apperror.CodeTagNameMismatch},
 		},
 	}
 
@@ -377,6 +852,100 @@ func Test_Delete(t *testing.T) {
 			assert.Equal(t, got.Status, tt.wantRespBody.Status)
 			assert.Equal(t, got.Message, tt.wantRespBody.Message)
 
+			if tt.wantRespBody.Code != "" {
+				assert.Equal(t, tt.wantRespBody.Code, got.Code)
+			}
+
+			if tt.wantErrors != nil {
+				gotErrors := []map[string]string{}
+				errJSON, _ := json.Marshal(got.Errors)
+				json.Unmarshal(errJSON, &gotErrors)
+
+				for k, v := range tt.wantErrors {
+					assert.Equal(t, v, gotErrors[0][k])
+				}
+			}
+		})
+	}
+}
+
+func Test_Undo(t *testing.T) {
+	log := testSuite()
+
+	type args struct {
+		req       types.UndoTagRequest
+		urlParams map[string]string
+	}
+
+	tests := []struct {
+		name         string
+		args         args
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+		wantErrors   map[string]string
+	}{
+		{
+			name: "success",
+			args: args{
+				req:       types.UndoTagRequest{Username: "Test", Tags: []types.Tag{{TagID: "1", TagName: "tag101"}}},
+				urlParams: map[string]string{"publication": "AK"},
+			},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Restore(mock.Anything, "Test", "AK", "1").Return(nil)
+
+				m := model.Models{
+					Tag:   tagStoreMock,
+					Audit: auditStoreMock(t),
+				}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Message: ""},
+		},
+		{
+			name: "should fail when invalid request is passed - empty username",
+			args: args{
+				req:       types.UndoTagRequest{Username: "", Tags: []types.Tag{{TagID: "1", TagName: "tag101"}}},
+				urlParams: map[string]string{"publication": "AK"},
+			},
+			mockDB: func() *handler.Application {
+				m := model.Models{}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest},
+			wantErrors:   map[string]string{"Username": "username is required"},
+		},
+		{
+			name: "Should fail when receive error from database while restoring userTag",
+			args: args{
+				req:       types.UndoTagRequest{Username: "Test", Tags: []types.Tag{{TagID: "1", TagName: "tag101"}}},
+				urlParams: map[string]string{"publication": "AK"},
+			},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Restore(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+				m := model.Models{Tag: tagStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while restoring user unfollowed tags"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			rawReq, _ := json.Marshal(tt.args.req)
+			got, gotErr := callEndpoint(t, rawReq, app.Undo(), tt.args.urlParams, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+			assert.Equal(t, tt.wantRespBody.Message, got.Message)
+
 			if tt.wantErrors != nil {
 				gotErrors := []map[string]string{}
 				errJSON, _ := json.Marshal(got.Errors)
@@ -436,7 +1005,7 @@ func Test_PopularTags(t *testing.T) {
 				return handler.New(nil, &m, log)
 			},
 			wantRespBody: &response.Body{Status: http.StatusBadRequest},
-			wantErrors:   map[string]string{"Publication": "field is required, and must be a valid publications"},
+			wantErrors:   map[string]string{"Publication": "publication is required"},
 		},
 		{
 			name: "Should fail when receive error from database while fetching popular userTag",
@@ -483,6 +1052,14 @@ func Test_PopularTags(t *testing.T) {
 	}
 }
 
+// auditStoreMock accepts every audit record
+func auditStoreMock(t *testing.T) *mocks.AuditStore {
+	auditStoreMock := mocks.NewAuditStore(t)
+	auditStoreMock.EXPECT().Record(mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return auditStoreMock
+}
+
 // callEndpoint creates a request and make a http call
 func callEndpoint(t *testing.T, rawReq []byte, handlerFunc http.HandlerFunc, urlParams, queryParams map[string]string) (*response.Body, error) {
 	w := httptest.NewRecorder()
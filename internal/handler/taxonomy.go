@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"article-tag/internal/types"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Subtree returns the tag of the path followed by its descendants in the taxonomy of the publication
+func (app *Application) Subtree() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		req := types.SubtreeRequest{Publication: chi.URLParam(r, "publication"), TagID: chi.URLParam(r, "tagID")}
+
+		// validate request
+		err := app.validate.Struct(req)
+		if err != nil {
+			response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+			return
+		}
+
+		subtree, err := app.model.Tag.GetSubtree(ctx, req.Publication, req.TagID)
+		if err != nil {
+			app.logger.Error("error fetching subtree from db", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while fetching subtree")
+
+			return
+		}
+
+		res := types.SubtreeResponse{Tags: []types.TaxonomyTag{}}
+		for _, val := range subtree {
+			res.Tags = append(res.Tags, types.TaxonomyTag{TagID: val.TagID, TagName: val.TagName, ParentID: val.ParentID})
+		}
+
+		response.Success(w, res, "")
+	}
+}
+
+// PutTaxonomyTag adds the tag of the path to the taxonomy of the publication or moves it under another parent
+func (app *Application) PutTaxonomyTag() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req types.TaxonomyTagRequest
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			app.logger.Error("error decoding taxonomy tag request body", zap.Error(err))
+			response.BadRequest(w, "invalid request", nil)
+
+			return
+		}
+
+		// fetch params from urlParams, they take precedence over the body
+		req.Publication = chi.URLParam(r, "publication")
+		req.TagID = chi.URLParam(r, "tagID")
+
+		// validate request
+		err = app.validate.Struct(req)
+		if err != nil {
+			response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+			return
+		}
+
+		err = app.model.Tag.PutTaxonomyTag(ctx, req.Publication, req.TagID, req.TagName, req.ParentID)
+		if err != nil {
+			app.logger.Error("error storing taxonomy tag", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while storing taxonomy tag")
+
+			return
+		}
+
+		app.audit(r, auditActorAdmin, model.AuditActionTaxonomy, "", req.Publication, types.Tag{TagID: req.TagID, TagName: req.TagName},
+			fmt.Sprintf("parent set to %q", req.ParentID))
+
+		response.Success(w, types.TaxonomyTag{TagID: req.TagID, TagName: req.TagName, ParentID: req.ParentID}, "")
+	}
+}
@@ -0,0 +1,160 @@
+package handler_test
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/handler"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Subtree(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+	}{
+		{
+			name:      "success",
+			urlParams: map[string]string{"publication": "AK", "tagID": "1"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().GetSubtree(mock.Anything, "AK", "1").Return([]*model.TaxonomyTag{
+					{TagID: "1", TagName: "sports"},
+					{TagID: "2", TagName: "cricket", ParentID: "1"},
+				}, nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"tags": []interface{}{
+					map[string]interface{}{"tag_id": "1", "tag_name": "sports"},
+					map[string]interface{}{"tag_id": "2", "tag_name": "cricket", "parent_id": "1"},
+				},
+			}},
+		},
+		{
+			name:      "should fail when tag is not in the taxonomy",
+			urlParams: map[string]string{"publication": "AK", "tagID": "9"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().GetSubtree(mock.Anything, "AK", "9").
+					Return(nil, apperror.New(apperror.KindNotFound, apperror.CodeTagNotFound, "tag 9 does not exist")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusNotFound, Code: apperror.CodeTagNotFound},
+		},
+		{
+			name:      "should fail when tag id is not numeric",
+			urlParams: map[string]string{"publication": "AK", "tagID": "abc"},
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, nil, app.Subtree(), tt.urlParams, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+
+			if tt.wantRespBody.Code != "" {
+				assert.Equal(t, tt.wantRespBody.Code, got.Code)
+			}
+
+			if tt.wantRespBody.Data != nil {
+				assert.Equal(t, tt.wantRespBody.Data, got.Data)
+			}
+		})
+	}
+}
+
+func Test_PutTaxonomyTag(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		req          []byte
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+	}{
+		{
+			name: "success",
+			req:  []byte(`{"tag_name":"cricket","parent_id":"1"}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().PutTaxonomyTag(mock.Anything, "AK", "2", "cricket", "1").Return(nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock(t)}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"tag_id": "2", "tag_name": "cricket", "parent_id": "1",
+			}},
+		},
+		{
+			name: "should fail when tag name is missing",
+			req:  []byte(`{"parent_id":"1"}`),
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+		},
+		{
+			name: "should fail when parent is a descendant of the tag",
+			req:  []byte(`{"tag_name":"cricket","parent_id":"3"}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().PutTaxonomyTag(mock.Anything, "AK", "2", "cricket", "3").
+					Return(apperror.New(apperror.KindValidationFailed, apperror.CodeTagCycle, "tag 2 can not be a descendant of itself")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusUnprocessableEntity, Code: apperror.CodeTagCycle},
+		},
+		{
+			name: "should fail when received error in putTaxonomyTag call",
+			req:  []byte(`{"tag_name":"cricket"}`),
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().PutTaxonomyTag(mock.Anything, "AK", "2", "cricket", "").Return(errors.New("mock error")).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, tt.req, app.PutTaxonomyTag(), map[string]string{"publication": "AK", "tagID": "2"}, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+
+			if tt.wantRespBody.Code != "" {
+				assert.Equal(t, tt.wantRespBody.Code, got.Code)
+			}
+
+			if tt.wantRespBody.Data != nil {
+				assert.Equal(t, tt.wantRespBody.Data, got.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"article-tag/internal/types"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+func (app *Application) UserData() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		req := types.UserDataRequest{Username: chi.URLParam(r, "username")}
+
+		// validate request
+		err := app.validate.Struct(req)
+		if err != nil {
+			response.BadRequest(w, "", []response.InvalidParam{app.invalidParam(r, "username", "Username", "required", "")})
+
+			return
+		}
+
+		// fetch follows of every publication
+		userTags, err := app.model.Tag.GetAll(ctx, req.Username)
+		if err != nil {
+			app.logger.Error("error fetching user data from db", zap.Error(err), zap.String("username", req.Username))
+			response.Error(w, err, "error while fetching user data")
+
+			return
+		}
+
+		follows := []types.UserFollow{}
+		for _, val := range userTags {
+			follows = append(follows, types.UserFollow{
+				Publication: val.Publication,
+				TagID:       val.TagID,
+				TagName:     val.TagName,
+				CreatedAt:   val.CreatedAt,
+				DeletedAt:   val.DeletedAt,
+			})
+		}
+
+		// audit records holding the username, the export itself is recorded afterwards
+		records, err := app.model.Audit.GetAll(ctx, req.Username)
+		if err != nil {
+			app.logger.Error("error fetching user audit records from db", zap.Error(err), zap.String("username", req.Username))
+			response.Error(w, err, "error while fetching user data")
+
+			return
+		}
+
+		app.audit(r, auditActorAdmin, model.AuditActionUserData, req.Username, "", types.Tag{}, "")
+
+		response.Success(w, types.UserDataResponse{Username: req.Username, Follows: follows, Audit: auditEntries(records)}, "")
+	}
+}
+
+func (app *Application) EraseUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		req := types.UserDataRequest{Username: chi.URLParam(r, "username")}
+
+		// validate request
+		err := app.validate.Struct(req)
+		if err != nil {
+			response.BadRequest(w, "", []response.InvalidParam{app.invalidParam(r, "username", "Username", "required", "")})
+
+			return
+		}
+
+		// delete follows and decrement counters
+		erased, err := app.model.Tag.Erase(ctx, req.Username)
+		if err != nil {
+			app.logger.Error("error erasing user data", zap.Error(err), zap.String("username", req.Username),
+				zap.Int("erased", erased))
+			response.Error(w, err, "error while erasing user data")
+
+			return
+		}
+
+		// delete the audit records of the user, the ones of the tags keep a pseudonym
+		auditErased, err := app.model.Audit.Erase(ctx, req.Username)
+		if err != nil {
+			app.logger.Error("error erasing user audit records", zap.Error(err), zap.String("username", req.Username),
+				zap.Int("erased", auditErased))
+			response.Error(w, err, "error while erasing user data")
+
+			return
+		}
+
+		// keep a record of the erasure, retained until AUDIT_RETENTION as the proof of the erasure
+		record := newAuditRecord(r, auditActorAdmin, model.AuditActionErase)
+		record.Username = req.Username
+		record.Detail = fmt.Sprintf("erased %d follows and %d audit records", erased, auditErased)
+
+		// unlike other actions the erasure fails when it can not be recorded
+		err = app.model.Audit.Record(ctx, record)
+		if err != nil {
+			app.logger.Error("error recording erasure audit", zap.Error(err), zap.String("username", req.Username))
+			response.Error(w, err, "error while recording user erasure")
+
+			return
+		}
+
+		response.Success(w, types.EraseUserResponse{Username: req.Username, Erased: erased, AuditErased: auditErased}, "")
+	}
+}
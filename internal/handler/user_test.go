@@ -0,0 +1,186 @@
+package handler_test
+
+import (
+	"article-tag/internal/handler"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_UserData(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+	}{
+		{
+			name:      "success",
+			urlParams: map[string]string{"username": "Test"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().GetAll(mock.Anything, "Test").Return([]*model.UserTag{
+					{Publication: "AK", TagID: "1", TagName: "tag1", CreatedAt: "2023-01-01T00:00:00Z"},
+				}, nil)
+
+				auditStoreMock := auditStoreMock(t)
+				auditStoreMock.EXPECT().GetAll(mock.Anything, "Test").Return([]*model.AuditRecord{
+					{Actor: "Test", Action: model.AuditActionFollow, Username: "Test", Publication: "AK", TagID: "1"},
+				}, nil).Once()
+
+				m := model.Models{Tag: tagStoreMock, Audit: auditStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK},
+		},
+		{
+			name:      "should fail when username is empty",
+			urlParams: map[string]string{"username": ""},
+			mockDB: func() *handler.Application {
+				m := model.Models{}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest},
+		},
+		{
+			name:      "should fail when got error while fetching user data",
+			urlParams: map[string]string{"username": "Test"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().GetAll(mock.Anything, "Test").Return(nil, errors.New("db error"))
+
+				m := model.Models{Tag: tagStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while fetching user data"},
+		},
+		{
+			name:      "should fail when got error while fetching audit records",
+			urlParams: map[string]string{"username": "Test"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().GetAll(mock.Anything, "Test").Return([]*model.UserTag{}, nil)
+
+				auditStoreMock := mocks.NewAuditStore(t)
+				auditStoreMock.EXPECT().GetAll(mock.Anything, "Test").Return(nil, errors.New("db error")).Once()
+
+				m := model.Models{Tag: tagStoreMock, Audit: auditStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while fetching user data"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, nil, app.UserData(), tt.urlParams, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+			assert.Equal(t, tt.wantRespBody.Message, got.Message)
+		})
+	}
+}
+
+func Test_EraseUser(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+	}{
+		{
+			name:      "success",
+			urlParams: map[string]string{"username": "Test"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Erase(mock.Anything, "Test").Return(2, nil)
+
+				auditStoreMock := mocks.NewAuditStore(t)
+				erase := auditStoreMock.EXPECT().Erase(mock.Anything, "Test").Return(3, nil).Once()
+				auditStoreMock.EXPECT().Record(mock.Anything, mock.MatchedBy(func(r *model.AuditRecord) bool {
+					return r.Username == "Test" && r.Action == model.AuditActionErase && r.Detail == "erased 2 follows and 3 audit records"
+				})).Return(nil).NotBefore(erase)
+
+				m := model.Models{Tag: tagStoreMock, Audit: auditStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK},
+		},
+		{
+			name:      "should fail when got error while erasing user data",
+			urlParams: map[string]string{"username": "Test"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Erase(mock.Anything, "Test").Return(0, errors.New("db error"))
+
+				m := model.Models{Tag: tagStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while erasing user data"},
+		},
+		{
+			name:      "should fail when got error while erasing audit records",
+			urlParams: map[string]string{"username": "Test"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Erase(mock.Anything, "Test").Return(2, nil)
+
+				auditStoreMock := mocks.NewAuditStore(t)
+				auditStoreMock.EXPECT().Erase(mock.Anything, "Test").Return(1, errors.New("db error")).Once()
+
+				m := model.Models{Tag: tagStoreMock, Audit: auditStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while erasing user data"},
+		},
+		{
+			name:      "should fail when got error while recording audit",
+			urlParams: map[string]string{"username": "Test"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Erase(mock.Anything, "Test").Return(2, nil)
+
+				auditStoreMock := mocks.NewAuditStore(t)
+				auditStoreMock.EXPECT().Erase(mock.Anything, "Test").Return(3, nil).Once()
+				auditStoreMock.EXPECT().Record(mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+				m := model.Models{Tag: tagStoreMock, Audit: auditStoreMock}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while recording user erasure"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, nil, app.EraseUser(), tt.urlParams, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+			assert.Equal(t, tt.wantRespBody.Message, got.Message)
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"article-tag/internal/types"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// PutTag follows a single tag, the user and the tag are read from the path
+func (app *Application) PutTag() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req types.PutTagRequest
+
+		// validate request
+		err := app.validatePutTagRequest(w, r, &req)
+		if err != nil {
+			app.logger.Error("error validating put tag request", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		ctx, err = app.matchVersion(w, r)
+		if err != nil {
+			app.logger.Error("error matching follow set version", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		// store follow tag, following a tag again is a no-op, an alias is followed as its canonical tag
+		followed, err := app.model.Tag.Store(ctx, req.Username, req.Publication, req.TagName, req.TagID, req.Descendants)
+		if err != nil {
+			app.logger.Error("error while storing item", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while storing user tag")
+
+			return
+		}
+
+		app.audit(r, req.Username, model.AuditActionFollow, req.Username, req.Publication,
+			types.Tag{TagID: followed.TagID, TagName: followed.TagName}, "")
+
+		response.Success(w, types.Tag{TagID: followed.TagID, TagName: followed.TagName, Descendants: followed.IncludeDescendants}, "")
+	}
+}
+
+// ListTags returns the followed tags of the user of the path
+func (app *Application) ListTags() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req types.GetTagRequest
+
+		// validate request
+		err := app.validateListTagsRequest(w, r, &req)
+		if err != nil {
+			app.logger.Error("error validating list tags request", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		// version of the follow set, sent back as If-Match by the writes. It is read
+		// before the tags so a concurrent write can only make the ETag older than them
+		version, err := app.model.Tag.Version(ctx, req.Username, req.Publication)
+		if err != nil {
+			app.logger.Error("error fetching follow set version from db", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while fetching user tags")
+
+			return
+		}
+
+		// fetch tags using username and publication
+		userTags, err := app.model.Tag.Get(ctx, req.Username, req.Publication, req.Order)
+		if err != nil {
+			app.logger.Error("error fetching user tags from db", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while fetching user tags")
+
+			return
+		}
+
+		tags := []types.Tag{}
+		for _, val := range userTags {
+			tags = append(tags, types.Tag{
+				TagID:       val.TagID,
+				TagName:     val.TagName,
+				Descendants: val.IncludeDescendants,
+			})
+		}
+
+		implicit, err := app.implicitTags(ctx, req, userTags)
+		if err != nil {
+			app.logger.Error("error fetching implicit user tags from db", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while fetching user tags")
+
+			return
+		}
+
+		setVersionETag(w, version, implicit)
+		response.Success(w, types.GetTagResponse{Tags: tags, Version: version, Implicit: implicit}, "")
+	}
+}
+
+// DeleteTag unfollows a single tag, the request has no body so the tag name is not checked
+func (app *Application) DeleteTag() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req types.UserTagRequest
+
+		// validate request
+		err := app.validateUserTagRequest(w, r, &req)
+		if err != nil {
+			app.logger.Error("error validating delete tag request", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		ctx, err = app.matchVersion(w, r)
+		if err != nil {
+			app.logger.Error("error matching follow set version", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		// delete user tag
+		err = app.model.Tag.Delete(ctx, req.Username, req.Publication, req.TagID, "")
+		if err != nil {
+			app.logger.Error("error deleting user tags", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while deleting user followed tag")
+
+			return
+		}
+
+		app.audit(r, req.Username, model.AuditActionUnfollow, req.Username, req.Publication, types.Tag{TagID: req.TagID}, "")
+
+		response.Success(w, nil, "")
+	}
+}
+
+// RestoreTag follows again a single tag unfollowed within the undo window
+func (app *Application) RestoreTag() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req types.UserTagRequest
+
+		// validate request
+		err := app.validateUserTagRequest(w, r, &req)
+		if err != nil {
+			app.logger.Error("error validating restore tag request", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		ctx, err = app.matchVersion(w, r)
+		if err != nil {
+			app.logger.Error("error matching follow set version", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+
+			return
+		}
+
+		// restore unfollowed user tag
+		err = app.model.Tag.Restore(ctx, req.Username, req.Publication, req.TagID)
+		if err != nil {
+			app.logger.Error("error restoring user tags", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while restoring user unfollowed tag")
+
+			return
+		}
+
+		app.audit(r, req.Username, model.AuditActionUndo, req.Username, req.Publication, types.Tag{TagID: req.TagID}, "")
+
+		response.Success(w, nil, "")
+	}
+}
+
+// Leaderboard returns the materialized top tags of the publication with their rank deltas,
+// the tags are empty until the leaderboard is first refreshed
+func (app *Application) Leaderboard() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		req := types.GetPopularTagRequest{Publication: chi.URLParam(r, "publication")}
+
+		// validate request
+		err := app.validate.Struct(req)
+		if err != nil {
+			response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+			return
+		}
+
+		leaderboard, err := app.model.Tag.GetLeaderboard(ctx, req.Publication)
+		if err != nil {
+			app.logger.Error("error fetching leaderboard from db", zap.Error(err), zap.Field{Key: "request",
+				Type: zapcore.ReflectType, Interface: req})
+			response.Error(w, err, "error while fetching leaderboard")
+
+			return
+		}
+
+		res := types.LeaderboardResponse{Tags: []types.LeaderboardTag{}}
+		if leaderboard != nil {
+			res.UpdatedAt = leaderboard.UpdatedAt
+
+			for _, val := range leaderboard.Entries {
+				res.Tags = append(res.Tags, types.LeaderboardTag{
+					TagID:   val.TagID,
+					TagName: val.TagName,
+					Count:   val.TagCount,
+					Rank:    val.Rank,
+					Delta:   val.Delta,
+					New:     val.New,
+				})
+			}
+		}
+
+		response.Success(w, res, "")
+	}
+}
+
+func (app *Application) validatePutTagRequest(w http.ResponseWriter, r *http.Request, req *types.PutTagRequest) error {
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		app.logger.Error("error decoding put tag request body", zap.Error(err))
+		response.BadRequest(w, "invalid request", nil)
+
+		return err
+	}
+
+	// fetch params from urlParams, they take precedence over the body
+	req.Publication = chi.URLParam(r, "publication")
+	req.Username = chi.URLParam(r, "username")
+	req.TagID = chi.URLParam(r, "tagID")
+
+	err = app.validate.Struct(req)
+	if err != nil {
+		response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+		return err
+	}
+
+	return nil
+}
+
+func (app *Application) validateListTagsRequest(w http.ResponseWriter, r *http.Request, req *types.GetTagRequest) error {
+	var err error
+
+	req.Order = r.URL.Query().Get("order")
+	req.Include = r.URL.Query().Get("include")
+
+	// fetch params from urlParams
+	req.Publication = chi.URLParam(r, "publication")
+	req.Username = chi.URLParam(r, "username")
+
+	err = app.validate.Struct(req)
+	if err != nil {
+		response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+		return err
+	}
+
+	return nil
+}
+
+func (app *Application) validateUserTagRequest(w http.ResponseWriter, r *http.Request, req *types.UserTagRequest) error {
+	var err error
+
+	// fetch params from urlParams
+	req.Publication = chi.URLParam(r, "publication")
+	req.Username = chi.URLParam(r, "username")
+	req.TagID = chi.URLParam(r, "tagID")
+
+	err = app.validate.Struct(req)
+	if err != nil {
+		response.BadRequest(w, "", app.validationErrorBag(r, err.(validator.ValidationErrors)))
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,443 @@
+package handler_test
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/handler"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"article-tag/internal/response"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_PutTag(t *testing.T) {
+	log := testSuite()
+
+	path := map[string]string{"publication": "AK", "username": "Test", "tagID": "1"}
+
+	tests := []struct {
+		name         string
+		body         string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+		wantErrors   map[string]string
+	}{
+		{
+			name:      "success",
+			body:      `{"tag_name": "tag101"}`,
+			urlParams: path,
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "Test", "AK", "tag101", "1", false).Return(&model.UserTag{TagID: "1", TagName: "tag101"}, nil).Once()
+
+				m := model.Models{
+					Tag:   tagStoreMock,
+					Audit: auditStoreMock(t),
+				}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK},
+		},
+		{
+			name:      "success - tag is followed with its descendants",
+			body:      `{"tag_name": "tag101", "descendants": true}`,
+			urlParams: path,
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "Test", "AK", "tag101", "1", true).Return(&model.UserTag{TagID: "1", TagName: "tag101", IncludeDescendants: true}, nil).Once()
+
+				m := model.Models{
+					Tag:   tagStoreMock,
+					Audit: auditStoreMock(t),
+				}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK},
+		},
+		{
+			name:      "success - alias is followed as its canonical tag",
+			body:      `{"tag_name": "A.I."}`,
+			urlParams: map[string]string{"publication": "AK", "username": "Test", "tagID": "7"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "Test", "AK", "A.I.", "7", false).
+					Return(&model.UserTag{TagID: "5", TagName: "artificial intelligence"}, nil).Once()
+
+				auditStoreMock := mocks.NewAuditStore(t)
+				auditStoreMock.EXPECT().Record(mock.Anything, mock.MatchedBy(func(record *model.AuditRecord) bool {
+					return record.TagID == "5" && record.TagName == "artificial intelligence"
+				})).Return(nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock, Audit: auditStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"tag_id":   "5",
+				"tag_name": "artificial intelligence",
+			}},
+		},
+		{
+			name:      "the path takes precedence over the body",
+			body:      `{"username": "other", "tag_id": "2", "tag_name": "tag101"}`,
+			urlParams: path,
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, "Test", "AK", "tag101", "1", false).Return(&model.UserTag{TagID: "1", TagName: "tag101"}, nil).Once()
+
+				m := model.Models{
+					Tag:   tagStoreMock,
+					Audit: auditStoreMock(t),
+				}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK},
+		},
+		{
+			name:      "should fail when tag id is not numeric",
+			body:      `{"tag_name": "tag101"}`,
+			urlParams: map[string]string{"publication": "AK", "username": "Test", "tagID": "abc"},
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+			wantErrors:   map[string]string{"TagID": "tag_id must have a numeric format"},
+		},
+		{
+			name:      "should fail when tag name is missing",
+			body:      `{}`,
+			urlParams: path,
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+			wantErrors:   map[string]string{"TagName": "tag_name is required"},
+		},
+		{
+			name:      "Should fail when receive error from database while storing userTag",
+			body:      `{"tag_name": "tag101"}`,
+			urlParams: path,
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Store(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusInternalServerError, Message: "error while storing user tag", Code: "internal_error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, []byte(tt.body), app.PutTag(), tt.urlParams, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+			assert.Equal(t, tt.wantRespBody.Message, got.Message)
+			assert.Equal(t, tt.wantRespBody.Code, got.Code)
+			assertErrors(t, got, tt.wantErrors)
+
+			if tt.wantRespBody.Data != nil {
+				assert.Equal(t, tt.wantRespBody.Data, got.Data)
+			}
+		})
+	}
+}
+
+func Test_ListTags(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		queryParams  map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+		wantErrors   map[string]string
+	}{
+		{
+			name:      "success",
+			urlParams: map[string]string{"publication": "AK", "username": "Test"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				// the version is read first so a concurrent write can only make the ETag older
+				version := tagStoreMock.EXPECT().Version(mock.Anything, "Test", "AK").Return(int64(3), nil).Once()
+				tagStoreMock.EXPECT().Get(mock.Anything, "Test", "AK", "").Return([]*model.UserTag{{TagID: "1", TagName: "tag101"}}, nil).Once().NotBefore(version)
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"tags":    []interface{}{map[string]interface{}{"tag_id": "1", "tag_name": "tag101"}},
+				"version": float64(3),
+			}},
+		},
+		{
+			name:        "success - implicitly followed tags are included",
+			urlParams:   map[string]string{"publication": "AK", "username": "Test"},
+			queryParams: map[string]string{"include": "implicit"},
+			mockDB: func() *handler.Application {
+				followed := []*model.UserTag{{TagID: "1", TagName: "sports", IncludeDescendants: true}}
+
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Get(mock.Anything, "Test", "AK", "").Return(followed, nil).Once()
+				tagStoreMock.EXPECT().Version(mock.Anything, "Test", "AK").Return(int64(3), nil).Once()
+				tagStoreMock.EXPECT().ImplicitTags(mock.Anything, "AK", followed).Return([]*model.UserTag{{TagID: "2", TagName: "cricket"}}, nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"tags":     []interface{}{map[string]interface{}{"tag_id": "1", "tag_name": "sports", "descendants": true}},
+				"implicit": []interface{}{map[string]interface{}{"tag_id": "2", "tag_name": "cricket"}},
+				"version":  float64(3),
+			}},
+		},
+		{
+			name:        "should fail when include is invalid",
+			urlParams:   map[string]string{"publication": "AK", "username": "Test"},
+			queryParams: map[string]string{"include": "all"},
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+		},
+		{
+			name:      "should fail when username is missing",
+			urlParams: map[string]string{"publication": "AK"},
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+			wantErrors:   map[string]string{"Username": "username is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, nil, app.ListTags(), tt.urlParams, tt.queryParams)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+
+			if tt.wantRespBody.Data != nil {
+				assert.Equal(t, tt.wantRespBody.Data, got.Data)
+			}
+
+			assertErrors(t, got, tt.wantErrors)
+		})
+	}
+}
+
+func Test_DeleteTag(t *testing.T) {
+	log := testSuite()
+
+	path := map[string]string{"publication": "AK", "username": "Test", "tagID": "1"}
+
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+		wantErrors   map[string]string
+	}{
+		{
+			name:      "success without checking the tag name",
+			urlParams: path,
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Delete(mock.Anything, "Test", "AK", "1", "").Return(nil).Once()
+
+				m := model.Models{
+					Tag:   tagStoreMock,
+					Audit: auditStoreMock(t),
+				}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK},
+		},
+		{
+			name:      "should fail when publication is invalid",
+			urlParams: map[string]string{"publication": "XX", "username": "Test", "tagID": "1"},
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+			wantErrors:   map[string]string{"Publication": "publication must be one of RS AK ST BC"},
+		},
+		{
+			name:      "Should fail with not found when tag is not followed",
+			urlParams: path,
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Delete(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(apperror.New(apperror.KindNotFound, apperror.CodeTagNotFollowed, "tag is not followed"))
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusNotFound, Message: "tag is not followed", Code: apperror.CodeTagNotFollowed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, nil, app.DeleteTag(), tt.urlParams, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+			assert.Equal(t, tt.wantRespBody.Message, got.Message)
+			assert.Equal(t, tt.wantRespBody.Code, got.Code)
+			assertErrors(t, got, tt.wantErrors)
+		})
+	}
+}
+
+func Test_RestoreTag(t *testing.T) {
+	log := testSuite()
+
+	path := map[string]string{"publication": "AK", "username": "Test", "tagID": "1"}
+
+	tests := []struct {
+		name         string
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+	}{
+		{
+			name: "success",
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Restore(mock.Anything, "Test", "AK", "1").Return(nil).Once()
+
+				m := model.Models{
+					Tag:   tagStoreMock,
+					Audit: auditStoreMock(t),
+				}
+
+				return handler.New(nil, &m, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK},
+		},
+		{
+			name: "Should fail with conflict when the undo window expired",
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().Restore(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(apperror.New(apperror.KindConflict, apperror.CodeUndoWindowExpired, "undo window expired"))
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusConflict, Message: "undo window expired", Code: apperror.CodeUndoWindowExpired},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, nil, app.RestoreTag(), path, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+			assert.Equal(t, tt.wantRespBody.Message, got.Message)
+			assert.Equal(t, tt.wantRespBody.Code, got.Code)
+		})
+	}
+}
+
+// assertErrors checks the legacy field errors of the response body
+func assertErrors(t *testing.T, got *response.Body, want map[string]string) {
+	if want == nil {
+		return
+	}
+
+	gotErrors := []map[string]string{}
+	errJSON, _ := json.Marshal(got.Errors)
+	json.Unmarshal(errJSON, &gotErrors)
+
+	assert.NotEmpty(t, gotErrors)
+	for k, v := range want {
+		assert.Equal(t, v, gotErrors[0][k])
+	}
+}
+
+func Test_Leaderboard(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name         string
+		urlParams    map[string]string
+		mockDB       func() *handler.Application
+		wantRespBody *response.Body
+	}{
+		{
+			name:      "success",
+			urlParams: map[string]string{"publication": "AK"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().GetLeaderboard(mock.Anything, "AK").Return(&model.Leaderboard{
+					Entries: []model.LeaderboardEntry{
+						{TagID: "1", TagName: "tag101", TagCount: 5, Rank: 1, Delta: 2},
+						{TagID: "2", TagName: "tag102", TagCount: 3, Rank: 2, New: true},
+					},
+					UpdatedAt: "2023-08-01T00:00:00Z",
+				}, nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{
+				"tags": []interface{}{
+					map[string]interface{}{"tag_id": "1", "tag_name": "tag101", "count": float64(5), "rank": float64(1), "delta": float64(2), "new": false},
+					map[string]interface{}{"tag_id": "2", "tag_name": "tag102", "count": float64(3), "rank": float64(2), "delta": float64(0), "new": true},
+				},
+				"updated_at": "2023-08-01T00:00:00Z",
+			}},
+		},
+		{
+			name:      "empty until the leaderboard is refreshed",
+			urlParams: map[string]string{"publication": "AK"},
+			mockDB: func() *handler.Application {
+				tagStoreMock := mocks.NewUserTagStore(t)
+				tagStoreMock.EXPECT().GetLeaderboard(mock.Anything, "AK").Return(nil, nil).Once()
+
+				return handler.New(nil, &model.Models{Tag: tagStoreMock}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusOK, Data: map[string]interface{}{"tags": []interface{}{}}},
+		},
+		{
+			name:      "should fail when publication is invalid",
+			urlParams: map[string]string{"publication": "XX"},
+			mockDB: func() *handler.Application {
+				return handler.New(nil, &model.Models{}, log)
+			},
+			wantRespBody: &response.Body{Status: http.StatusBadRequest, Code: "invalid_request"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := tt.mockDB()
+
+			got, gotErr := callEndpoint(t, nil, app.Leaderboard(), tt.urlParams, nil)
+
+			assert.Nil(t, gotErr)
+			assert.Equal(t, tt.wantRespBody.Status, got.Status)
+			assert.Equal(t, tt.wantRespBody.Code, got.Code)
+
+			if tt.wantRespBody.Data != nil {
+				assert.Equal(t, tt.wantRespBody.Data, got.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package i18n
+
+import (
+	"embed"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	ut "github.com/go-playground/universal-translator"
+)
+
+// catalogs are the default translations, one json file per locale
+//
+//go:embed catalog/*.json
+var catalogs embed.FS
+
+// keyInvalid is the message of a rule without translation
+const keyInvalid = "invalid"
+
+// Translator of the validation messages, english is the fallback locale
+type Translator struct {
+	uni *ut.UniversalTranslator
+}
+
+// New loads the embedded catalogs, then the catalogs of dir when it is set.
+// Translations of dir must set "override": true to replace an embedded one.
+func New(dir string) (*Translator, error) {
+	uni := ut.New(en.New(), en.New(), es.New())
+
+	files, err := fs.Glob(catalogs, "catalog/*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range files {
+		f, err := catalogs.Open(name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = uni.ImportByReader(ut.FormatJSON, f)
+		f.Close()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dir != "" {
+		if err := uni.Import(ut.FormatJSON, dir); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Translator{uni: uni}, nil
+}
+
+// Locale returns the translator of the preferred language of the Accept-Language header
+func (t *Translator) Locale(acceptLanguage string) ut.Translator {
+	trans, _ := t.uni.FindTranslator(languages(acceptLanguage)...)
+
+	return trans
+}
+
+// Message of the failed rule, a translation of the field and rule (e.g. tags.required)
+// is preferred over the one of the rule
+func Message(trans ut.Translator, field, rule, param string) string {
+	if msg, err := trans.T(field+"."+rule, field, param); err == nil {
+		return msg
+	}
+
+	if msg, err := trans.T(rule, field, param); err == nil {
+		return msg
+	}
+
+	msg, _ := trans.T(keyInvalid, field)
+
+	return msg
+}
+
+// language of the Accept-Language header with its quality
+type language struct {
+	tag     string
+	quality float64
+}
+
+// languages returns the locales of the Accept-Language header by preference,
+// a region (e.g. es-MX) is followed by its base language
+func languages(acceptLanguage string) []string {
+	var langs []language
+
+	for _, val := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(val), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = v
+			}
+		}
+
+		langs = append(langs, language{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].quality > langs[j].quality
+	})
+
+	locales := []string{}
+	for _, val := range langs {
+		locale := strings.ReplaceAll(val.tag, "-", "_")
+		locales = append(locales, locale)
+
+		if base, _, ok := strings.Cut(locale, "_"); ok {
+			locales = append(locales, base)
+		}
+	}
+
+	return locales
+}
@@ -0,0 +1,96 @@
+package i18n_test
+
+import (
+	"article-tag/internal/i18n"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Message(t *testing.T) {
+	translator, err := i18n.New("")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		field          string
+		rule           string
+		param          string
+		want           string
+	}{
+		{
+			name:  "english is the default",
+			field: "username",
+			rule:  "required",
+			want:  "username is required",
+		},
+		{
+			name:           "message with param",
+			acceptLanguage: "en-US,en;q=0.9",
+			field:          "publication",
+			rule:           "oneof",
+			param:          "RS AK ST BC",
+			want:           "publication must be one of RS AK ST BC",
+		},
+		{
+			name:           "preferred language by quality",
+			acceptLanguage: "en;q=0.5, es-MX;q=0.8",
+			field:          "tag_id",
+			rule:           "numeric",
+			want:           "tag_id debe tener un formato numérico",
+		},
+		{
+			name:           "message of the field and rule",
+			acceptLanguage: "es",
+			field:          "tags",
+			rule:           "required",
+			want:           "se requiere al menos una etiqueta",
+		},
+		{
+			name:           "unsupported language falls back to english",
+			acceptLanguage: "fr-FR",
+			field:          "username",
+			rule:           "required",
+			want:           "username is required",
+		},
+		{
+			name:  "rule without translation",
+			field: "username",
+			rule:  "alpha",
+			want:  "username is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trans := translator.Locale(tt.acceptLanguage)
+
+			assert.Equal(t, tt.want, i18n.Message(trans, tt.field, tt.rule, tt.param))
+		})
+	}
+}
+
+func Test_NewCatalogDir(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "en.json"), []byte(`[
+		{"locale": "en", "key": "required", "trans": "{0} must be set", "override": true}
+	]`), 0o644)
+	assert.Nil(t, err)
+
+	translator, err := i18n.New(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, "username must be set", i18n.Message(translator.Locale("en"), "username", "required", ""))
+
+	// a translation can not be replaced without override
+	err = os.WriteFile(filepath.Join(dir, "en.json"), []byte(`[
+		{"locale": "en", "key": "required", "trans": "{0} must be set"}
+	]`), 0o644)
+	assert.Nil(t, err)
+
+	_, err = i18n.New(dir)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,84 @@
+package leaderboard
+
+import (
+	"article-tag/internal/config"
+	"article-tag/internal/constant"
+	"article-tag/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Refresher materializes the leaderboard of every publication periodically
+type Refresher struct {
+	store        model.UserTagStore
+	publications []string
+	size         int
+	interval     time.Duration
+	logger       *zap.Logger
+}
+
+// NewRefresher reads the configuration from the environment:
+//
+//	LEADERBOARD_SIZE the number of tags of a leaderboard
+//	LEADERBOARD_INTERVAL the time between two refreshes, 0 disables the periodic refresh
+func NewRefresher(store model.UserTagStore, logger *zap.Logger) *Refresher {
+	return &Refresher{
+		store:        store,
+		publications: constant.AllowdedPublications,
+		size:         config.Int("LEADERBOARD_SIZE", constant.LeaderboardSize),
+		interval:     config.Duration("LEADERBOARD_INTERVAL", constant.LeaderboardInterval),
+		logger:       logger,
+	}
+}
+
+// Run refreshes the leaderboards right away and then every interval, until the context is done.
+// Failures are only logged, the next refresh retries them.
+func (r *Refresher) Run(ctx context.Context) {
+	if r.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RefreshAll(ctx); err != nil {
+			r.logger.Error("error refreshing leaderboards", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RefreshAll refreshes the leaderboard of every publication, a failed publication does not stop the others
+func (r *Refresher) RefreshAll(ctx context.Context) error {
+	var errs []error
+
+	for _, val := range r.publications {
+		if err := r.Refresh(ctx, val); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Refresh the leaderboard of the publication
+func (r *Refresher) Refresh(ctx context.Context, publication string) error {
+	leaderboard, err := r.store.RefreshLeaderboard(ctx, publication, r.size)
+	if err != nil {
+		return fmt.Errorf("refreshing leaderboard of %s: %w", publication, err)
+	}
+
+	r.logger.Debug("leaderboard refreshed", zap.String("publication", publication), zap.Int("tags", len(leaderboard.Entries)))
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package leaderboard_test
+
+import (
+	"article-tag/internal/leaderboard"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func Test_RefreshAll(t *testing.T) {
+	t.Setenv("LEADERBOARD_SIZE", "10")
+
+	tagStoreMock := mocks.NewUserTagStore(t)
+	tagStoreMock.EXPECT().RefreshLeaderboard(mock.Anything, "AK", 10).Return(&model.Leaderboard{}, nil).Once()
+	tagStoreMock.EXPECT().RefreshLeaderboard(mock.Anything, "RS", 10).Return(nil, errors.New("db error")).Once()
+	tagStoreMock.EXPECT().RefreshLeaderboard(mock.Anything, "BC", 10).Return(&model.Leaderboard{}, nil).Once()
+	tagStoreMock.EXPECT().RefreshLeaderboard(mock.Anything, "ST", 10).Return(&model.Leaderboard{}, nil).Once()
+
+	err := leaderboard.NewRefresher(tagStoreMock, zap.NewNop()).RefreshAll(context.Background())
+
+	assert.EqualError(t, err, "refreshing leaderboard of RS: db error")
+}
+
+func Test_Run(t *testing.T) {
+	t.Run("refreshes until the context is done", func(t *testing.T) {
+		t.Setenv("LEADERBOARD_INTERVAL", "10ms")
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		refreshed := make(chan struct{}, 8)
+
+		tagStoreMock := mocks.NewUserTagStore(t)
+		tagStoreMock.EXPECT().RefreshLeaderboard(mock.Anything, mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, publication string, size int) (*model.Leaderboard, error) {
+				if publication == "ST" {
+					refreshed <- struct{}{}
+				}
+
+				return &model.Leaderboard{}, nil
+			})
+
+		done := make(chan struct{})
+		go func() {
+			leaderboard.NewRefresher(tagStoreMock, zap.NewNop()).Run(ctx)
+			close(done)
+		}()
+
+		// the first refresh is right away, the second after the interval
+		<-refreshed
+		<-refreshed
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("refresher did not stop")
+		}
+	})
+
+	t.Run("a zero interval disables the refresh", func(t *testing.T) {
+		t.Setenv("LEADERBOARD_INTERVAL", "0s")
+
+		leaderboard.NewRefresher(mocks.NewUserTagStore(t), zap.NewNop()).Run(context.Background())
+	})
+}
@@ -0,0 +1,204 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// Key of the item recording the applied migrations
+const (
+	recordPK = "SCHEMA"
+	recordSK = "MIGRATIONS"
+)
+
+// ErrSchemaBehind is returned by Check when migrations are pending
+var ErrSchemaBehind = errors.New("schema version is behind")
+
+// Client
+type Client interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+}
+
+// Migration is a single versioned schema change, Up must be safe to run again
+// when a previous run failed before the version was recorded
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context) error
+}
+
+// Applied
+type Applied struct {
+	Version     int
+	Description string
+	AppliedAt   string
+}
+
+// record is the item stored in the table, holding the current version
+type record struct {
+	PK      string
+	SK      string
+	Version int
+	Applied []Applied
+}
+
+// Status
+type Status struct {
+	Current int
+	Latest  int
+	Applied []Applied
+	Pending []Migration
+}
+
+// Runner applies the migrations in order of their version
+type Runner struct {
+	db         Client
+	table      string
+	migrations []Migration
+	logger     *zap.Logger
+}
+
+// NewRunner, migrations must be sorted by version starting at 1
+func NewRunner(db Client, table string, migrations []Migration, logger *zap.Logger) *Runner {
+	return &Runner{db: db, table: table, migrations: migrations, logger: logger}
+}
+
+// Status returns the applied and pending migrations
+func (r *Runner) Status(ctx context.Context) (*Status, error) {
+	rec, err := r.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := Status{Current: rec.Version, Applied: rec.Applied, Pending: []Migration{}}
+	for _, m := range r.migrations {
+		if m.Version > rec.Version {
+			status.Pending = append(status.Pending, m)
+		}
+
+		status.Latest = m.Version
+	}
+
+	return &status, nil
+}
+
+// Check returns ErrSchemaBehind when the schema has pending migrations
+func (r *Runner) Check(ctx context.Context) error {
+	status, err := r.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(status.Pending) > 0 {
+		return fmt.Errorf("%w: current version %d, latest version %d", ErrSchemaBehind, status.Current, status.Latest)
+	}
+
+	return nil
+}
+
+// Up applies every pending migration and records the version after each one
+func (r *Runner) Up(ctx context.Context) (*Status, error) {
+	status, err := r.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := record{PK: recordPK, SK: recordSK, Version: status.Current, Applied: status.Applied}
+
+	for _, m := range status.Pending {
+		r.logger.Info("applying migration", zap.Int("version", m.Version), zap.String("description", m.Description))
+
+		if err := m.Up(ctx); err != nil {
+			return nil, fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Description, err)
+		}
+
+		prev := rec.Version
+		rec.Version = m.Version
+		rec.Applied = append(rec.Applied, Applied{
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   time.Now().UTC().Format(time.RFC3339),
+		})
+
+		if err := r.write(ctx, &rec, prev); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.Status(ctx)
+}
+
+// read returns the recorded version, zero when the table or the record does not exist yet
+func (r *Runner) read(ctx context.Context) (*record, error) {
+	rec := record{}
+
+	_, err := r.db.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(r.table)})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return &rec, nil
+		}
+
+		return nil, err
+	}
+
+	res, err := r.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.table),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: recordPK},
+			"SK": &types.AttributeValueMemberS{Value: recordSK},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Item == nil {
+		return &rec, nil
+	}
+
+	err = attributevalue.UnmarshalMap(res.Item, &rec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
+
+// write stores the record, conditioned on the previous version so that
+// two runners applying the same migration can not both record it
+func (r *Runner) write(ctx context.Context, rec *record, prev int) error {
+	item, err := attributevalue.MarshalMap(rec)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.table),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(PK) OR Version = :v1"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberN{Value: fmt.Sprint(prev)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error recording migration %d: %w", rec.Version, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package migration_test
+
+import (
+	"article-tag/internal/migration"
+	"article-tag/internal/mocks"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func testSuite() *zap.Logger {
+	rawJSON := []byte(`{
+		"level": "debug",
+		"encoding": "json",
+		"outputPaths": ["stdout", "/tmp/logs"],
+		"errorOutputPaths": ["stderr"],
+		"encoderConfig": {
+		  "messageKey": "message",
+		  "levelKey": "level",
+		  "levelEncoder": "lowercase"
+		}
+	  }`)
+
+	var cfg zap.Config
+	if err := json.Unmarshal(rawJSON, &cfg); err != nil {
+		panic(err)
+	}
+
+	return zap.Must(cfg.Build())
+}
+
+// recordAt returns the migrations item at the version
+func recordAt(version string) *dynamodb.GetItemOutput {
+	return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+		"PK":      &types.AttributeValueMemberS{Value: "SCHEMA"},
+		"SK":      &types.AttributeValueMemberS{Value: "MIGRATIONS"},
+		"Version": &types.AttributeValueMemberN{Value: version},
+	}}
+}
+
+func Test_Up(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name        string
+		mockDB      func() *mocks.Client
+		wantApplied []int
+		wantErr     bool
+	}{
+		{
+			name: "success - all migrations are applied when table does not exist",
+			mockDB: func() *mocks.Client {
+				m := mocks.NewClient(t)
+				m.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(nil, &types.ResourceNotFoundException{}).Once()
+				m.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["Version"].(*types.AttributeValueMemberN).Value == "1"
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				m.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["Version"].(*types.AttributeValueMemberN).Value == "2" &&
+						in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == "1"
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+				// final status
+				m.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(&dynamodb.DescribeTableOutput{}, nil).Once()
+				m.EXPECT().GetItem(mock.Anything, mock.Anything).Return(recordAt("2"), nil).Once()
+
+				return m
+			},
+			wantApplied: []int{1, 2},
+		},
+		{
+			name: "success - only pending migrations are applied",
+			mockDB: func() *mocks.Client {
+				m := mocks.NewClient(t)
+				m.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(&dynamodb.DescribeTableOutput{}, nil)
+				m.EXPECT().GetItem(mock.Anything, mock.Anything).Return(recordAt("1"), nil).Once()
+				m.EXPECT().PutItem(mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				m.EXPECT().GetItem(mock.Anything, mock.Anything).Return(recordAt("2"), nil).Once()
+
+				return m
+			},
+			wantApplied: []int{2},
+		},
+		{
+			name: "should fail when migration fails",
+			mockDB: func() *mocks.Client {
+				m := mocks.NewClient(t)
+				m.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(&dynamodb.DescribeTableOutput{}, nil)
+				m.EXPECT().GetItem(mock.Anything, mock.Anything).Return(recordAt("1"), nil).Once()
+
+				return m
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied := []int{}
+			up := func(version int, err error) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					if err == nil {
+						applied = append(applied, version)
+					}
+
+					return err
+				}
+			}
+
+			var failing error
+			if tt.wantErr {
+				failing = errors.New("mock error")
+			}
+
+			runner := migration.NewRunner(tt.mockDB(), "table", []migration.Migration{
+				{Version: 1, Description: "first", Up: up(1, nil)},
+				{Version: 2, Description: "second", Up: up(2, failing)},
+			}, log)
+
+			status, err := runner.Up(context.TODO())
+
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantApplied, applied)
+			assert.Equal(t, 2, status.Current)
+			assert.Empty(t, status.Pending)
+		})
+	}
+}
+
+func Test_Check(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name    string
+		version string
+		wantErr error
+	}{
+		{
+			name:    "success - schema is up to date",
+			version: "1",
+		},
+		{
+			name:    "should fail when schema is behind",
+			version: "0",
+			wantErr: migration.ErrSchemaBehind,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewClient(t)
+			m.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(&dynamodb.DescribeTableOutput{}, nil)
+			m.EXPECT().GetItem(mock.Anything, mock.Anything).Return(recordAt(tt.version), nil)
+
+			runner := migration.NewRunner(m, "table", []migration.Migration{
+				{Version: 1, Description: "first", Up: func(ctx context.Context) error { return nil }},
+			}, log)
+
+			err := runner.Check(context.TODO())
+
+			if tt.wantErr == nil {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
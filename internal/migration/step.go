@@ -0,0 +1,167 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// pollInterval used while waiting for the table to become active
+var pollInterval = 2 * time.Second
+
+// TableDescriber
+type TableDescriber interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+}
+
+// WaitForTable waits until the table and all of its global secondary indexes are active
+func WaitForTable(ctx context.Context, db TableDescriber, table string) error {
+	for {
+		res, err := db.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(table)})
+		if err != nil {
+			return err
+		}
+
+		active := res.Table.TableStatus == types.TableStatusActive
+		for _, val := range res.Table.GlobalSecondaryIndexes {
+			if val.IndexStatus != types.IndexStatusActive {
+				active = false
+			}
+		}
+
+		if active {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// AddGlobalSecondaryIndex creates the index when it does not exist and waits until it is backfilled.
+// attributes must define the key attributes of the index.
+func AddGlobalSecondaryIndex(ctx context.Context, db Client, table string, attributes []types.AttributeDefinition, index types.CreateGlobalSecondaryIndexAction) error {
+	res, err := db.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(table)})
+	if err != nil {
+		return err
+	}
+
+	for _, val := range res.Table.GlobalSecondaryIndexes {
+		if aws.ToString(val.IndexName) == aws.ToString(index.IndexName) {
+			return WaitForTable(ctx, db, table)
+		}
+	}
+
+	_, err = db.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:                   aws.String(table),
+		AttributeDefinitions:        attributes,
+		GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{Create: &index}},
+	})
+	if err != nil {
+		return err
+	}
+
+	return WaitForTable(ctx, db, table)
+}
+
+// Backfill scans the items matching the filter expression and applies the update returned by fn,
+// items for which fn returns nil are skipped. The update should be conditional so that rows changed
+// concurrently by the application are not overwritten.
+func Backfill(ctx context.Context, db Client, table string, scan *dynamodb.ScanInput, fn func(item map[string]types.AttributeValue) *dynamodb.UpdateItemInput) error {
+	scanInput := *scan
+	scanInput.TableName = aws.String(table)
+
+	for {
+		res, err := db.Scan(ctx, &scanInput)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range res.Items {
+			update := fn(item)
+			if update == nil {
+				continue
+			}
+
+			update.TableName = aws.String(table)
+
+			_, err := db.UpdateItem(ctx, update)
+
+			// row was changed since it was scanned
+			var condErr *types.ConditionalCheckFailedException
+			if errors.As(err, &condErr) {
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if res.LastEvaluatedKey == nil {
+			return nil
+		}
+
+		scanInput.ExclusiveStartKey = res.LastEvaluatedKey
+	}
+}
+
+// RenameItemType moves the items whose partition key starts with from to a partition key starting with to,
+// e.g. renaming the PUB# counter items to COUNTER#. The new item is written before the old one is deleted.
+func RenameItemType(ctx context.Context, db Client, table, from, to string) error {
+	scanInput := dynamodb.ScanInput{
+		TableName:        aws.String(table),
+		FilterExpression: aws.String("begins_with(PK, :v1)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberS{Value: from},
+		},
+	}
+
+	for {
+		res, err := db.Scan(ctx, &scanInput)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range res.Items {
+			pk, ok := item["PK"].(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+
+			renamed := map[string]types.AttributeValue{}
+			for k, v := range item {
+				renamed[k] = v
+			}
+
+			renamed["PK"] = &types.AttributeValueMemberS{Value: to + strings.TrimPrefix(pk.Value, from)}
+
+			_, err := db.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String(table), Item: renamed})
+			if err != nil {
+				return err
+			}
+
+			_, err = db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+				TableName: aws.String(table),
+				Key:       map[string]types.AttributeValue{"PK": item["PK"], "SK": item["SK"]},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if res.LastEvaluatedKey == nil {
+			return nil
+		}
+
+		scanInput.ExclusiveStartKey = res.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,101 @@
+package migration_test
+
+import (
+	"article-tag/internal/migration"
+	"article-tag/internal/mocks"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_AddGlobalSecondaryIndex(t *testing.T) {
+	index := types.CreateGlobalSecondaryIndexAction{IndexName: aws.String("NewIndex")}
+	active := &dynamodb.DescribeTableOutput{Table: &types.TableDescription{
+		TableStatus: types.TableStatusActive,
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+			{IndexName: aws.String("NewIndex"), IndexStatus: types.IndexStatusActive},
+		},
+	}}
+
+	tests := []struct {
+		name   string
+		mockDB func() *mocks.Client
+	}{
+		{
+			name: "index is created when missing",
+			mockDB: func() *mocks.Client {
+				m := mocks.NewClient(t)
+				m.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(&dynamodb.DescribeTableOutput{
+					Table: &types.TableDescription{TableStatus: types.TableStatusActive},
+				}, nil).Once()
+				m.EXPECT().UpdateTable(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateTableInput) bool {
+					return aws.ToString(in.GlobalSecondaryIndexUpdates[0].Create.IndexName) == "NewIndex"
+				})).Return(&dynamodb.UpdateTableOutput{}, nil).Once()
+				m.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(active, nil).Once()
+
+				return m
+			},
+		},
+		{
+			name: "existing index is not created again",
+			mockDB: func() *mocks.Client {
+				m := mocks.NewClient(t)
+				m.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(active, nil).Twice()
+
+				return m
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := migration.AddGlobalSecondaryIndex(context.TODO(), tt.mockDB(), "table", nil, index)
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func Test_Backfill(t *testing.T) {
+	m := mocks.NewClient(t)
+	m.EXPECT().Scan(mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+		{"PK": &types.AttributeValueMemberS{Value: "user1#AK"}},
+		{"PK": &types.AttributeValueMemberS{Value: "PUB#AK"}},
+	}}, nil).Once()
+	m.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+		return in.Key["PK"].(*types.AttributeValueMemberS).Value == "user1#AK" && aws.ToString(in.TableName) == "table"
+	})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	// only the follow row is updated
+	err := migration.Backfill(context.TODO(), m, "table", &dynamodb.ScanInput{}, func(item map[string]types.AttributeValue) *dynamodb.UpdateItemInput {
+		if item["PK"].(*types.AttributeValueMemberS).Value == "PUB#AK" {
+			return nil
+		}
+
+		return &dynamodb.UpdateItemInput{Key: map[string]types.AttributeValue{"PK": item["PK"]}}
+	})
+
+	assert.Nil(t, err)
+}
+
+func Test_RenameItemType(t *testing.T) {
+	m := mocks.NewClient(t)
+	m.EXPECT().Scan(mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+		{"PK": &types.AttributeValueMemberS{Value: "PUB#AK"}, "SK": &types.AttributeValueMemberS{Value: "1"}},
+	}}, nil).Once()
+	m.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		return in.Item["PK"].(*types.AttributeValueMemberS).Value == "COUNTER#AK"
+	})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	m.EXPECT().DeleteItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.DeleteItemInput) bool {
+		return in.Key["PK"].(*types.AttributeValueMemberS).Value == "PUB#AK"
+	})).Return(&dynamodb.DeleteItemOutput{}, nil).Once()
+
+	err := migration.RenameItemType(context.TODO(), m, "table", "PUB#", "COUNTER#")
+
+	assert.Nil(t, err)
+}
@@ -0,0 +1,243 @@
+// Code generated by mockery v2.30.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "article-tag/internal/model"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditStore is an autogenerated mock type for the AuditStore type
+type AuditStore struct {
+	mock.Mock
+}
+
+type AuditStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AuditStore) EXPECT() *AuditStore_Expecter {
+	return &AuditStore_Expecter{mock: &_m.Mock}
+}
+
+// Erase provides a mock function with given fields: ctx, username
+func (_m *AuditStore) Erase(ctx context.Context, username string) (int, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditStore_Erase_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Erase'
+type AuditStore_Erase_Call struct {
+	*mock.Call
+}
+
+// Erase is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+func (_e *AuditStore_Expecter) Erase(ctx interface{}, username interface{}) *AuditStore_Erase_Call {
+	return &AuditStore_Erase_Call{Call: _e.mock.On("Erase", ctx, username)}
+}
+
+func (_c *AuditStore_Erase_Call) Run(run func(ctx context.Context, username string)) *AuditStore_Erase_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AuditStore_Erase_Call) Return(_a0 int, _a1 error) *AuditStore_Erase_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditStore_Erase_Call) RunAndReturn(run func(context.Context, string) (int, error)) *AuditStore_Erase_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAll provides a mock function with given fields: ctx, username
+func (_m *AuditStore) GetAll(ctx context.Context, username string) ([]*model.AuditRecord, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 []*model.AuditRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.AuditRecord, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.AuditRecord); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuditRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditStore_GetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAll'
+type AuditStore_GetAll_Call struct {
+	*mock.Call
+}
+
+// GetAll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+func (_e *AuditStore_Expecter) GetAll(ctx interface{}, username interface{}) *AuditStore_GetAll_Call {
+	return &AuditStore_GetAll_Call{Call: _e.mock.On("GetAll", ctx, username)}
+}
+
+func (_c *AuditStore_GetAll_Call) Run(run func(ctx context.Context, username string)) *AuditStore_GetAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AuditStore_GetAll_Call) Return(_a0 []*model.AuditRecord, _a1 error) *AuditStore_GetAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditStore_GetAll_Call) RunAndReturn(run func(context.Context, string) ([]*model.AuditRecord, error)) *AuditStore_GetAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Query provides a mock function with given fields: ctx, filter
+func (_m *AuditStore) Query(ctx context.Context, filter model.AuditFilter) ([]*model.AuditRecord, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*model.AuditRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.AuditFilter) ([]*model.AuditRecord, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.AuditFilter) []*model.AuditRecord); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuditRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.AuditFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditStore_Query_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Query'
+type AuditStore_Query_Call struct {
+	*mock.Call
+}
+
+// Query is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter model.AuditFilter
+func (_e *AuditStore_Expecter) Query(ctx interface{}, filter interface{}) *AuditStore_Query_Call {
+	return &AuditStore_Query_Call{Call: _e.mock.On("Query", ctx, filter)}
+}
+
+func (_c *AuditStore_Query_Call) Run(run func(ctx context.Context, filter model.AuditFilter)) *AuditStore_Query_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.AuditFilter))
+	})
+	return _c
+}
+
+func (_c *AuditStore_Query_Call) Return(_a0 []*model.AuditRecord, _a1 error) *AuditStore_Query_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditStore_Query_Call) RunAndReturn(run func(context.Context, model.AuditFilter) ([]*model.AuditRecord, error)) *AuditStore_Query_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Record provides a mock function with given fields: ctx, record
+func (_m *AuditStore) Record(ctx context.Context, record *model.AuditRecord) error {
+	ret := _m.Called(ctx, record)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AuditRecord) error); ok {
+		r0 = rf(ctx, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AuditStore_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type AuditStore_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - ctx context.Context
+//   - record *model.AuditRecord
+func (_e *AuditStore_Expecter) Record(ctx interface{}, record interface{}) *AuditStore_Record_Call {
+	return &AuditStore_Record_Call{Call: _e.mock.On("Record", ctx, record)}
+}
+
+func (_c *AuditStore_Record_Call) Run(run func(ctx context.Context, record *model.AuditRecord)) *AuditStore_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*model.AuditRecord))
+	})
+	return _c
+}
+
+func (_c *AuditStore_Record_Call) Return(_a0 error) *AuditStore_Record_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AuditStore_Record_Call) RunAndReturn(run func(context.Context, *model.AuditRecord) error) *AuditStore_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditStore creates a new instance of AuditStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditStore {
+	mock := &AuditStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -22,6 +22,146 @@ func (_m *DynamoAPI) EXPECT() *DynamoAPI_Expecter {
 	return &DynamoAPI_Expecter{mock: &_m.Mock}
 }
 
+// BatchGetItem provides a mock function with given fields: ctx, params, optFns
+func (_m *DynamoAPI) BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.BatchGetItemOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) *dynamodb.BatchGetItemOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.BatchGetItemOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DynamoAPI_BatchGetItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BatchGetItem'
+type DynamoAPI_BatchGetItem_Call struct {
+	*mock.Call
+}
+
+// BatchGetItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.BatchGetItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *DynamoAPI_Expecter) BatchGetItem(ctx interface{}, params interface{}, optFns ...interface{}) *DynamoAPI_BatchGetItem_Call {
+	return &DynamoAPI_BatchGetItem_Call{Call: _e.mock.On("BatchGetItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *DynamoAPI_BatchGetItem_Call) Run(run func(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options))) *DynamoAPI_BatchGetItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.BatchGetItemInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DynamoAPI_BatchGetItem_Call) Return(_a0 *dynamodb.BatchGetItemOutput, _a1 error) *DynamoAPI_BatchGetItem_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DynamoAPI_BatchGetItem_Call) RunAndReturn(run func(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)) *DynamoAPI_BatchGetItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BatchWriteItem provides a mock function with given fields: ctx, params, optFns
+func (_m *DynamoAPI) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.BatchWriteItemOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.BatchWriteItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.BatchWriteItemInput, ...func(*dynamodb.Options)) *dynamodb.BatchWriteItemOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.BatchWriteItemOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.BatchWriteItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DynamoAPI_BatchWriteItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BatchWriteItem'
+type DynamoAPI_BatchWriteItem_Call struct {
+	*mock.Call
+}
+
+// BatchWriteItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.BatchWriteItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *DynamoAPI_Expecter) BatchWriteItem(ctx interface{}, params interface{}, optFns ...interface{}) *DynamoAPI_BatchWriteItem_Call {
+	return &DynamoAPI_BatchWriteItem_Call{Call: _e.mock.On("BatchWriteItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *DynamoAPI_BatchWriteItem_Call) Run(run func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options))) *DynamoAPI_BatchWriteItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.BatchWriteItemInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DynamoAPI_BatchWriteItem_Call) Return(_a0 *dynamodb.BatchWriteItemOutput, _a1 error) *DynamoAPI_BatchWriteItem_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DynamoAPI_BatchWriteItem_Call) RunAndReturn(run func(context.Context, *dynamodb.BatchWriteItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)) *DynamoAPI_BatchWriteItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateTable provides a mock function with given fields: ctx, params, optFns
 func (_m *DynamoAPI) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
 	_va := make([]interface{}, len(optFns))
@@ -232,6 +372,76 @@ func (_c *DynamoAPI_DescribeTable_Call) RunAndReturn(run func(context.Context, *
 	return _c
 }
 
+// DescribeTimeToLive provides a mock function with given fields: ctx, params, optFns
+func (_m *DynamoAPI) DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.DescribeTimeToLiveOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTimeToLiveInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTimeToLiveInput, ...func(*dynamodb.Options)) *dynamodb.DescribeTimeToLiveOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.DescribeTimeToLiveOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.DescribeTimeToLiveInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DynamoAPI_DescribeTimeToLive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeTimeToLive'
+type DynamoAPI_DescribeTimeToLive_Call struct {
+	*mock.Call
+}
+
+// DescribeTimeToLive is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.DescribeTimeToLiveInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *DynamoAPI_Expecter) DescribeTimeToLive(ctx interface{}, params interface{}, optFns ...interface{}) *DynamoAPI_DescribeTimeToLive_Call {
+	return &DynamoAPI_DescribeTimeToLive_Call{Call: _e.mock.On("DescribeTimeToLive",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *DynamoAPI_DescribeTimeToLive_Call) Run(run func(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options))) *DynamoAPI_DescribeTimeToLive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.DescribeTimeToLiveInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DynamoAPI_DescribeTimeToLive_Call) Return(_a0 *dynamodb.DescribeTimeToLiveOutput, _a1 error) *DynamoAPI_DescribeTimeToLive_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DynamoAPI_DescribeTimeToLive_Call) RunAndReturn(run func(context.Context, *dynamodb.DescribeTimeToLiveInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)) *DynamoAPI_DescribeTimeToLive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetItem provides a mock function with given fields: ctx, params, optFns
 func (_m *DynamoAPI) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
 	_va := make([]interface{}, len(optFns))
@@ -442,6 +652,146 @@ func (_c *DynamoAPI_Query_Call) RunAndReturn(run func(context.Context, *dynamodb
 	return _c
 }
 
+// Scan provides a mock function with given fields: ctx, params, optFns
+func (_m *DynamoAPI) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.ScanOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.ScanInput, ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.ScanInput, ...func(*dynamodb.Options)) *dynamodb.ScanOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.ScanOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.ScanInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DynamoAPI_Scan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Scan'
+type DynamoAPI_Scan_Call struct {
+	*mock.Call
+}
+
+// Scan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.ScanInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *DynamoAPI_Expecter) Scan(ctx interface{}, params interface{}, optFns ...interface{}) *DynamoAPI_Scan_Call {
+	return &DynamoAPI_Scan_Call{Call: _e.mock.On("Scan",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *DynamoAPI_Scan_Call) Run(run func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options))) *DynamoAPI_Scan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.ScanInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DynamoAPI_Scan_Call) Return(_a0 *dynamodb.ScanOutput, _a1 error) *DynamoAPI_Scan_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DynamoAPI_Scan_Call) RunAndReturn(run func(context.Context, *dynamodb.ScanInput, ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)) *DynamoAPI_Scan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TransactWriteItems provides a mock function with given fields: ctx, params, optFns
+func (_m *DynamoAPI) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.TransactWriteItemsOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.TransactWriteItemsInput, ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.TransactWriteItemsInput, ...func(*dynamodb.Options)) *dynamodb.TransactWriteItemsOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.TransactWriteItemsOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.TransactWriteItemsInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DynamoAPI_TransactWriteItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransactWriteItems'
+type DynamoAPI_TransactWriteItems_Call struct {
+	*mock.Call
+}
+
+// TransactWriteItems is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.TransactWriteItemsInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *DynamoAPI_Expecter) TransactWriteItems(ctx interface{}, params interface{}, optFns ...interface{}) *DynamoAPI_TransactWriteItems_Call {
+	return &DynamoAPI_TransactWriteItems_Call{Call: _e.mock.On("TransactWriteItems",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *DynamoAPI_TransactWriteItems_Call) Run(run func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options))) *DynamoAPI_TransactWriteItems_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.TransactWriteItemsInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DynamoAPI_TransactWriteItems_Call) Return(_a0 *dynamodb.TransactWriteItemsOutput, _a1 error) *DynamoAPI_TransactWriteItems_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DynamoAPI_TransactWriteItems_Call) RunAndReturn(run func(context.Context, *dynamodb.TransactWriteItemsInput, ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)) *DynamoAPI_TransactWriteItems_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateItem provides a mock function with given fields: ctx, params, optFns
 func (_m *DynamoAPI) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
 	_va := make([]interface{}, len(optFns))
@@ -512,6 +862,76 @@ func (_c *DynamoAPI_UpdateItem_Call) RunAndReturn(run func(context.Context, *dyn
 	return _c
 }
 
+// UpdateTimeToLive provides a mock function with given fields: ctx, params, optFns
+func (_m *DynamoAPI) UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.UpdateTimeToLiveOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateTimeToLiveInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateTimeToLiveInput, ...func(*dynamodb.Options)) *dynamodb.UpdateTimeToLiveOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.UpdateTimeToLiveOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.UpdateTimeToLiveInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DynamoAPI_UpdateTimeToLive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTimeToLive'
+type DynamoAPI_UpdateTimeToLive_Call struct {
+	*mock.Call
+}
+
+// UpdateTimeToLive is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.UpdateTimeToLiveInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *DynamoAPI_Expecter) UpdateTimeToLive(ctx interface{}, params interface{}, optFns ...interface{}) *DynamoAPI_UpdateTimeToLive_Call {
+	return &DynamoAPI_UpdateTimeToLive_Call{Call: _e.mock.On("UpdateTimeToLive",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *DynamoAPI_UpdateTimeToLive_Call) Run(run func(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options))) *DynamoAPI_UpdateTimeToLive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.UpdateTimeToLiveInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *DynamoAPI_UpdateTimeToLive_Call) Return(_a0 *dynamodb.UpdateTimeToLiveOutput, _a1 error) *DynamoAPI_UpdateTimeToLive_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DynamoAPI_UpdateTimeToLive_Call) RunAndReturn(run func(context.Context, *dynamodb.UpdateTimeToLiveInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)) *DynamoAPI_UpdateTimeToLive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewDynamoAPI creates a new instance of DynamoAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewDynamoAPI(t interface {
@@ -0,0 +1,1017 @@
+// Code generated by mockery v2.30.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MigrationAPI is an autogenerated mock type for the migrationAPI type
+type MigrationAPI struct {
+	mock.Mock
+}
+
+type MigrationAPI_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MigrationAPI) EXPECT() *MigrationAPI_Expecter {
+	return &MigrationAPI_Expecter{mock: &_m.Mock}
+}
+
+// BatchGetItem provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.BatchGetItemOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) *dynamodb.BatchGetItemOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.BatchGetItemOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_BatchGetItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BatchGetItem'
+type MigrationAPI_BatchGetItem_Call struct {
+	*mock.Call
+}
+
+// BatchGetItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.BatchGetItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) BatchGetItem(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_BatchGetItem_Call {
+	return &MigrationAPI_BatchGetItem_Call{Call: _e.mock.On("BatchGetItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_BatchGetItem_Call) Run(run func(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_BatchGetItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.BatchGetItemInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_BatchGetItem_Call) Return(_a0 *dynamodb.BatchGetItemOutput, _a1 error) *MigrationAPI_BatchGetItem_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_BatchGetItem_Call) RunAndReturn(run func(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)) *MigrationAPI_BatchGetItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BatchWriteItem provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.BatchWriteItemOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.BatchWriteItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.BatchWriteItemInput, ...func(*dynamodb.Options)) *dynamodb.BatchWriteItemOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.BatchWriteItemOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.BatchWriteItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_BatchWriteItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BatchWriteItem'
+type MigrationAPI_BatchWriteItem_Call struct {
+	*mock.Call
+}
+
+// BatchWriteItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.BatchWriteItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) BatchWriteItem(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_BatchWriteItem_Call {
+	return &MigrationAPI_BatchWriteItem_Call{Call: _e.mock.On("BatchWriteItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_BatchWriteItem_Call) Run(run func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_BatchWriteItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.BatchWriteItemInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_BatchWriteItem_Call) Return(_a0 *dynamodb.BatchWriteItemOutput, _a1 error) *MigrationAPI_BatchWriteItem_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_BatchWriteItem_Call) RunAndReturn(run func(context.Context, *dynamodb.BatchWriteItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)) *MigrationAPI_BatchWriteItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTable provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.CreateTableOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.CreateTableInput, ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.CreateTableInput, ...func(*dynamodb.Options)) *dynamodb.CreateTableOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.CreateTableOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.CreateTableInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_CreateTable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTable'
+type MigrationAPI_CreateTable_Call struct {
+	*mock.Call
+}
+
+// CreateTable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.CreateTableInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) CreateTable(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_CreateTable_Call {
+	return &MigrationAPI_CreateTable_Call{Call: _e.mock.On("CreateTable",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_CreateTable_Call) Run(run func(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_CreateTable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.CreateTableInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_CreateTable_Call) Return(_a0 *dynamodb.CreateTableOutput, _a1 error) *MigrationAPI_CreateTable_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_CreateTable_Call) RunAndReturn(run func(context.Context, *dynamodb.CreateTableInput, ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)) *MigrationAPI_CreateTable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteItem provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.DeleteItemOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options)) *dynamodb.DeleteItemOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.DeleteItemOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_DeleteItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteItem'
+type MigrationAPI_DeleteItem_Call struct {
+	*mock.Call
+}
+
+// DeleteItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.DeleteItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) DeleteItem(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_DeleteItem_Call {
+	return &MigrationAPI_DeleteItem_Call{Call: _e.mock.On("DeleteItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_DeleteItem_Call) Run(run func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_DeleteItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.DeleteItemInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_DeleteItem_Call) Return(_a0 *dynamodb.DeleteItemOutput, _a1 error) *MigrationAPI_DeleteItem_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_DeleteItem_Call) RunAndReturn(run func(context.Context, *dynamodb.DeleteItemInput, ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)) *MigrationAPI_DeleteItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeTable provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.DescribeTableOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) *dynamodb.DescribeTableOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.DescribeTableOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_DescribeTable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeTable'
+type MigrationAPI_DescribeTable_Call struct {
+	*mock.Call
+}
+
+// DescribeTable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.DescribeTableInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) DescribeTable(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_DescribeTable_Call {
+	return &MigrationAPI_DescribeTable_Call{Call: _e.mock.On("DescribeTable",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_DescribeTable_Call) Run(run func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_DescribeTable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.DescribeTableInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_DescribeTable_Call) Return(_a0 *dynamodb.DescribeTableOutput, _a1 error) *MigrationAPI_DescribeTable_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_DescribeTable_Call) RunAndReturn(run func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)) *MigrationAPI_DescribeTable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeTimeToLive provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.DescribeTimeToLiveOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTimeToLiveInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTimeToLiveInput, ...func(*dynamodb.Options)) *dynamodb.DescribeTimeToLiveOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.DescribeTimeToLiveOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.DescribeTimeToLiveInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_DescribeTimeToLive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeTimeToLive'
+type MigrationAPI_DescribeTimeToLive_Call struct {
+	*mock.Call
+}
+
+// DescribeTimeToLive is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.DescribeTimeToLiveInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) DescribeTimeToLive(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_DescribeTimeToLive_Call {
+	return &MigrationAPI_DescribeTimeToLive_Call{Call: _e.mock.On("DescribeTimeToLive",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_DescribeTimeToLive_Call) Run(run func(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_DescribeTimeToLive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.DescribeTimeToLiveInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_DescribeTimeToLive_Call) Return(_a0 *dynamodb.DescribeTimeToLiveOutput, _a1 error) *MigrationAPI_DescribeTimeToLive_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_DescribeTimeToLive_Call) RunAndReturn(run func(context.Context, *dynamodb.DescribeTimeToLiveInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)) *MigrationAPI_DescribeTimeToLive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetItem provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.GetItemOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.GetItemInput, ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.GetItemInput, ...func(*dynamodb.Options)) *dynamodb.GetItemOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.GetItemOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.GetItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_GetItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetItem'
+type MigrationAPI_GetItem_Call struct {
+	*mock.Call
+}
+
+// GetItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.GetItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) GetItem(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_GetItem_Call {
+	return &MigrationAPI_GetItem_Call{Call: _e.mock.On("GetItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_GetItem_Call) Run(run func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_GetItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.GetItemInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_GetItem_Call) Return(_a0 *dynamodb.GetItemOutput, _a1 error) *MigrationAPI_GetItem_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_GetItem_Call) RunAndReturn(run func(context.Context, *dynamodb.GetItemInput, ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)) *MigrationAPI_GetItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutItem provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.PutItemOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) *dynamodb.PutItemOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.PutItemOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_PutItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutItem'
+type MigrationAPI_PutItem_Call struct {
+	*mock.Call
+}
+
+// PutItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.PutItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) PutItem(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_PutItem_Call {
+	return &MigrationAPI_PutItem_Call{Call: _e.mock.On("PutItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_PutItem_Call) Run(run func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_PutItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.PutItemInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_PutItem_Call) Return(_a0 *dynamodb.PutItemOutput, _a1 error) *MigrationAPI_PutItem_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_PutItem_Call) RunAndReturn(run func(context.Context, *dynamodb.PutItemInput, ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)) *MigrationAPI_PutItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Query provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.QueryOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options)) *dynamodb.QueryOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.QueryOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_Query_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Query'
+type MigrationAPI_Query_Call struct {
+	*mock.Call
+}
+
+// Query is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.QueryInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) Query(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_Query_Call {
+	return &MigrationAPI_Query_Call{Call: _e.mock.On("Query",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_Query_Call) Run(run func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_Query_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.QueryInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_Query_Call) Return(_a0 *dynamodb.QueryOutput, _a1 error) *MigrationAPI_Query_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_Query_Call) RunAndReturn(run func(context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)) *MigrationAPI_Query_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Scan provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.ScanOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.ScanInput, ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.ScanInput, ...func(*dynamodb.Options)) *dynamodb.ScanOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.ScanOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.ScanInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_Scan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Scan'
+type MigrationAPI_Scan_Call struct {
+	*mock.Call
+}
+
+// Scan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.ScanInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) Scan(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_Scan_Call {
+	return &MigrationAPI_Scan_Call{Call: _e.mock.On("Scan",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_Scan_Call) Run(run func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_Scan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.ScanInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_Scan_Call) Return(_a0 *dynamodb.ScanOutput, _a1 error) *MigrationAPI_Scan_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_Scan_Call) RunAndReturn(run func(context.Context, *dynamodb.ScanInput, ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)) *MigrationAPI_Scan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TransactWriteItems provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.TransactWriteItemsOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.TransactWriteItemsInput, ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.TransactWriteItemsInput, ...func(*dynamodb.Options)) *dynamodb.TransactWriteItemsOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.TransactWriteItemsOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.TransactWriteItemsInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_TransactWriteItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TransactWriteItems'
+type MigrationAPI_TransactWriteItems_Call struct {
+	*mock.Call
+}
+
+// TransactWriteItems is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.TransactWriteItemsInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) TransactWriteItems(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_TransactWriteItems_Call {
+	return &MigrationAPI_TransactWriteItems_Call{Call: _e.mock.On("TransactWriteItems",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_TransactWriteItems_Call) Run(run func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_TransactWriteItems_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.TransactWriteItemsInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_TransactWriteItems_Call) Return(_a0 *dynamodb.TransactWriteItemsOutput, _a1 error) *MigrationAPI_TransactWriteItems_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_TransactWriteItems_Call) RunAndReturn(run func(context.Context, *dynamodb.TransactWriteItemsInput, ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)) *MigrationAPI_TransactWriteItems_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateItem provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.UpdateItemOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options)) *dynamodb.UpdateItemOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.UpdateItemOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_UpdateItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateItem'
+type MigrationAPI_UpdateItem_Call struct {
+	*mock.Call
+}
+
+// UpdateItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.UpdateItemInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) UpdateItem(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_UpdateItem_Call {
+	return &MigrationAPI_UpdateItem_Call{Call: _e.mock.On("UpdateItem",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_UpdateItem_Call) Run(run func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_UpdateItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.UpdateItemInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_UpdateItem_Call) Return(_a0 *dynamodb.UpdateItemOutput, _a1 error) *MigrationAPI_UpdateItem_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_UpdateItem_Call) RunAndReturn(run func(context.Context, *dynamodb.UpdateItemInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)) *MigrationAPI_UpdateItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTable provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.UpdateTableOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateTableInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateTableInput, ...func(*dynamodb.Options)) *dynamodb.UpdateTableOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.UpdateTableOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.UpdateTableInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_UpdateTable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTable'
+type MigrationAPI_UpdateTable_Call struct {
+	*mock.Call
+}
+
+// UpdateTable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.UpdateTableInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) UpdateTable(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_UpdateTable_Call {
+	return &MigrationAPI_UpdateTable_Call{Call: _e.mock.On("UpdateTable",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_UpdateTable_Call) Run(run func(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_UpdateTable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.UpdateTableInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_UpdateTable_Call) Return(_a0 *dynamodb.UpdateTableOutput, _a1 error) *MigrationAPI_UpdateTable_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_UpdateTable_Call) RunAndReturn(run func(context.Context, *dynamodb.UpdateTableInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)) *MigrationAPI_UpdateTable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTimeToLive provides a mock function with given fields: ctx, params, optFns
+func (_m *MigrationAPI) UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.UpdateTimeToLiveOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateTimeToLiveInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateTimeToLiveInput, ...func(*dynamodb.Options)) *dynamodb.UpdateTimeToLiveOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.UpdateTimeToLiveOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.UpdateTimeToLiveInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrationAPI_UpdateTimeToLive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTimeToLive'
+type MigrationAPI_UpdateTimeToLive_Call struct {
+	*mock.Call
+}
+
+// UpdateTimeToLive is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.UpdateTimeToLiveInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *MigrationAPI_Expecter) UpdateTimeToLive(ctx interface{}, params interface{}, optFns ...interface{}) *MigrationAPI_UpdateTimeToLive_Call {
+	return &MigrationAPI_UpdateTimeToLive_Call{Call: _e.mock.On("UpdateTimeToLive",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *MigrationAPI_UpdateTimeToLive_Call) Run(run func(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options))) *MigrationAPI_UpdateTimeToLive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.UpdateTimeToLiveInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MigrationAPI_UpdateTimeToLive_Call) Return(_a0 *dynamodb.UpdateTimeToLiveOutput, _a1 error) *MigrationAPI_UpdateTimeToLive_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MigrationAPI_UpdateTimeToLive_Call) RunAndReturn(run func(context.Context, *dynamodb.UpdateTimeToLiveInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)) *MigrationAPI_UpdateTimeToLive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMigrationAPI creates a new instance of MigrationAPI. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMigrationAPI(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MigrationAPI {
+	mock := &MigrationAPI{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,108 @@
+// Code generated by mockery v2.30.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TableDescriber is an autogenerated mock type for the TableDescriber type
+type TableDescriber struct {
+	mock.Mock
+}
+
+type TableDescriber_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TableDescriber) EXPECT() *TableDescriber_Expecter {
+	return &TableDescriber_Expecter{mock: &_m.Mock}
+}
+
+// DescribeTable provides a mock function with given fields: ctx, params, optFns
+func (_m *TableDescriber) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.DescribeTableOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) *dynamodb.DescribeTableOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.DescribeTableOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TableDescriber_DescribeTable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeTable'
+type TableDescriber_DescribeTable_Call struct {
+	*mock.Call
+}
+
+// DescribeTable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.DescribeTableInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *TableDescriber_Expecter) DescribeTable(ctx interface{}, params interface{}, optFns ...interface{}) *TableDescriber_DescribeTable_Call {
+	return &TableDescriber_DescribeTable_Call{Call: _e.mock.On("DescribeTable",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *TableDescriber_DescribeTable_Call) Run(run func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options))) *TableDescriber_DescribeTable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.DescribeTableInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TableDescriber_DescribeTable_Call) Return(_a0 *dynamodb.DescribeTableOutput, _a1 error) *TableDescriber_DescribeTable_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TableDescriber_DescribeTable_Call) RunAndReturn(run func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)) *TableDescriber_DescribeTable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTableDescriber creates a new instance of TableDescriber. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTableDescriber(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TableDescriber {
+	mock := &TableDescriber{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,177 @@
+// Code generated by mockery v2.30.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TableUpdater is an autogenerated mock type for the TableUpdater type
+type TableUpdater struct {
+	mock.Mock
+}
+
+type TableUpdater_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TableUpdater) EXPECT() *TableUpdater_Expecter {
+	return &TableUpdater_Expecter{mock: &_m.Mock}
+}
+
+// DescribeTable provides a mock function with given fields: ctx, params, optFns
+func (_m *TableUpdater) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.DescribeTableOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) *dynamodb.DescribeTableOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.DescribeTableOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TableUpdater_DescribeTable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeTable'
+type TableUpdater_DescribeTable_Call struct {
+	*mock.Call
+}
+
+// DescribeTable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.DescribeTableInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *TableUpdater_Expecter) DescribeTable(ctx interface{}, params interface{}, optFns ...interface{}) *TableUpdater_DescribeTable_Call {
+	return &TableUpdater_DescribeTable_Call{Call: _e.mock.On("DescribeTable",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *TableUpdater_DescribeTable_Call) Run(run func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options))) *TableUpdater_DescribeTable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.DescribeTableInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TableUpdater_DescribeTable_Call) Return(_a0 *dynamodb.DescribeTableOutput, _a1 error) *TableUpdater_DescribeTable_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TableUpdater_DescribeTable_Call) RunAndReturn(run func(context.Context, *dynamodb.DescribeTableInput, ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)) *TableUpdater_DescribeTable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTable provides a mock function with given fields: ctx, params, optFns
+func (_m *TableUpdater) UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error) {
+	_va := make([]interface{}, len(optFns))
+	for _i := range optFns {
+		_va[_i] = optFns[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, params)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *dynamodb.UpdateTableOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateTableInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)); ok {
+		return rf(ctx, params, optFns...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dynamodb.UpdateTableInput, ...func(*dynamodb.Options)) *dynamodb.UpdateTableOutput); ok {
+		r0 = rf(ctx, params, optFns...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dynamodb.UpdateTableOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dynamodb.UpdateTableInput, ...func(*dynamodb.Options)) error); ok {
+		r1 = rf(ctx, params, optFns...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TableUpdater_UpdateTable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTable'
+type TableUpdater_UpdateTable_Call struct {
+	*mock.Call
+}
+
+// UpdateTable is a helper method to define mock.On call
+//   - ctx context.Context
+//   - params *dynamodb.UpdateTableInput
+//   - optFns ...func(*dynamodb.Options)
+func (_e *TableUpdater_Expecter) UpdateTable(ctx interface{}, params interface{}, optFns ...interface{}) *TableUpdater_UpdateTable_Call {
+	return &TableUpdater_UpdateTable_Call{Call: _e.mock.On("UpdateTable",
+		append([]interface{}{ctx, params}, optFns...)...)}
+}
+
+func (_c *TableUpdater_UpdateTable_Call) Run(run func(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options))) *TableUpdater_UpdateTable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]func(*dynamodb.Options), len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(func(*dynamodb.Options))
+			}
+		}
+		run(args[0].(context.Context), args[1].(*dynamodb.UpdateTableInput), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *TableUpdater_UpdateTable_Call) Return(_a0 *dynamodb.UpdateTableOutput, _a1 error) *TableUpdater_UpdateTable_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TableUpdater_UpdateTable_Call) RunAndReturn(run func(context.Context, *dynamodb.UpdateTableInput, ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)) *TableUpdater_UpdateTable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTableUpdater creates a new instance of TableUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTableUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TableUpdater {
+	mock := &TableUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -22,6 +22,61 @@ func (_m *UserTagStore) EXPECT() *UserTagStore_Expecter {
 	return &UserTagStore_Expecter{mock: &_m.Mock}
 }
 
+// BatchStore provides a mock function with given fields: ctx, items
+func (_m *UserTagStore) BatchStore(ctx context.Context, items []*model.UserTag) ([]*model.BatchReject, error) {
+	ret := _m.Called(ctx, items)
+
+	var r0 []*model.BatchReject
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.UserTag) ([]*model.BatchReject, error)); ok {
+		return rf(ctx, items)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.UserTag) []*model.BatchReject); ok {
+		r0 = rf(ctx, items)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.BatchReject)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*model.UserTag) error); ok {
+		r1 = rf(ctx, items)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_BatchStore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BatchStore'
+type UserTagStore_BatchStore_Call struct {
+	*mock.Call
+}
+
+// BatchStore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - items []*model.UserTag
+func (_e *UserTagStore_Expecter) BatchStore(ctx interface{}, items interface{}) *UserTagStore_BatchStore_Call {
+	return &UserTagStore_BatchStore_Call{Call: _e.mock.On("BatchStore", ctx, items)}
+}
+
+func (_c *UserTagStore_BatchStore_Call) Run(run func(ctx context.Context, items []*model.UserTag)) *UserTagStore_BatchStore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*model.UserTag))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_BatchStore_Call) Return(_a0 []*model.BatchReject, _a1 error) *UserTagStore_BatchStore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_BatchStore_Call) RunAndReturn(run func(context.Context, []*model.UserTag) ([]*model.BatchReject, error)) *UserTagStore_BatchStore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateTable provides a mock function with given fields: ctx
 func (_m *UserTagStore) CreateTable(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -64,6 +119,48 @@ func (_c *UserTagStore_CreateTable_Call) RunAndReturn(run func(context.Context)
 	return _c
 }
 
+// DefaultFollowLimit provides a mock function with given fields: publication
+func (_m *UserTagStore) DefaultFollowLimit(publication string) int {
+	ret := _m.Called(publication)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(publication)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// UserTagStore_DefaultFollowLimit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DefaultFollowLimit'
+type UserTagStore_DefaultFollowLimit_Call struct {
+	*mock.Call
+}
+
+// DefaultFollowLimit is a helper method to define mock.On call
+//   - publication string
+func (_e *UserTagStore_Expecter) DefaultFollowLimit(publication interface{}) *UserTagStore_DefaultFollowLimit_Call {
+	return &UserTagStore_DefaultFollowLimit_Call{Call: _e.mock.On("DefaultFollowLimit", publication)}
+}
+
+func (_c *UserTagStore_DefaultFollowLimit_Call) Run(run func(publication string)) *UserTagStore_DefaultFollowLimit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_DefaultFollowLimit_Call) Return(_a0 int) *UserTagStore_DefaultFollowLimit_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserTagStore_DefaultFollowLimit_Call) RunAndReturn(run func(string) int) *UserTagStore_DefaultFollowLimit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Delete provides a mock function with given fields: ctx, username, publication, tagID, tagName
 func (_m *UserTagStore) Delete(ctx context.Context, username string, publication string, tagID string, tagName string) error {
 	ret := _m.Called(ctx, username, publication, tagID, tagName)
@@ -152,6 +249,101 @@ func (_c *UserTagStore_DescribeTable_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// EnableTTL provides a mock function with given fields: ctx
+func (_m *UserTagStore) EnableTTL(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserTagStore_EnableTTL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableTTL'
+type UserTagStore_EnableTTL_Call struct {
+	*mock.Call
+}
+
+// EnableTTL is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *UserTagStore_Expecter) EnableTTL(ctx interface{}) *UserTagStore_EnableTTL_Call {
+	return &UserTagStore_EnableTTL_Call{Call: _e.mock.On("EnableTTL", ctx)}
+}
+
+func (_c *UserTagStore_EnableTTL_Call) Run(run func(ctx context.Context)) *UserTagStore_EnableTTL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_EnableTTL_Call) Return(_a0 error) *UserTagStore_EnableTTL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserTagStore_EnableTTL_Call) RunAndReturn(run func(context.Context) error) *UserTagStore_EnableTTL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Erase provides a mock function with given fields: ctx, username
+func (_m *UserTagStore) Erase(ctx context.Context, username string) (int, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_Erase_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Erase'
+type UserTagStore_Erase_Call struct {
+	*mock.Call
+}
+
+// Erase is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+func (_e *UserTagStore_Expecter) Erase(ctx interface{}, username interface{}) *UserTagStore_Erase_Call {
+	return &UserTagStore_Erase_Call{Call: _e.mock.On("Erase", ctx, username)}
+}
+
+func (_c *UserTagStore_Erase_Call) Run(run func(ctx context.Context, username string)) *UserTagStore_Erase_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_Erase_Call) Return(_a0 int, _a1 error) *UserTagStore_Erase_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_Erase_Call) RunAndReturn(run func(context.Context, string) (int, error)) *UserTagStore_Erase_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Get provides a mock function with given fields: ctx, username, publication, order
 func (_m *UserTagStore) Get(ctx context.Context, username string, publication string, order string) ([]*model.UserTag, error) {
 	ret := _m.Called(ctx, username, publication, order)
@@ -209,25 +401,25 @@ func (_c *UserTagStore_Get_Call) RunAndReturn(run func(context.Context, string,
 	return _c
 }
 
-// GetPopularTags provides a mock function with given fields: ctx, username, publication
-func (_m *UserTagStore) GetPopularTags(ctx context.Context, username string, publication string) ([]string, error) {
-	ret := _m.Called(ctx, username, publication)
+// GetAliases provides a mock function with given fields: ctx, publication
+func (_m *UserTagStore) GetAliases(ctx context.Context, publication string) ([]*model.TagAlias, error) {
+	ret := _m.Called(ctx, publication)
 
-	var r0 []string
+	var r0 []*model.TagAlias
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]string, error)); ok {
-		return rf(ctx, username, publication)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.TagAlias, error)); ok {
+		return rf(ctx, publication)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
-		r0 = rf(ctx, username, publication)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.TagAlias); ok {
+		r0 = rf(ctx, publication)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).([]*model.TagAlias)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, username, publication)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, publication)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -235,78 +427,1160 @@ func (_m *UserTagStore) GetPopularTags(ctx context.Context, username string, pub
 	return r0, r1
 }
 
-// UserTagStore_GetPopularTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPopularTags'
-type UserTagStore_GetPopularTags_Call struct {
+// UserTagStore_GetAliases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAliases'
+type UserTagStore_GetAliases_Call struct {
 	*mock.Call
 }
 
-// GetPopularTags is a helper method to define mock.On call
+// GetAliases is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+func (_e *UserTagStore_Expecter) GetAliases(ctx interface{}, publication interface{}) *UserTagStore_GetAliases_Call {
+	return &UserTagStore_GetAliases_Call{Call: _e.mock.On("GetAliases", ctx, publication)}
+}
+
+func (_c *UserTagStore_GetAliases_Call) Run(run func(ctx context.Context, publication string)) *UserTagStore_GetAliases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_GetAliases_Call) Return(_a0 []*model.TagAlias, _a1 error) *UserTagStore_GetAliases_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_GetAliases_Call) RunAndReturn(run func(context.Context, string) ([]*model.TagAlias, error)) *UserTagStore_GetAliases_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAll provides a mock function with given fields: ctx, username
+func (_m *UserTagStore) GetAll(ctx context.Context, username string) ([]*model.UserTag, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 []*model.UserTag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.UserTag, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.UserTag); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserTag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_GetAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAll'
+type UserTagStore_GetAll_Call struct {
+	*mock.Call
+}
+
+// GetAll is a helper method to define mock.On call
 //   - ctx context.Context
 //   - username string
+func (_e *UserTagStore_Expecter) GetAll(ctx interface{}, username interface{}) *UserTagStore_GetAll_Call {
+	return &UserTagStore_GetAll_Call{Call: _e.mock.On("GetAll", ctx, username)}
+}
+
+func (_c *UserTagStore_GetAll_Call) Run(run func(ctx context.Context, username string)) *UserTagStore_GetAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_GetAll_Call) Return(_a0 []*model.UserTag, _a1 error) *UserTagStore_GetAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_GetAll_Call) RunAndReturn(run func(context.Context, string) ([]*model.UserTag, error)) *UserTagStore_GetAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCounters provides a mock function with given fields: ctx, publication, tagIDs
+func (_m *UserTagStore) GetCounters(ctx context.Context, publication string, tagIDs []string) ([]*model.UserTag, error) {
+	ret := _m.Called(ctx, publication, tagIDs)
+
+	var r0 []*model.UserTag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) ([]*model.UserTag, error)); ok {
+		return rf(ctx, publication, tagIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) []*model.UserTag); ok {
+		r0 = rf(ctx, publication, tagIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserTag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, publication, tagIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_GetCounters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCounters'
+type UserTagStore_GetCounters_Call struct {
+	*mock.Call
+}
+
+// GetCounters is a helper method to define mock.On call
+//   - ctx context.Context
 //   - publication string
-func (_e *UserTagStore_Expecter) GetPopularTags(ctx interface{}, username interface{}, publication interface{}) *UserTagStore_GetPopularTags_Call {
-	return &UserTagStore_GetPopularTags_Call{Call: _e.mock.On("GetPopularTags", ctx, username, publication)}
+//   - tagIDs []string
+func (_e *UserTagStore_Expecter) GetCounters(ctx interface{}, publication interface{}, tagIDs interface{}) *UserTagStore_GetCounters_Call {
+	return &UserTagStore_GetCounters_Call{Call: _e.mock.On("GetCounters", ctx, publication, tagIDs)}
 }
 
-func (_c *UserTagStore_GetPopularTags_Call) Run(run func(ctx context.Context, username string, publication string)) *UserTagStore_GetPopularTags_Call {
+func (_c *UserTagStore_GetCounters_Call) Run(run func(ctx context.Context, publication string, tagIDs []string)) *UserTagStore_GetCounters_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].([]string))
 	})
 	return _c
 }
 
-func (_c *UserTagStore_GetPopularTags_Call) Return(_a0 []string, _a1 error) *UserTagStore_GetPopularTags_Call {
+func (_c *UserTagStore_GetCounters_Call) Return(_a0 []*model.UserTag, _a1 error) *UserTagStore_GetCounters_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *UserTagStore_GetPopularTags_Call) RunAndReturn(run func(context.Context, string, string) ([]string, error)) *UserTagStore_GetPopularTags_Call {
+func (_c *UserTagStore_GetCounters_Call) RunAndReturn(run func(context.Context, string, []string) ([]*model.UserTag, error)) *UserTagStore_GetCounters_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Store provides a mock function with given fields: ctx, username, publication, tagID, tagName
-func (_m *UserTagStore) Store(ctx context.Context, username string, publication string, tagID string, tagName string) error {
-	ret := _m.Called(ctx, username, publication, tagID, tagName)
+// GetFollowed provides a mock function with given fields: ctx, username, publication, tagIDs
+func (_m *UserTagStore) GetFollowed(ctx context.Context, username string, publication string, tagIDs []string) ([]*model.UserTag, error) {
+	ret := _m.Called(ctx, username, publication, tagIDs)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
-		r0 = rf(ctx, username, publication, tagID, tagName)
+	var r0 []*model.UserTag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) ([]*model.UserTag, error)); ok {
+		return rf(ctx, username, publication, tagIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) []*model.UserTag); ok {
+		r0 = rf(ctx, username, publication, tagIDs)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserTag)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string) error); ok {
+		r1 = rf(ctx, username, publication, tagIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// UserTagStore_Store_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Store'
-type UserTagStore_Store_Call struct {
+// UserTagStore_GetFollowed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFollowed'
+type UserTagStore_GetFollowed_Call struct {
 	*mock.Call
 }
 
-// Store is a helper method to define mock.On call
+// GetFollowed is a helper method to define mock.On call
 //   - ctx context.Context
 //   - username string
 //   - publication string
-//   - tagID string
-//   - tagName string
-func (_e *UserTagStore_Expecter) Store(ctx interface{}, username interface{}, publication interface{}, tagID interface{}, tagName interface{}) *UserTagStore_Store_Call {
-	return &UserTagStore_Store_Call{Call: _e.mock.On("Store", ctx, username, publication, tagID, tagName)}
+//   - tagIDs []string
+func (_e *UserTagStore_Expecter) GetFollowed(ctx interface{}, username interface{}, publication interface{}, tagIDs interface{}) *UserTagStore_GetFollowed_Call {
+	return &UserTagStore_GetFollowed_Call{Call: _e.mock.On("GetFollowed", ctx, username, publication, tagIDs)}
 }
 
-func (_c *UserTagStore_Store_Call) Run(run func(ctx context.Context, username string, publication string, tagID string, tagName string)) *UserTagStore_Store_Call {
+func (_c *UserTagStore_GetFollowed_Call) Run(run func(ctx context.Context, username string, publication string, tagIDs []string)) *UserTagStore_GetFollowed_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].([]string))
 	})
 	return _c
 }
 
-func (_c *UserTagStore_Store_Call) Return(_a0 error) *UserTagStore_Store_Call {
-	_c.Call.Return(_a0)
+func (_c *UserTagStore_GetFollowed_Call) Return(_a0 []*model.UserTag, _a1 error) *UserTagStore_GetFollowed_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_GetFollowed_Call) RunAndReturn(run func(context.Context, string, string, []string) ([]*model.UserTag, error)) *UserTagStore_GetFollowed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLeaderboard provides a mock function with given fields: ctx, publication
+func (_m *UserTagStore) GetLeaderboard(ctx context.Context, publication string) (*model.Leaderboard, error) {
+	ret := _m.Called(ctx, publication)
+
+	var r0 *model.Leaderboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Leaderboard, error)); ok {
+		return rf(ctx, publication)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Leaderboard); ok {
+		r0 = rf(ctx, publication)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Leaderboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, publication)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_GetLeaderboard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLeaderboard'
+type UserTagStore_GetLeaderboard_Call struct {
+	*mock.Call
+}
+
+// GetLeaderboard is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+func (_e *UserTagStore_Expecter) GetLeaderboard(ctx interface{}, publication interface{}) *UserTagStore_GetLeaderboard_Call {
+	return &UserTagStore_GetLeaderboard_Call{Call: _e.mock.On("GetLeaderboard", ctx, publication)}
+}
+
+func (_c *UserTagStore_GetLeaderboard_Call) Run(run func(ctx context.Context, publication string)) *UserTagStore_GetLeaderboard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_GetLeaderboard_Call) Return(_a0 *model.Leaderboard, _a1 error) *UserTagStore_GetLeaderboard_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_GetLeaderboard_Call) RunAndReturn(run func(context.Context, string) (*model.Leaderboard, error)) *UserTagStore_GetLeaderboard_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMeta provides a mock function with given fields: ctx, username, publication
+func (_m *UserTagStore) GetMeta(ctx context.Context, username string, publication string) (*model.UserMeta, error) {
+	ret := _m.Called(ctx, username, publication)
+
+	var r0 *model.UserMeta
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.UserMeta, error)); ok {
+		return rf(ctx, username, publication)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.UserMeta); ok {
+		r0 = rf(ctx, username, publication)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserMeta)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, username, publication)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_GetMeta_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMeta'
+type UserTagStore_GetMeta_Call struct {
+	*mock.Call
+}
+
+// GetMeta is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - publication string
+func (_e *UserTagStore_Expecter) GetMeta(ctx interface{}, username interface{}, publication interface{}) *UserTagStore_GetMeta_Call {
+	return &UserTagStore_GetMeta_Call{Call: _e.mock.On("GetMeta", ctx, username, publication)}
+}
+
+func (_c *UserTagStore_GetMeta_Call) Run(run func(ctx context.Context, username string, publication string)) *UserTagStore_GetMeta_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_GetMeta_Call) Return(_a0 *model.UserMeta, _a1 error) *UserTagStore_GetMeta_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_GetMeta_Call) RunAndReturn(run func(context.Context, string, string) (*model.UserMeta, error)) *UserTagStore_GetMeta_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPopularTags provides a mock function with given fields: ctx, username, publication
+func (_m *UserTagStore) GetPopularTags(ctx context.Context, username string, publication string) ([]string, error) {
+	ret := _m.Called(ctx, username, publication)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]string, error)); ok {
+		return rf(ctx, username, publication)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = rf(ctx, username, publication)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, username, publication)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_GetPopularTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPopularTags'
+type UserTagStore_GetPopularTags_Call struct {
+	*mock.Call
+}
+
+// GetPopularTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - publication string
+func (_e *UserTagStore_Expecter) GetPopularTags(ctx interface{}, username interface{}, publication interface{}) *UserTagStore_GetPopularTags_Call {
+	return &UserTagStore_GetPopularTags_Call{Call: _e.mock.On("GetPopularTags", ctx, username, publication)}
+}
+
+func (_c *UserTagStore_GetPopularTags_Call) Run(run func(ctx context.Context, username string, publication string)) *UserTagStore_GetPopularTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_GetPopularTags_Call) Return(_a0 []string, _a1 error) *UserTagStore_GetPopularTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_GetPopularTags_Call) RunAndReturn(run func(context.Context, string, string) ([]string, error)) *UserTagStore_GetPopularTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRanking provides a mock function with given fields: ctx, publication
+func (_m *UserTagStore) GetRanking(ctx context.Context, publication string) ([]*model.UserTag, error) {
+	ret := _m.Called(ctx, publication)
+
+	var r0 []*model.UserTag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.UserTag, error)); ok {
+		return rf(ctx, publication)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.UserTag); ok {
+		r0 = rf(ctx, publication)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserTag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, publication)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_GetRanking_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRanking'
+type UserTagStore_GetRanking_Call struct {
+	*mock.Call
+}
+
+// GetRanking is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+func (_e *UserTagStore_Expecter) GetRanking(ctx interface{}, publication interface{}) *UserTagStore_GetRanking_Call {
+	return &UserTagStore_GetRanking_Call{Call: _e.mock.On("GetRanking", ctx, publication)}
+}
+
+func (_c *UserTagStore_GetRanking_Call) Run(run func(ctx context.Context, publication string)) *UserTagStore_GetRanking_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_GetRanking_Call) Return(_a0 []*model.UserTag, _a1 error) *UserTagStore_GetRanking_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_GetRanking_Call) RunAndReturn(run func(context.Context, string) ([]*model.UserTag, error)) *UserTagStore_GetRanking_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSubtree provides a mock function with given fields: ctx, publication, tagID
+func (_m *UserTagStore) GetSubtree(ctx context.Context, publication string, tagID string) ([]*model.TaxonomyTag, error) {
+	ret := _m.Called(ctx, publication, tagID)
+
+	var r0 []*model.TaxonomyTag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]*model.TaxonomyTag, error)); ok {
+		return rf(ctx, publication, tagID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*model.TaxonomyTag); ok {
+		r0 = rf(ctx, publication, tagID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TaxonomyTag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, publication, tagID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_GetSubtree_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSubtree'
+type UserTagStore_GetSubtree_Call struct {
+	*mock.Call
+}
+
+// GetSubtree is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+//   - tagID string
+func (_e *UserTagStore_Expecter) GetSubtree(ctx interface{}, publication interface{}, tagID interface{}) *UserTagStore_GetSubtree_Call {
+	return &UserTagStore_GetSubtree_Call{Call: _e.mock.On("GetSubtree", ctx, publication, tagID)}
+}
+
+func (_c *UserTagStore_GetSubtree_Call) Run(run func(ctx context.Context, publication string, tagID string)) *UserTagStore_GetSubtree_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_GetSubtree_Call) Return(_a0 []*model.TaxonomyTag, _a1 error) *UserTagStore_GetSubtree_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_GetSubtree_Call) RunAndReturn(run func(context.Context, string, string) ([]*model.TaxonomyTag, error)) *UserTagStore_GetSubtree_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTaxonomy provides a mock function with given fields: ctx, publication
+func (_m *UserTagStore) GetTaxonomy(ctx context.Context, publication string) ([]*model.TaxonomyTag, error) {
+	ret := _m.Called(ctx, publication)
+
+	var r0 []*model.TaxonomyTag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*model.TaxonomyTag, error)); ok {
+		return rf(ctx, publication)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*model.TaxonomyTag); ok {
+		r0 = rf(ctx, publication)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TaxonomyTag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, publication)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_GetTaxonomy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTaxonomy'
+type UserTagStore_GetTaxonomy_Call struct {
+	*mock.Call
+}
+
+// GetTaxonomy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+func (_e *UserTagStore_Expecter) GetTaxonomy(ctx interface{}, publication interface{}) *UserTagStore_GetTaxonomy_Call {
+	return &UserTagStore_GetTaxonomy_Call{Call: _e.mock.On("GetTaxonomy", ctx, publication)}
+}
+
+func (_c *UserTagStore_GetTaxonomy_Call) Run(run func(ctx context.Context, publication string)) *UserTagStore_GetTaxonomy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_GetTaxonomy_Call) Return(_a0 []*model.TaxonomyTag, _a1 error) *UserTagStore_GetTaxonomy_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_GetTaxonomy_Call) RunAndReturn(run func(context.Context, string) ([]*model.TaxonomyTag, error)) *UserTagStore_GetTaxonomy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ImplicitTags provides a mock function with given fields: ctx, publication, followed
+func (_m *UserTagStore) ImplicitTags(ctx context.Context, publication string, followed []*model.UserTag) ([]*model.UserTag, error) {
+	ret := _m.Called(ctx, publication, followed)
+
+	var r0 []*model.UserTag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*model.UserTag) ([]*model.UserTag, error)); ok {
+		return rf(ctx, publication, followed)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*model.UserTag) []*model.UserTag); ok {
+		r0 = rf(ctx, publication, followed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserTag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []*model.UserTag) error); ok {
+		r1 = rf(ctx, publication, followed)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_ImplicitTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImplicitTags'
+type UserTagStore_ImplicitTags_Call struct {
+	*mock.Call
+}
+
+// ImplicitTags is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+//   - followed []*model.UserTag
+func (_e *UserTagStore_Expecter) ImplicitTags(ctx interface{}, publication interface{}, followed interface{}) *UserTagStore_ImplicitTags_Call {
+	return &UserTagStore_ImplicitTags_Call{Call: _e.mock.On("ImplicitTags", ctx, publication, followed)}
+}
+
+func (_c *UserTagStore_ImplicitTags_Call) Run(run func(ctx context.Context, publication string, followed []*model.UserTag)) *UserTagStore_ImplicitTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]*model.UserTag))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_ImplicitTags_Call) Return(_a0 []*model.UserTag, _a1 error) *UserTagStore_ImplicitTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_ImplicitTags_Call) RunAndReturn(run func(context.Context, string, []*model.UserTag) ([]*model.UserTag, error)) *UserTagStore_ImplicitTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Merge provides a mock function with given fields: ctx, publication, aliasID
+func (_m *UserTagStore) Merge(ctx context.Context, publication string, aliasID string) (*model.MergeResult, error) {
+	ret := _m.Called(ctx, publication, aliasID)
+
+	var r0 *model.MergeResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*model.MergeResult, error)); ok {
+		return rf(ctx, publication, aliasID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *model.MergeResult); ok {
+		r0 = rf(ctx, publication, aliasID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MergeResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, publication, aliasID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_Merge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Merge'
+type UserTagStore_Merge_Call struct {
+	*mock.Call
+}
+
+// Merge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+//   - aliasID string
+func (_e *UserTagStore_Expecter) Merge(ctx interface{}, publication interface{}, aliasID interface{}) *UserTagStore_Merge_Call {
+	return &UserTagStore_Merge_Call{Call: _e.mock.On("Merge", ctx, publication, aliasID)}
+}
+
+func (_c *UserTagStore_Merge_Call) Run(run func(ctx context.Context, publication string, aliasID string)) *UserTagStore_Merge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_Merge_Call) Return(_a0 *model.MergeResult, _a1 error) *UserTagStore_Merge_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_Merge_Call) RunAndReturn(run func(context.Context, string, string) (*model.MergeResult, error)) *UserTagStore_Merge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutAliases provides a mock function with given fields: ctx, publication, canonicalID, canonicalName, aliasIDs, aliasNames
+func (_m *UserTagStore) PutAliases(ctx context.Context, publication string, canonicalID string, canonicalName string, aliasIDs []string, aliasNames []string) error {
+	ret := _m.Called(ctx, publication, canonicalID, canonicalName, aliasIDs, aliasNames)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []string, []string) error); ok {
+		r0 = rf(ctx, publication, canonicalID, canonicalName, aliasIDs, aliasNames)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserTagStore_PutAliases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutAliases'
+type UserTagStore_PutAliases_Call struct {
+	*mock.Call
+}
+
+// PutAliases is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+//   - canonicalID string
+//   - canonicalName string
+//   - aliasIDs []string
+//   - aliasNames []string
+func (_e *UserTagStore_Expecter) PutAliases(ctx interface{}, publication interface{}, canonicalID interface{}, canonicalName interface{}, aliasIDs interface{}, aliasNames interface{}) *UserTagStore_PutAliases_Call {
+	return &UserTagStore_PutAliases_Call{Call: _e.mock.On("PutAliases", ctx, publication, canonicalID, canonicalName, aliasIDs, aliasNames)}
+}
+
+func (_c *UserTagStore_PutAliases_Call) Run(run func(ctx context.Context, publication string, canonicalID string, canonicalName string, aliasIDs []string, aliasNames []string)) *UserTagStore_PutAliases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].([]string), args[5].([]string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_PutAliases_Call) Return(_a0 error) *UserTagStore_PutAliases_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserTagStore_PutAliases_Call) RunAndReturn(run func(context.Context, string, string, string, []string, []string) error) *UserTagStore_PutAliases_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PutTaxonomyTag provides a mock function with given fields: ctx, publication, tagID, tagName, parentID
+func (_m *UserTagStore) PutTaxonomyTag(ctx context.Context, publication string, tagID string, tagName string, parentID string) error {
+	ret := _m.Called(ctx, publication, tagID, tagName, parentID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, publication, tagID, tagName, parentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserTagStore_PutTaxonomyTag_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PutTaxonomyTag'
+type UserTagStore_PutTaxonomyTag_Call struct {
+	*mock.Call
+}
+
+// PutTaxonomyTag is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+//   - tagID string
+//   - tagName string
+//   - parentID string
+func (_e *UserTagStore_Expecter) PutTaxonomyTag(ctx interface{}, publication interface{}, tagID interface{}, tagName interface{}, parentID interface{}) *UserTagStore_PutTaxonomyTag_Call {
+	return &UserTagStore_PutTaxonomyTag_Call{Call: _e.mock.On("PutTaxonomyTag", ctx, publication, tagID, tagName, parentID)}
+}
+
+func (_c *UserTagStore_PutTaxonomyTag_Call) Run(run func(ctx context.Context, publication string, tagID string, tagName string, parentID string)) *UserTagStore_PutTaxonomyTag_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_PutTaxonomyTag_Call) Return(_a0 error) *UserTagStore_PutTaxonomyTag_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserTagStore_PutTaxonomyTag_Call) RunAndReturn(run func(context.Context, string, string, string, string) error) *UserTagStore_PutTaxonomyTag_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RebuildCounters provides a mock function with given fields: ctx, publication
+func (_m *UserTagStore) RebuildCounters(ctx context.Context, publication string) error {
+	ret := _m.Called(ctx, publication)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, publication)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserTagStore_RebuildCounters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RebuildCounters'
+type UserTagStore_RebuildCounters_Call struct {
+	*mock.Call
+}
+
+// RebuildCounters is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+func (_e *UserTagStore_Expecter) RebuildCounters(ctx interface{}, publication interface{}) *UserTagStore_RebuildCounters_Call {
+	return &UserTagStore_RebuildCounters_Call{Call: _e.mock.On("RebuildCounters", ctx, publication)}
+}
+
+func (_c *UserTagStore_RebuildCounters_Call) Run(run func(ctx context.Context, publication string)) *UserTagStore_RebuildCounters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_RebuildCounters_Call) Return(_a0 error) *UserTagStore_RebuildCounters_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserTagStore_RebuildCounters_Call) RunAndReturn(run func(context.Context, string) error) *UserTagStore_RebuildCounters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RefreshLeaderboard provides a mock function with given fields: ctx, publication, size
+func (_m *UserTagStore) RefreshLeaderboard(ctx context.Context, publication string, size int) (*model.Leaderboard, error) {
+	ret := _m.Called(ctx, publication, size)
+
+	var r0 *model.Leaderboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*model.Leaderboard, error)); ok {
+		return rf(ctx, publication, size)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *model.Leaderboard); ok {
+		r0 = rf(ctx, publication, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Leaderboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, publication, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_RefreshLeaderboard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshLeaderboard'
+type UserTagStore_RefreshLeaderboard_Call struct {
+	*mock.Call
+}
+
+// RefreshLeaderboard is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+//   - size int
+func (_e *UserTagStore_Expecter) RefreshLeaderboard(ctx interface{}, publication interface{}, size interface{}) *UserTagStore_RefreshLeaderboard_Call {
+	return &UserTagStore_RefreshLeaderboard_Call{Call: _e.mock.On("RefreshLeaderboard", ctx, publication, size)}
+}
+
+func (_c *UserTagStore_RefreshLeaderboard_Call) Run(run func(ctx context.Context, publication string, size int)) *UserTagStore_RefreshLeaderboard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_RefreshLeaderboard_Call) Return(_a0 *model.Leaderboard, _a1 error) *UserTagStore_RefreshLeaderboard_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_RefreshLeaderboard_Call) RunAndReturn(run func(context.Context, string, int) (*model.Leaderboard, error)) *UserTagStore_RefreshLeaderboard_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Replace provides a mock function with given fields: ctx, username, publication, tags
+func (_m *UserTagStore) Replace(ctx context.Context, username string, publication string, tags []*model.UserTag) ([]*model.UserTag, []*model.UserTag, []*model.UserTag, error) {
+	ret := _m.Called(ctx, username, publication, tags)
+
+	var r0 []*model.UserTag
+	var r1 []*model.UserTag
+	var r2 []*model.UserTag
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []*model.UserTag) ([]*model.UserTag, []*model.UserTag, []*model.UserTag, error)); ok {
+		return rf(ctx, username, publication, tags)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []*model.UserTag) []*model.UserTag); ok {
+		r0 = rf(ctx, username, publication, tags)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserTag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []*model.UserTag) []*model.UserTag); ok {
+		r1 = rf(ctx, username, publication, tags)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]*model.UserTag)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, []*model.UserTag) []*model.UserTag); ok {
+		r2 = rf(ctx, username, publication, tags)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).([]*model.UserTag)
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, string, string, []*model.UserTag) error); ok {
+		r3 = rf(ctx, username, publication, tags)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// UserTagStore_Replace_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Replace'
+type UserTagStore_Replace_Call struct {
+	*mock.Call
+}
+
+// Replace is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - publication string
+//   - tags []*model.UserTag
+func (_e *UserTagStore_Expecter) Replace(ctx interface{}, username interface{}, publication interface{}, tags interface{}) *UserTagStore_Replace_Call {
+	return &UserTagStore_Replace_Call{Call: _e.mock.On("Replace", ctx, username, publication, tags)}
+}
+
+func (_c *UserTagStore_Replace_Call) Run(run func(ctx context.Context, username string, publication string, tags []*model.UserTag)) *UserTagStore_Replace_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].([]*model.UserTag))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_Replace_Call) Return(_a0 []*model.UserTag, _a1 []*model.UserTag, _a2 []*model.UserTag, _a3 error) *UserTagStore_Replace_Call {
+	_c.Call.Return(_a0, _a1, _a2, _a3)
+	return _c
+}
+
+func (_c *UserTagStore_Replace_Call) RunAndReturn(run func(context.Context, string, string, []*model.UserTag) ([]*model.UserTag, []*model.UserTag, []*model.UserTag, error)) *UserTagStore_Replace_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restore provides a mock function with given fields: ctx, username, publication, tagID
+func (_m *UserTagStore) Restore(ctx context.Context, username string, publication string, tagID string) error {
+	ret := _m.Called(ctx, username, publication, tagID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, username, publication, tagID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserTagStore_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type UserTagStore_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - publication string
+//   - tagID string
+func (_e *UserTagStore_Expecter) Restore(ctx interface{}, username interface{}, publication interface{}, tagID interface{}) *UserTagStore_Restore_Call {
+	return &UserTagStore_Restore_Call{Call: _e.mock.On("Restore", ctx, username, publication, tagID)}
+}
+
+func (_c *UserTagStore_Restore_Call) Run(run func(ctx context.Context, username string, publication string, tagID string)) *UserTagStore_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_Restore_Call) Return(_a0 error) *UserTagStore_Restore_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserTagStore_Restore_Call) RunAndReturn(run func(context.Context, string, string, string) error) *UserTagStore_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ScanPublication provides a mock function with given fields: ctx, publication, segment, totalSegments, fn
+func (_m *UserTagStore) ScanPublication(ctx context.Context, publication string, segment int, totalSegments int, fn func([]*model.UserTag) error) error {
+	ret := _m.Called(ctx, publication, segment, totalSegments, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int, func([]*model.UserTag) error) error); ok {
+		r0 = rf(ctx, publication, segment, totalSegments, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserTagStore_ScanPublication_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScanPublication'
+type UserTagStore_ScanPublication_Call struct {
+	*mock.Call
+}
+
+// ScanPublication is a helper method to define mock.On call
+//   - ctx context.Context
+//   - publication string
+//   - segment int
+//   - totalSegments int
+//   - fn func([]*model.UserTag) error
+func (_e *UserTagStore_Expecter) ScanPublication(ctx interface{}, publication interface{}, segment interface{}, totalSegments interface{}, fn interface{}) *UserTagStore_ScanPublication_Call {
+	return &UserTagStore_ScanPublication_Call{Call: _e.mock.On("ScanPublication", ctx, publication, segment, totalSegments, fn)}
+}
+
+func (_c *UserTagStore_ScanPublication_Call) Run(run func(ctx context.Context, publication string, segment int, totalSegments int, fn func([]*model.UserTag) error)) *UserTagStore_ScanPublication_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(int), args[4].(func([]*model.UserTag) error))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_ScanPublication_Call) Return(_a0 error) *UserTagStore_ScanPublication_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserTagStore_ScanPublication_Call) RunAndReturn(run func(context.Context, string, int, int, func([]*model.UserTag) error) error) *UserTagStore_ScanPublication_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetFollowLimit provides a mock function with given fields: ctx, username, publication, limit
+func (_m *UserTagStore) SetFollowLimit(ctx context.Context, username string, publication string, limit int) error {
+	ret := _m.Called(ctx, username, publication, limit)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) error); ok {
+		r0 = rf(ctx, username, publication, limit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserTagStore_SetFollowLimit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetFollowLimit'
+type UserTagStore_SetFollowLimit_Call struct {
+	*mock.Call
+}
+
+// SetFollowLimit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - publication string
+//   - limit int
+func (_e *UserTagStore_Expecter) SetFollowLimit(ctx interface{}, username interface{}, publication interface{}, limit interface{}) *UserTagStore_SetFollowLimit_Call {
+	return &UserTagStore_SetFollowLimit_Call{Call: _e.mock.On("SetFollowLimit", ctx, username, publication, limit)}
+}
+
+func (_c *UserTagStore_SetFollowLimit_Call) Run(run func(ctx context.Context, username string, publication string, limit int)) *UserTagStore_SetFollowLimit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_SetFollowLimit_Call) Return(_a0 error) *UserTagStore_SetFollowLimit_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserTagStore_SetFollowLimit_Call) RunAndReturn(run func(context.Context, string, string, int) error) *UserTagStore_SetFollowLimit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Store provides a mock function with given fields: ctx, username, publication, tagName, tagID, descendants
+func (_m *UserTagStore) Store(ctx context.Context, username string, publication string, tagName string, tagID string, descendants bool) (*model.UserTag, error) {
+	ret := _m.Called(ctx, username, publication, tagName, tagID, descendants)
+
+	var r0 *model.UserTag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, bool) (*model.UserTag, error)); ok {
+		return rf(ctx, username, publication, tagName, tagID, descendants)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, bool) *model.UserTag); ok {
+		r0 = rf(ctx, username, publication, tagName, tagID, descendants)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserTag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, bool) error); ok {
+		r1 = rf(ctx, username, publication, tagName, tagID, descendants)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_Store_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Store'
+type UserTagStore_Store_Call struct {
+	*mock.Call
+}
+
+// Store is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - publication string
+//   - tagName string
+//   - tagID string
+//   - descendants bool
+func (_e *UserTagStore_Expecter) Store(ctx interface{}, username interface{}, publication interface{}, tagName interface{}, tagID interface{}, descendants interface{}) *UserTagStore_Store_Call {
+	return &UserTagStore_Store_Call{Call: _e.mock.On("Store", ctx, username, publication, tagName, tagID, descendants)}
+}
+
+func (_c *UserTagStore_Store_Call) Run(run func(ctx context.Context, username string, publication string, tagName string, tagID string, descendants bool)) *UserTagStore_Store_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string), args[5].(bool))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_Store_Call) Return(_a0 *model.UserTag, _a1 error) *UserTagStore_Store_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserTagStore_Store_Call) RunAndReturn(run func(context.Context, string, string, string, string, bool) (*model.UserTag, error)) *UserTagStore_Store_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Version provides a mock function with given fields: ctx, username, publication
+func (_m *UserTagStore) Version(ctx context.Context, username string, publication string) (int64, error) {
+	ret := _m.Called(ctx, username, publication)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (int64, error)); ok {
+		return rf(ctx, username, publication)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int64); ok {
+		r0 = rf(ctx, username, publication)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, username, publication)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserTagStore_Version_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Version'
+type UserTagStore_Version_Call struct {
+	*mock.Call
+}
+
+// Version is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - publication string
+func (_e *UserTagStore_Expecter) Version(ctx interface{}, username interface{}, publication interface{}) *UserTagStore_Version_Call {
+	return &UserTagStore_Version_Call{Call: _e.mock.On("Version", ctx, username, publication)}
+}
+
+func (_c *UserTagStore_Version_Call) Run(run func(ctx context.Context, username string, publication string)) *UserTagStore_Version_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserTagStore_Version_Call) Return(_a0 int64, _a1 error) *UserTagStore_Version_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *UserTagStore_Store_Call) RunAndReturn(run func(context.Context, string, string, string, string) error) *UserTagStore_Store_Call {
+func (_c *UserTagStore_Version_Call) RunAndReturn(run func(context.Context, string, string) (int64, error)) *UserTagStore_Version_Call {
 	_c.Call.Return(run)
 	return _c
 }
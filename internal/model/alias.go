@@ -0,0 +1,393 @@
+package model
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/constant"
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// TagAlias is stored as ALIAS#<publication>, the sort key is ID#<tag id> for an alias id and NAME#<normalized name>
+// for an alias name. It has no Publication attribute so the scans of the follow rows skip it.
+type TagAlias struct {
+	PK            string
+	SK            string
+	CanonicalID   string
+	CanonicalName string
+}
+
+// MergeResult of the merge of an alias tag into its canonical tag
+type MergeResult struct {
+	CanonicalID   string
+	CanonicalName string
+	// Moved is the number of follows moved to the canonical tag
+	Moved int
+	// Duplicates is the number of follows dropped because the user already followed the canonical tag
+	Duplicates int
+	// Usernames of the users whose follows changed
+	Usernames []string
+}
+
+// PutAliases maps the alias ids and names to the canonical tag. An alias can not be the canonical tag of
+// other aliases and the canonical tag can not be an alias, so an alias is resolved in a single step.
+func (t *tag) PutAliases(ctx context.Context, publication, canonicalID, canonicalName string, aliasIDs, aliasNames []string) error {
+	aliases, err := t.GetAliases(ctx, publication)
+	if err != nil {
+		return err
+	}
+
+	canonicals := map[string]struct{}{}
+	for _, val := range aliases {
+		canonicals[val.CanonicalID] = struct{}{}
+
+		if val.SK == aliasIDKey(canonicalID) {
+			return apperror.New(apperror.KindValidationFailed, apperror.CodeAliasChain,
+				fmt.Sprintf("tag %s is an alias of tag %s", canonicalID, val.CanonicalID))
+		}
+	}
+
+	for _, id := range aliasIDs {
+		if id == canonicalID {
+			return apperror.New(apperror.KindValidationFailed, apperror.CodeAliasChain, fmt.Sprintf("tag %s can not be an alias of itself", id))
+		}
+
+		if _, ok := canonicals[id]; ok {
+			return apperror.New(apperror.KindValidationFailed, apperror.CodeAliasChain, fmt.Sprintf("tag %s has aliases", id))
+		}
+	}
+
+	keys := []string{}
+	for _, id := range aliasIDs {
+		keys = append(keys, aliasIDKey(id))
+	}
+
+	for _, name := range aliasNames {
+		keys = append(keys, aliasNameKey(name))
+	}
+
+	for _, key := range keys {
+		item := TagAlias{
+			PK:            aliasPK(publication),
+			SK:            key,
+			CanonicalID:   canonicalID,
+			CanonicalName: canonicalName,
+		}
+
+		// convert struct to map
+		inputMap, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			t.logger.Error("marshal failed", zap.Error(err))
+			return err
+		}
+
+		_, err = t.db.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(tableName),
+			Item:      inputMap,
+		})
+		if err != nil {
+			t.logger.Error("error storing tag alias", zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetAliases returns every alias of the publication
+func (t *tag) GetAliases(ctx context.Context, publication string) ([]*TagAlias, error) {
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("#v1 = :v1"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "PK",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberS{Value: aliasPK(publication)},
+		},
+	}
+
+	aliases := []*TagAlias{}
+
+	for {
+		res, err := t.db.Query(ctx, &queryInput)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, val := range res.Items {
+			var m TagAlias
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				t.logger.Error("unmarshal failed while fetching aliases", zap.Error(err))
+				return nil, err
+			}
+
+			aliases = append(aliases, &m)
+		}
+
+		if res.LastEvaluatedKey == nil {
+			return aliases, nil
+		}
+
+		queryInput.ExclusiveStartKey = res.LastEvaluatedKey
+	}
+}
+
+// resolveAliases replaces the tags which are aliases by their canonical tag, an alias id takes precedence
+// over an alias name. The tags are resolved in place.
+func (t *tag) resolveAliases(ctx context.Context, publication string, tags []*UserTag) error {
+	keys := []map[string]types.AttributeValue{}
+	seen := map[string]struct{}{}
+	for _, val := range tags {
+		for _, sk := range []string{aliasIDKey(val.TagID), aliasNameKey(val.TagName)} {
+			if _, ok := seen[sk]; ok {
+				continue
+			}
+
+			seen[sk] = struct{}{}
+			keys = append(keys, map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: aliasPK(publication)},
+				"SK": &types.AttributeValueMemberS{Value: sk},
+			})
+		}
+	}
+
+	aliases := map[string]*TagAlias{}
+	for start := 0; start < len(keys); start += constant.BatchGetLimit {
+		end := start + constant.BatchGetLimit
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		items, err := t.batchGet(ctx, keys[start:end])
+		if err != nil {
+			return err
+		}
+
+		for _, val := range items {
+			var m TagAlias
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				t.logger.Error("unmarshal failed while resolving aliases", zap.Error(err))
+				return err
+			}
+
+			aliases[m.SK] = &m
+		}
+	}
+
+	for _, val := range tags {
+		alias, ok := aliases[aliasIDKey(val.TagID)]
+		if !ok {
+			alias, ok = aliases[aliasNameKey(val.TagName)]
+		}
+
+		if ok {
+			val.TagID = alias.CanonicalID
+			val.TagName = alias.CanonicalName
+		}
+	}
+
+	return nil
+}
+
+// Merge moves the follows and the counter of the alias tag to its canonical tag, a user following both
+// keeps the follow of the canonical tag. The alias tag must be mapped to its canonical tag by id.
+func (t *tag) Merge(ctx context.Context, publication, aliasID string) (*MergeResult, error) {
+	res, err := t.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: aliasPK(publication)},
+			"SK": &types.AttributeValueMemberS{Value: aliasIDKey(aliasID)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Item == nil {
+		return nil, apperror.New(apperror.KindNotFound, apperror.CodeAliasNotFound, fmt.Sprintf("tag %s is not an alias", aliasID))
+	}
+
+	var alias TagAlias
+
+	err = attributevalue.UnmarshalMap(res.Item, &alias)
+	if err != nil {
+		t.logger.Error("unmarshal failed while fetching alias", zap.Error(err))
+		return nil, err
+	}
+
+	result := MergeResult{CanonicalID: alias.CanonicalID, CanonicalName: alias.CanonicalName, Usernames: []string{}}
+
+	// audit records of the alias tag have a TagID as well, the ones stored before AuditPublication a Publication too
+	scanInput := dynamodb.ScanInput{
+		TableName:        aws.String(tableName),
+		FilterExpression: aws.String("#v1 = :v1 AND #v2 = :v2 AND attribute_exists(CreatedAt) AND attribute_not_exists(#v3)"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "Publication",
+			"#v2": "TagID",
+			"#v3": "Action",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberS{Value: publication},
+			":v2": &types.AttributeValueMemberS{Value: aliasID},
+		},
+	}
+
+	for {
+		page, err := t.db.Scan(ctx, &scanInput)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, val := range page.Items {
+			var m UserTag
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				t.logger.Error("unmarshal failed while merging user tags", zap.Error(err))
+				return nil, err
+			}
+
+			err = t.mergeFollow(ctx, &m, &alias, &result)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if page.LastEvaluatedKey == nil {
+			break
+		}
+
+		scanInput.ExclusiveStartKey = page.LastEvaluatedKey
+	}
+
+	if result.Moved > 0 {
+		_, err = t.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("PUB#%s", publication)},
+				"SK": &types.AttributeValueMemberS{Value: alias.CanonicalID},
+			},
+			UpdateExpression: aws.String("SET TagCount = if_not_exists(TagCount, :v1) + :incr, TagID = :v2, TagName = :v3"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":v1":   &types.AttributeValueMemberN{Value: "0"},
+				":incr": &types.AttributeValueMemberN{Value: fmt.Sprint(result.Moved)},
+				":v2":   &types.AttributeValueMemberS{Value: alias.CanonicalID},
+				":v3":   &types.AttributeValueMemberS{Value: alias.CanonicalName},
+			},
+		})
+		if err != nil {
+			t.logger.Error("error updating tag counter while merging", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// the counter of the alias tag is dropped with its follows
+	_, err = t.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("PUB#%s", publication)},
+			"SK": &types.AttributeValueMemberS{Value: aliasID},
+		},
+	})
+	if err != nil {
+		t.logger.Error("error deleting tag counter while merging", zap.Error(err))
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// mergeFollow moves the follow row of the alias tag to the canonical tag, an unfollowed row is dropped
+func (t *tag) mergeFollow(ctx context.Context, item *UserTag, alias *TagAlias, result *MergeResult) error {
+	followed := item.DeletedAt == ""
+
+	// the user follows the canonical tag already, the follow of the alias tag is uncounted
+	follows := 0
+	if followed {
+		canonical := *item
+		canonical.SK = alias.CanonicalID
+		canonical.TagID = alias.CanonicalID
+		canonical.TagName = alias.CanonicalName
+
+		// convert struct to map
+		inputMap, err := attributevalue.MarshalMap(canonical)
+		if err != nil {
+			t.logger.Error("marshal failed", zap.Error(err))
+			return err
+		}
+
+		_, err = t.db.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:           aws.String(tableName),
+			Item:                inputMap,
+			ConditionExpression: aws.String("attribute_not_exists(PK) OR attribute_exists(DeletedAt)"),
+		})
+		if _, ok := conditionFailed(err); ok {
+			follows = -1
+			result.Duplicates++
+		} else if err != nil {
+			t.logger.Error("error moving user tag while merging", zap.Error(err))
+			return err
+		} else {
+			result.Moved++
+		}
+	}
+
+	_, err := t.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: item.PK},
+			"SK": &types.AttributeValueMemberS{Value: item.SK},
+		},
+	})
+	if err != nil {
+		t.logger.Error("error deleting user tag while merging", zap.Error(err))
+		return err
+	}
+
+	if !followed {
+		return nil
+	}
+
+	result.Usernames = append(result.Usernames, item.Username)
+
+	return t.bumpVersion(ctx, item.Username, item.Publication, follows)
+}
+
+// aliasPK
+func aliasPK(publication string) string {
+	return fmt.Sprintf("ALIAS#%s", publication)
+}
+
+// aliasIDKey
+func aliasIDKey(tagID string) string {
+	return "ID#" + tagID
+}
+
+// aliasNameKey of the normalized name, "A.I." and "ai" are the same name
+func aliasNameKey(tagName string) string {
+	return "NAME#" + normalizeTagName(tagName)
+}
+
+// normalizeTagName keeps the letters and digits of the name, lower cased
+func normalizeTagName(tagName string) string {
+	var b strings.Builder
+	for _, r := range tagName {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+
+	return b.String()
+}
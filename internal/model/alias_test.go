@@ -0,0 +1,339 @@
+package model_test
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// aliases of the tests, tag 7 and the name "AI" are aliases of tag 5
+var aliases = &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+	{"SK": &types.AttributeValueMemberS{Value: "ID#7"}, "CanonicalID": &types.AttributeValueMemberS{Value: "5"}, "CanonicalName": &types.AttributeValueMemberS{Value: "artificial intelligence"}},
+	{"SK": &types.AttributeValueMemberS{Value: "NAME#ai"}, "CanonicalID": &types.AttributeValueMemberS{Value: "5"}, "CanonicalName": &types.AttributeValueMemberS{Value: "artificial intelligence"}},
+}}
+
+func Test_PutAliases(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name       string
+		tagID      string
+		aliasIDs   []string
+		aliasNames []string
+		mockDB     func() model.Models
+		wantErr    error
+		wantIs     error
+	}{
+		{
+			name:       "success - one item per alias id and name",
+			tagID:      "5",
+			aliasIDs:   []string{"8"},
+			aliasNames: []string{"Machine-Learning"},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(aliases, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["PK"].(*types.AttributeValueMemberS).Value == "ALIAS#AK" &&
+						in.Item["SK"].(*types.AttributeValueMemberS).Value == "ID#8" &&
+						in.Item["CanonicalID"].(*types.AttributeValueMemberS).Value == "5"
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["SK"].(*types.AttributeValueMemberS).Value == "NAME#machinelearning"
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name:     "Should fail with validation failed when canonical tag is an alias",
+			tagID:    "7",
+			aliasIDs: []string{"8"},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(aliases, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindValidationFailed, Code: apperror.CodeAliasChain},
+		},
+		{
+			name:     "Should fail with validation failed when alias has aliases",
+			tagID:    "9",
+			aliasIDs: []string{"5"},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(aliases, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindValidationFailed, Code: apperror.CodeAliasChain},
+		},
+		{
+			name:     "Should fail with validation failed when tag is its own alias",
+			tagID:    "9",
+			aliasIDs: []string{"9"},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(aliases, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindValidationFailed, Code: apperror.CodeAliasChain},
+		},
+		{
+			name:     "Should fail when received error in query call",
+			tagID:    "5",
+			aliasIDs: []string{"8"},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+		{
+			name:     "Should fail when received error in putItem call",
+			tagID:    "5",
+			aliasIDs: []string{"8"},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(aliases, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			err := a.Tag.PutAliases(context.TODO(), "AK", tt.tagID, "tag", tt.aliasIDs, tt.aliasNames)
+
+			if tt.wantIs != nil {
+				assert.ErrorIs(t, err, tt.wantIs)
+				return
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_Merge(t *testing.T) {
+	log := testSuite()
+
+	alias := &dynamodb.GetItemOutput{Item: aliases.Items[0]}
+
+	follow := func(username string, deleted bool) map[string]types.AttributeValue {
+		item := map[string]types.AttributeValue{
+			"PK":          &types.AttributeValueMemberS{Value: username + "#AK"},
+			"SK":          &types.AttributeValueMemberS{Value: "7"},
+			"Username":    &types.AttributeValueMemberS{Value: username},
+			"Publication": &types.AttributeValueMemberS{Value: "AK"},
+			"TagID":       &types.AttributeValueMemberS{Value: "7"},
+			"TagName":     &types.AttributeValueMemberS{Value: "ml"},
+		}
+		if deleted {
+			item["DeletedAt"] = &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00Z"}
+		}
+
+		return item
+	}
+
+	tests := []struct {
+		name    string
+		mockDB  func() model.Models
+		want    *model.MergeResult
+		wantErr error
+		wantIs  error
+	}{
+		{
+			name: "success - follows are moved and duplicates dropped",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(alias, nil).Once()
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+					follow("john", false), follow("jane", false), follow("jack", true),
+				}}, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["Username"].(*types.AttributeValueMemberS).Value == "john" &&
+						in.Item["SK"].(*types.AttributeValueMemberS).Value == "5" &&
+						in.Item["TagID"].(*types.AttributeValueMemberS).Value == "5"
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+				dmock.EXPECT().DeleteItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.DeleteItemInput) bool {
+					return in.Key["SK"].(*types.AttributeValueMemberS).Value == "7"
+				})).Return(&dynamodb.DeleteItemOutput{}, nil).Times(4)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return metaUpdate(in) && in.ExpressionAttributeValues[":v2"] == nil
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return metaUpdate(in) && in.ExpressionAttributeValues[":v2"].(*types.AttributeValueMemberN).Value == "-1"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "PUB#AK" &&
+						in.Key["SK"].(*types.AttributeValueMemberS).Value == "5" &&
+						in.ExpressionAttributeValues[":incr"].(*types.AttributeValueMemberN).Value == "1"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: &model.MergeResult{
+				CanonicalID:   "5",
+				CanonicalName: "artificial intelligence",
+				Moved:         1,
+				Duplicates:    1,
+				Usernames:     []string{"john", "jane"},
+			},
+		},
+		{
+			name: "success - audit records of the alias tag are not merged",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				table := []map[string]types.AttributeValue{
+					follow("john", false),
+					{"PK": &types.AttributeValueMemberS{Value: "AUDIT#USER#john"}, "SK": &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00.000000000Z#00000000"},
+						"Action": &types.AttributeValueMemberS{Value: model.AuditActionFollow}, "Username": &types.AttributeValueMemberS{Value: "john"},
+						"Publication": &types.AttributeValueMemberS{Value: "AK"}, "TagID": &types.AttributeValueMemberS{Value: "7"},
+						"CreatedAt": &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00.000000000Z"}},
+				}
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(alias, nil).Once()
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).RunAndReturn(scanTable(table)).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["PK"].(*types.AttributeValueMemberS).Value == "john#AK"
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				dmock.EXPECT().DeleteItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.DeleteItemInput) bool {
+					return in.Key["SK"].(*types.AttributeValueMemberS).Value == "7"
+				})).Return(&dynamodb.DeleteItemOutput{}, nil).Twice()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "PUB#AK"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: &model.MergeResult{
+				CanonicalID:   "5",
+				CanonicalName: "artificial intelligence",
+				Moved:         1,
+				Usernames:     []string{"john"},
+			},
+		},
+		{
+			name: "success - no follows only the counter is dropped",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(alias, nil).Once()
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{}, nil).Once()
+				dmock.EXPECT().DeleteItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.DeleteItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "PUB#AK"
+				})).Return(&dynamodb.DeleteItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: &model.MergeResult{CanonicalID: "5", CanonicalName: "artificial intelligence", Usernames: []string{}},
+		},
+		{
+			name: "Should fail with not found when tag is not an alias",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindNotFound, Code: apperror.CodeAliasNotFound},
+		},
+		{
+			name: "Should fail when received error in scan call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(alias, nil).Once()
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+		{
+			name: "Should fail when received error in putItem call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(alias, nil).Once()
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+					follow("john", false),
+				}}, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			got, err := a.Tag.Merge(context.TODO(), "AK", "7")
+
+			if tt.wantIs != nil {
+				assert.ErrorIs(t, err, tt.wantIs)
+				return
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package model
+
+import (
+	"article-tag/internal/config"
+	"article-tag/internal/constant"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// Audit actions
+const (
+	AuditActionFollow   = "follow"
+	AuditActionUnfollow = "unfollow"
+	AuditActionUndo     = "undo"
+	AuditActionErase    = "erase"
+	AuditActionUserData = "user_data"
+	AuditActionExport   = "export"
+	AuditActionLimit    = "limit"
+	AuditActionTaxonomy = "taxonomy"
+	AuditActionAlias    = "alias"
+	AuditActionMerge    = "merge"
+)
+
+// AuditErasedUser replaces the username in the records of the tags kept after the user is erased
+const AuditErasedUser = "erased"
+
+// auditTimeLayout is a fixed width timestamp, so that the sort keys are ordered by time
+const auditTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+type AuditStore interface {
+	Record(ctx context.Context, record *AuditRecord) error
+	Query(ctx context.Context, filter AuditFilter) ([]*AuditRecord, error)
+	GetAll(ctx context.Context, username string) ([]*AuditRecord, error)
+	Erase(ctx context.Context, username string) (int, error)
+}
+
+// AuditRecord is an append only entry, stored in the AUDIT#USER#<username> partition,
+// in the AUDIT#TAG#<publication>#<tagID> partition when the action concerns a tag
+// and in the AUDIT#PUB#<publication> partition when the action concerns a publication but no user.
+// The publication is stored as AuditPublication so the scans of the follow rows skip the records.
+type AuditRecord struct {
+	PK          string
+	SK          string
+	Actor       string
+	Action      string
+	Username    string `dynamodbav:",omitempty"`
+	Publication string `dynamodbav:"AuditPublication,omitempty"`
+	TagID       string `dynamodbav:",omitempty"`
+	TagName     string `dynamodbav:",omitempty"`
+	RequestID   string `dynamodbav:",omitempty"`
+	SourceIP    string `dynamodbav:",omitempty"`
+	Detail      string `dynamodbav:",omitempty"`
+	CreatedAt   string
+	TTL         int64 `dynamodbav:",omitempty"`
+}
+
+// ErrAuditNoPartition is returned for a record without username, publication and tag
+var ErrAuditNoPartition = errors.New("audit record has no username, publication or tag")
+
+// AuditFilter selects the records of a user, of a tag when TagID is set, or of a publication
+// when only Publication is set, created between From and To
+type AuditFilter struct {
+	Username    string
+	Publication string
+	TagID       string
+	From        time.Time
+	To          time.Time
+	Limit       int
+}
+
+type audit struct {
+	db        dynamoAPI
+	logger    *zap.Logger
+	retention time.Duration
+}
+
+func NewAudit(m dynamoAPI, logger *zap.Logger) AuditStore {
+	return &audit{
+		db:        m,
+		logger:    logger,
+		retention: config.Duration("AUDIT_RETENTION", constant.AuditRetention),
+	}
+}
+
+// Record
+func (a *audit) Record(ctx context.Context, record *AuditRecord) error {
+	now := time.Now().UTC()
+
+	// random suffix keeps the sort key unique for records created at the same time
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+
+	item := *record
+	item.SK = fmt.Sprintf("%s#%s", now.Format(auditTimeLayout), hex.EncodeToString(suffix))
+	item.CreatedAt = now.Format(time.RFC3339Nano)
+	item.TTL = now.Add(a.retention).Unix()
+
+	partitions := []string{}
+	if record.Username != "" {
+		partitions = append(partitions, auditUserPK(record.Username))
+	}
+
+	if record.TagID != "" {
+		partitions = append(partitions, auditTagPK(record.Publication, record.TagID))
+	}
+
+	// e.g. an export or a taxonomy edit by an admin
+	if record.Username == "" && record.Publication != "" {
+		partitions = append(partitions, auditPublicationPK(record.Publication))
+	}
+
+	if len(partitions) == 0 {
+		return ErrAuditNoPartition
+	}
+
+	for _, pk := range partitions {
+		item.PK = pk
+
+		// convert struct to map
+		inputMap, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			a.logger.Error("marshal failed", zap.Error(err))
+			return err
+		}
+
+		// records are never overwritten
+		input := dynamodb.PutItemInput{
+			TableName:           aws.String(tableName),
+			Item:                inputMap,
+			ConditionExpression: aws.String("attribute_not_exists(PK)"),
+		}
+
+		_, err = a.db.PutItem(ctx, &input)
+		if err != nil {
+			a.logger.Error("error storing audit record", zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Query returns the records matching the filter, newest first
+func (a *audit) Query(ctx context.Context, filter AuditFilter) ([]*AuditRecord, error) {
+	pk := auditUserPK(filter.Username)
+	if filter.TagID != "" {
+		pk = auditTagPK(filter.Publication, filter.TagID)
+	} else if filter.Username == "" {
+		pk = auditPublicationPK(filter.Publication)
+	}
+
+	to := filter.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = constant.AuditQueryLimit
+	}
+
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("#v1 = :v1 AND #v2 BETWEEN :v2 AND :v3"),
+		// expired records are removed by the TTL process lazily
+		FilterExpression: aws.String("attribute_not_exists(#v4) OR #v4 > :v4"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "PK",
+			"#v2": "SK",
+			"#v4": constant.TTLAttribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberS{Value: pk},
+			":v2": &types.AttributeValueMemberS{Value: filter.From.UTC().Format(auditTimeLayout)},
+			// '~' sorts after the suffix of every record created at the upper bound
+			":v3": &types.AttributeValueMemberS{Value: to.UTC().Format(auditTimeLayout) + "~"},
+			":v4": &types.AttributeValueMemberN{Value: fmt.Sprint(time.Now().Unix())},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+
+	records := []*AuditRecord{}
+	for len(records) < limit {
+		queryInput.Limit = aws.Int32(int32(limit - len(records)))
+
+		res, err := a.db.Query(ctx, &queryInput)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, val := range res.Items {
+			var m AuditRecord
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				a.logger.Error("unmarshal failed while fetching audit records", zap.Error(err))
+				return nil, err
+			}
+
+			records = append(records, &m)
+		}
+
+		if res.LastEvaluatedKey == nil {
+			break
+		}
+
+		queryInput.ExclusiveStartKey = res.LastEvaluatedKey
+	}
+
+	return records, nil
+}
+
+// GetAll returns every record of the user which has not expired, oldest first
+func (a *audit) GetAll(ctx context.Context, username string) ([]*AuditRecord, error) {
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("#v1 = :v1"),
+		FilterExpression:       aws.String("attribute_not_exists(#v4) OR #v4 > :v4"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "PK",
+			"#v4": constant.TTLAttribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberS{Value: auditUserPK(username)},
+			":v4": &types.AttributeValueMemberN{Value: fmt.Sprint(time.Now().Unix())},
+		},
+	}
+
+	records := []*AuditRecord{}
+	for {
+		res, err := a.db.Query(ctx, &queryInput)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, val := range res.Items {
+			var m AuditRecord
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				a.logger.Error("unmarshal failed while fetching audit records", zap.Error(err))
+				return nil, err
+			}
+
+			records = append(records, &m)
+		}
+
+		if res.LastEvaluatedKey == nil {
+			return records, nil
+		}
+
+		queryInput.ExclusiveStartKey = res.LastEvaluatedKey
+	}
+}
+
+// Erase deletes the records of the user partition. Their copies in the tag partitions are kept for the history
+// of the tags, with the username and the actor replaced by AuditErasedUser and without the source ip.
+// Returns the number of records erased.
+func (a *audit) Erase(ctx context.Context, username string) (int, error) {
+	records, err := a.GetAll(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+
+	erased := 0
+	for _, val := range records {
+		if val.TagID != "" {
+			err := a.pseudonymize(ctx, auditTagPK(val.Publication, val.TagID), val)
+			if err != nil {
+				return erased, err
+			}
+		}
+
+		_, err := a.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: val.PK},
+				"SK": &types.AttributeValueMemberS{Value: val.SK},
+			},
+		})
+		if err != nil {
+			a.logger.Error("error deleting audit record", zap.Error(err))
+			return erased, err
+		}
+
+		erased++
+	}
+
+	return erased, nil
+}
+
+// pseudonymize removes the user from the copy of the record stored in the partition
+func (a *audit) pseudonymize(ctx context.Context, pk string, record *AuditRecord) error {
+	input := dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: pk},
+			"SK": &types.AttributeValueMemberS{Value: record.SK},
+		},
+		UpdateExpression:    aws.String("SET #v1 = :v1 REMOVE #v3"),
+		ConditionExpression: aws.String("attribute_exists(PK)"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "Username",
+			"#v3": "SourceIP",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberS{Value: AuditErasedUser},
+		},
+	}
+
+	// the actor of the follows is the user
+	if record.Actor == record.Username {
+		input.UpdateExpression = aws.String("SET #v1 = :v1, #v2 = :v1 REMOVE #v3")
+		input.ExpressionAttributeNames["#v2"] = "Actor"
+	}
+
+	_, err := a.db.UpdateItem(ctx, &input)
+
+	// the copy has expired already
+	if _, ok := conditionFailed(err); ok {
+		return nil
+	}
+
+	if err != nil {
+		a.logger.Error("error pseudonymizing audit record", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// auditUserPK
+func auditUserPK(username string) string {
+	return fmt.Sprintf("AUDIT#USER#%s", username)
+}
+
+// auditTagPK
+func auditTagPK(publication, tagID string) string {
+	return fmt.Sprintf("AUDIT#TAG#%s#%s", publication, tagID)
+}
+
+// auditPublicationPK
+func auditPublicationPK(publication string) string {
+	return fmt.Sprintf("AUDIT#PUB#%s", publication)
+}
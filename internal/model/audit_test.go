@@ -0,0 +1,324 @@
+package model_test
+
+import (
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Record(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name    string
+		mockDB  func() model.Models
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["PK"].(*types.AttributeValueMemberS).Value == "AUDIT#USER#user1"
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				models.Audit = model.NewAudit(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name: "Should fail when received error in putItem call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Audit = model.NewAudit(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			err := a.Audit.Record(context.TODO(), &model.AuditRecord{
+				Actor:    "admin",
+				Action:   model.AuditActionErase,
+				Username: "user1",
+			})
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_RecordTag(t *testing.T) {
+	log := testSuite()
+
+	dmock := mocks.NewDynamoAPI(t)
+	dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		return in.Item["PK"].(*types.AttributeValueMemberS).Value == "AUDIT#USER#user1"
+	})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		_, hasTTL := in.Item["TTL"]
+		_, hasPublication := in.Item["Publication"]
+		return in.Item["PK"].(*types.AttributeValueMemberS).Value == "AUDIT#TAG#AK#1" && hasTTL && !hasPublication &&
+			in.Item["AuditPublication"].(*types.AttributeValueMemberS).Value == "AK"
+	})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	// record of a tag action is stored in the user and the tag partition
+	err := model.NewAudit(dmock, log).Record(context.TODO(), &model.AuditRecord{
+		Actor:       "user1",
+		Action:      model.AuditActionFollow,
+		Username:    "user1",
+		Publication: "AK",
+		TagID:       "1",
+	})
+
+	assert.Nil(t, err)
+}
+
+func Test_RecordPublication(t *testing.T) {
+	log := testSuite()
+
+	dmock := mocks.NewDynamoAPI(t)
+	dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		return in.Item["PK"].(*types.AttributeValueMemberS).Value == "AUDIT#PUB#AK"
+	})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	// record of an admin action on a publication is stored in the publication partition
+	err := model.NewAudit(dmock, log).Record(context.TODO(), &model.AuditRecord{
+		Actor:       "admin",
+		Action:      model.AuditActionExport,
+		Publication: "AK",
+	})
+
+	assert.Nil(t, err)
+
+	// record without partition is not lost silently
+	err = model.NewAudit(dmock, log).Record(context.TODO(), &model.AuditRecord{Actor: "admin", Action: model.AuditActionExport})
+
+	assert.Equal(t, model.ErrAuditNoPartition, err)
+}
+
+func Test_AuditQuery(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name    string
+		filter  model.AuditFilter
+		mockDB  func() model.Models
+		want    int
+		wantErr error
+	}{
+		{
+			name:   "success - by user",
+			filter: model.AuditFilter{Username: "user1", Limit: 2},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberS).Value == "AUDIT#USER#user1"
+				})).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{
+						{"Action": &types.AttributeValueMemberS{Value: "follow"}},
+					},
+					LastEvaluatedKey: map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "AUDIT#USER#user1"}},
+				}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return *in.Limit == 1
+				})).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{
+						{"Action": &types.AttributeValueMemberS{Value: "unfollow"}},
+					},
+					LastEvaluatedKey: map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "AUDIT#USER#user1"}},
+				}, nil).Once()
+				models.Audit = model.NewAudit(dmock, log)
+
+				return models
+			},
+			want: 2,
+		},
+		{
+			name:   "success - by tag",
+			filter: model.AuditFilter{Publication: "AK", TagID: "1"},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberS).Value == "AUDIT#TAG#AK#1"
+				})).Return(&dynamodb.QueryOutput{}, nil).Once()
+				models.Audit = model.NewAudit(dmock, log)
+
+				return models
+			},
+			want: 0,
+		},
+		{
+			name:   "success - by publication",
+			filter: model.AuditFilter{Publication: "AK"},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberS).Value == "AUDIT#PUB#AK"
+				})).Return(&dynamodb.QueryOutput{}, nil).Once()
+				models.Audit = model.NewAudit(dmock, log)
+
+				return models
+			},
+			want: 0,
+		},
+		{
+			name:   "Should fail when received error in query call",
+			filter: model.AuditFilter{Username: "user1"},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Audit = model.NewAudit(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			got, err := a.Audit.Query(context.TODO(), tt.filter)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, len(got))
+		})
+	}
+}
+
+func Test_AuditErase(t *testing.T) {
+	log := testSuite()
+
+	follow := map[string]types.AttributeValue{
+		"PK":               &types.AttributeValueMemberS{Value: "AUDIT#USER#user1"},
+		"SK":               &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00.000000000Z#00"},
+		"Actor":            &types.AttributeValueMemberS{Value: "user1"},
+		"Username":         &types.AttributeValueMemberS{Value: "user1"},
+		"AuditPublication": &types.AttributeValueMemberS{Value: "AK"},
+		"TagID":            &types.AttributeValueMemberS{Value: "1"},
+	}
+	limit := map[string]types.AttributeValue{
+		"PK":       &types.AttributeValueMemberS{Value: "AUDIT#USER#user1"},
+		"SK":       &types.AttributeValueMemberS{Value: "2023-01-02T00:00:00.000000000Z#00"},
+		"Actor":    &types.AttributeValueMemberS{Value: "admin"},
+		"Username": &types.AttributeValueMemberS{Value: "user1"},
+	}
+
+	tests := []struct {
+		name    string
+		mockDB  func() model.Models
+		want    int
+		wantErr error
+	}{
+		{
+			name: "success - the copies of the tags are pseudonymized",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberS).Value == "AUDIT#USER#user1"
+				})).Return(&dynamodb.QueryOutput{
+					Items:            []map[string]types.AttributeValue{follow},
+					LastEvaluatedKey: map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "AUDIT#USER#user1"}},
+				}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return in.ExclusiveStartKey != nil
+				})).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{limit},
+				}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "AUDIT#TAG#AK#1" &&
+						in.Key["SK"].(*types.AttributeValueMemberS).Value == "2023-01-01T00:00:00.000000000Z#00" &&
+						*in.UpdateExpression == "SET #v1 = :v1, #v2 = :v1 REMOVE #v3" &&
+						in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberS).Value == model.AuditErasedUser
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().DeleteItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.DeleteItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "AUDIT#USER#user1"
+				})).Return(&dynamodb.DeleteItemOutput{}, nil).Twice()
+				models.Audit = model.NewAudit(dmock, log)
+
+				return models
+			},
+			want: 2,
+		},
+		{
+			name: "expired copies of the tags are skipped",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{follow},
+				}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+				dmock.EXPECT().DeleteItem(mock.Anything, mock.Anything).Return(&dynamodb.DeleteItemOutput{}, nil).Once()
+				models.Audit = model.NewAudit(dmock, log)
+
+				return models
+			},
+			want: 1,
+		},
+		{
+			name: "Should fail when received error in delete call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{limit},
+				}, nil).Once()
+				dmock.EXPECT().DeleteItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Audit = model.NewAudit(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			got, err := a.Audit.Erase(context.TODO(), "user1")
+
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
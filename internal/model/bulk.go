@@ -0,0 +1,467 @@
+package model
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/constant"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// BatchReject is an item of a batch which was not written, with the reason
+type BatchReject struct {
+	Item   *UserTag
+	Reason string
+}
+
+// BatchStore follows the tags of the items, at most 25 items per call. The rows of a user are written in a
+// single transaction along with the follow count and the version of the follow set: aliases are resolved,
+// tags already followed are left as is and the tags over the follow limit are rejected.
+// Popularity counters are not updated here, call RebuildCounters once the writes are done.
+// Items which are still not written after the retries are returned to the caller.
+func (t *tag) BatchStore(ctx context.Context, items []*UserTag) ([]*BatchReject, error) {
+	if len(items) > constant.BatchWriteLimit {
+		return nil, apperror.New(apperror.KindValidationFailed, apperror.CodeBatchTooLarge,
+			fmt.Sprintf("batch of %d items exceeds the limit of %d", len(items), constant.BatchWriteLimit))
+	}
+
+	// the items of every user, in the order of the batch
+	keys := []string{}
+	groups := map[string][]*UserTag{}
+	for _, val := range items {
+		key := fmt.Sprintf("%v#%v", val.Username, val.Publication)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		groups[key] = append(groups[key], val)
+	}
+
+	rejects := []*BatchReject{}
+	for _, key := range keys {
+		res, err := t.batchStoreUser(ctx, groups[key])
+		if err != nil {
+			return nil, err
+		}
+
+		rejects = append(rejects, res...)
+	}
+
+	return rejects, nil
+}
+
+// batchStoreUser follows the tags of the items of a single user and publication
+func (t *tag) batchStoreUser(ctx context.Context, items []*UserTag) ([]*BatchReject, error) {
+	username, publication := items[0].Username, items[0].Publication
+	createdAt := time.Now().UTC().Format(time.RFC3339Nano)
+
+	// resolved copies, the items are returned as given
+	resolved := []*UserTag{}
+	for _, val := range items {
+		resolved = append(resolved, &UserTag{TagID: val.TagID, TagName: val.TagName, CreatedAt: val.CreatedAt, IncludeDescendants: val.IncludeDescendants})
+	}
+
+	err := t.resolveAliases(ctx, publication, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	// an alias and its canonical tag are followed once
+	pending, original := []*UserTag{}, map[*UserTag]*UserTag{}
+	seen := map[string]bool{}
+	for k, val := range resolved {
+		if seen[val.TagID] {
+			continue
+		}
+
+		seen[val.TagID] = true
+		pending = append(pending, val)
+		original[val] = items[k]
+	}
+
+	rejects := []*BatchReject{}
+
+	// retry the cancelled transactions with an exponential backoff, the follow set is read again
+	for attempt := 0; attempt <= constant.BatchWriteRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(1<<attempt) * 50 * time.Millisecond):
+			}
+		}
+
+		tagIDs := []string{}
+		for _, val := range pending {
+			tagIDs = append(tagIDs, val.TagID)
+		}
+
+		followed, err := t.GetFollowed(ctx, username, publication, tagIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		pending = excludeFollowed(pending, followed)
+		if len(pending) == 0 {
+			return rejects, nil
+		}
+
+		meta, err := t.GetMeta(ctx, username, publication)
+		if err != nil {
+			return nil, err
+		}
+
+		limit := meta.FollowLimit
+		if limit == 0 {
+			limit = t.followLimits[publication]
+		}
+
+		if room := limit - meta.FollowCount; limit > 0 && room < len(pending) {
+			if room < 0 {
+				room = 0
+			}
+
+			for _, val := range pending[room:] {
+				rejects = append(rejects, &BatchReject{
+					Item:   original[val],
+					Reason: fmt.Sprintf("follow limit of %d is reached", limit),
+				})
+			}
+
+			pending = pending[:room]
+			if len(pending) == 0 {
+				return rejects, nil
+			}
+		}
+
+		input, err := t.batchStoreInput(username, publication, createdAt, meta, pending)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = t.db.TransactWriteItems(ctx, input)
+
+		// a tag followed or a follow count changed in the meantime
+		var cancelErr *types.TransactionCanceledException
+		if errors.As(err, &cancelErr) {
+			t.logger.Debug("batch store of the user cancelled, retrying", zap.String("username", username), zap.Error(err))
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return rejects, nil
+	}
+
+	for _, val := range pending {
+		rejects = append(rejects, &BatchReject{Item: original[val], Reason: "unprocessed after retries"})
+	}
+
+	return rejects, nil
+}
+
+// batchStoreInput puts the follow rows unless they are followed already, an unfollowed row waiting for its undo window
+// is replaced. The follow count is checked against the one read, so the limit holds.
+func (t *tag) batchStoreInput(username, publication, createdAt string, meta *UserMeta, tags []*UserTag) (*dynamodb.TransactWriteItemsInput, error) {
+	items := []types.TransactWriteItem{}
+	for _, val := range tags {
+		item := UserTag{
+			PK:                 fmt.Sprintf("%v#%v", username, publication),
+			SK:                 val.TagID,
+			TagID:              val.TagID,
+			TagName:            val.TagName,
+			CreatedAt:          val.CreatedAt,
+			Username:           username,
+			Publication:        publication,
+			IncludeDescendants: val.IncludeDescendants,
+		}
+
+		if item.CreatedAt == "" {
+			item.CreatedAt = createdAt
+		}
+
+		// convert struct to map
+		inputMap, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			t.logger.Error("marshal failed", zap.Error(err))
+			return nil, err
+		}
+
+		items = append(items, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:           aws.String(tableName),
+				Item:                inputMap,
+				ConditionExpression: aws.String("attribute_not_exists(PK) OR attribute_exists(DeletedAt)"),
+			},
+		})
+	}
+
+	condition := "#v2 = :v3"
+	if meta.FollowCount == 0 {
+		condition = "attribute_not_exists(#v2) OR #v2 = :v3"
+	}
+
+	items = append(items, types.TransactWriteItem{
+		Update: &types.Update{
+			TableName:           aws.String(tableName),
+			Key:                 userMetaKey(username, publication),
+			UpdateExpression:    aws.String("ADD #v1 :incr, #v2 :v2"),
+			ConditionExpression: aws.String(condition),
+			ExpressionAttributeNames: map[string]string{
+				"#v1": "Version",
+				"#v2": "FollowCount",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":incr": &types.AttributeValueMemberN{Value: "1"},
+				":v2":   &types.AttributeValueMemberN{Value: strconv.Itoa(len(tags))},
+				":v3":   &types.AttributeValueMemberN{Value: strconv.Itoa(meta.FollowCount)},
+			},
+		},
+	})
+
+	return &dynamodb.TransactWriteItemsInput{TransactItems: items}, nil
+}
+
+// tagCounter
+type tagCounter struct {
+	TagName string
+	Count   int
+}
+
+// RebuildCounters recomputes the popularity counters (PUB#<publication> items)
+// of a publication by counting the follow rows of every user, the follow count
+// of every user following a tag of the publication is recomputed as well
+func (t *tag) RebuildCounters(ctx context.Context, publication string) error {
+	counters, follows, err := t.countFollows(ctx, publication)
+	if err != nil {
+		return err
+	}
+
+	// existing counters without any follower are reset to zero
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("#v1 = :v1"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "PK",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberS{Value: fmt.Sprintf("PUB#%s", publication)},
+		},
+		ProjectionExpression: aws.String("TagID, TagName"),
+	}
+
+	for {
+		res, err := t.db.Query(ctx, &queryInput)
+		if err != nil {
+			return err
+		}
+
+		for _, val := range res.Items {
+			var m UserTag
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				t.logger.Error("unmarshal failed while fetching tag counters", zap.Error(err))
+				return err
+			}
+
+			if _, ok := counters[m.TagID]; !ok {
+				counters[m.TagID] = &tagCounter{TagName: m.TagName}
+			}
+		}
+
+		if res.LastEvaluatedKey == nil {
+			break
+		}
+
+		queryInput.ExclusiveStartKey = res.LastEvaluatedKey
+	}
+
+	for tagID, counter := range counters {
+		input := dynamodb.UpdateItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("PUB#%s", publication)},
+				"SK": &types.AttributeValueMemberS{Value: tagID},
+			},
+			UpdateExpression: aws.String("SET TagCount = :v1, TagID = :v2, TagName = :v3"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":v1": &types.AttributeValueMemberN{Value: fmt.Sprint(counter.Count)},
+				":v2": &types.AttributeValueMemberS{Value: tagID},
+				":v3": &types.AttributeValueMemberS{Value: counter.TagName},
+			},
+		}
+
+		_, err := t.db.UpdateItem(ctx, &input)
+		if err != nil {
+			t.logger.Error("error updating tag counter while rebuilding counters", zap.Error(err))
+			return err
+		}
+	}
+
+	return t.setFollowCounts(ctx, publication, follows)
+}
+
+// backfillFollowCounts sets the follow count of every user of every publication
+// from the follow rows, the meta items of the users following no tag are not written
+func (t *tag) backfillFollowCounts(ctx context.Context) error {
+	for _, publication := range constant.AllowdedPublications {
+		_, follows, err := t.countFollows(ctx, publication)
+		if err != nil {
+			return err
+		}
+
+		err = t.setFollowCounts(ctx, publication, follows)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// countFollows counts the followers of each tag of the publication and the follows of each user by scanning the follow rows
+func (t *tag) countFollows(ctx context.Context, publication string) (map[string]*tagCounter, map[string]int, error) {
+	counters := map[string]*tagCounter{}
+	follows := map[string]int{}
+
+	// audit records stored before AuditPublication have a Publication attribute as well
+	scanInput := dynamodb.ScanInput{
+		TableName:        aws.String(tableName),
+		FilterExpression: aws.String("#v1 = :v1 AND attribute_not_exists(DeletedAt) AND attribute_not_exists(#v2)"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "Publication",
+			"#v2": "Action",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberS{Value: publication},
+		},
+		ProjectionExpression: aws.String("TagID, TagName, Username"),
+	}
+
+	// count the followers of each tag and the follows of each user
+	for {
+		res, err := t.db.Scan(ctx, &scanInput)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, val := range res.Items {
+			var m UserTag
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				t.logger.Error("unmarshal failed while counting user tags", zap.Error(err))
+				return nil, nil, err
+			}
+
+			if _, ok := counters[m.TagID]; !ok {
+				counters[m.TagID] = &tagCounter{TagName: m.TagName}
+			}
+
+			counters[m.TagID].Count++
+
+			if m.Username != "" {
+				follows[m.Username]++
+			}
+		}
+
+		if res.LastEvaluatedKey == nil {
+			break
+		}
+
+		scanInput.ExclusiveStartKey = res.LastEvaluatedKey
+	}
+
+	return counters, follows, nil
+}
+
+// setFollowCounts sets the follow count of the users of the publication
+func (t *tag) setFollowCounts(ctx context.Context, publication string, follows map[string]int) error {
+	for username, count := range follows {
+		input := dynamodb.UpdateItemInput{
+			TableName:        aws.String(tableName),
+			Key:              userMetaKey(username, publication),
+			UpdateExpression: aws.String("SET #v1 = :v1"),
+			ExpressionAttributeNames: map[string]string{
+				"#v1": "FollowCount",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":v1": &types.AttributeValueMemberN{Value: strconv.Itoa(count)},
+			},
+		}
+
+		_, err := t.db.UpdateItem(ctx, &input)
+		if err != nil {
+			t.logger.Error("error updating follow count while rebuilding counters", zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ScanPublication scans one segment of the table and passes every page of follow rows,
+// excluding the unfollowed ones, and popularity counters (PUB#<publication> items) of the publication to fn.
+// Segments can be scanned in parallel, each page holds at most constant.ExportPageSize items.
+func (t *tag) ScanPublication(ctx context.Context, publication string, segment, totalSegments int, fn func([]*UserTag) error) error {
+	// audit records stored before AuditPublication have a Publication attribute as well
+	scanInput := dynamodb.ScanInput{
+		TableName:        aws.String(tableName),
+		FilterExpression: aws.String("(#v1 = :v1 AND attribute_not_exists(DeletedAt) AND attribute_not_exists(#v3)) OR #v2 = :v2"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "Publication",
+			"#v2": "PK",
+			"#v3": "Action",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberS{Value: publication},
+			":v2": &types.AttributeValueMemberS{Value: fmt.Sprintf("PUB#%s", publication)},
+		},
+		Segment:       aws.Int32(int32(segment)),
+		TotalSegments: aws.Int32(int32(totalSegments)),
+		Limit:         aws.Int32(constant.ExportPageSize),
+	}
+
+	for {
+		res, err := t.db.Scan(ctx, &scanInput)
+		if err != nil {
+			return err
+		}
+
+		items := []*UserTag{}
+		for _, val := range res.Items {
+			var m UserTag
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				t.logger.Error("unmarshal failed while scanning publication", zap.Error(err))
+				return err
+			}
+
+			items = append(items, &m)
+		}
+
+		if len(items) > 0 {
+			if err := fn(items); err != nil {
+				return err
+			}
+		}
+
+		if res.LastEvaluatedKey == nil {
+			return nil
+		}
+
+		scanInput.ExclusiveStartKey = res.LastEvaluatedKey
+	}
+}
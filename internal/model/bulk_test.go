@@ -0,0 +1,176 @@
+package model_test
+
+import (
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_BatchStore(t *testing.T) {
+	log := testSuite()
+
+	// batch gets of the aliases and of the follow rows of user1
+	aliasKeys := mock.MatchedBy(func(in *dynamodb.BatchGetItemInput) bool {
+		return strings.HasPrefix(in.RequestItems["article-follow-tag-v5"].Keys[0]["PK"].(*types.AttributeValueMemberS).Value, "ALIAS#")
+	})
+	followKeys := mock.MatchedBy(func(in *dynamodb.BatchGetItemInput) bool {
+		return in.RequestItems["article-follow-tag-v5"].Keys[0]["PK"].(*types.AttributeValueMemberS).Value == "user1#AK"
+	})
+	rows := func(items ...map[string]types.AttributeValue) *dynamodb.BatchGetItemOutput {
+		return &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"article-follow-tag-v5": items}}
+	}
+	meta := func(count string) *dynamodb.GetItemOutput {
+		return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+			"FollowCount": &types.AttributeValueMemberN{Value: count},
+		}}
+	}
+
+	items := []*model.UserTag{
+		{Username: "user1", Publication: "AK", TagID: "1", TagName: "tag1"},
+		{Username: "user1", Publication: "AK", TagID: "9", TagName: "alias of tag2"},
+		{Username: "user1", Publication: "AK", TagID: "3", TagName: "tag3"},
+	}
+
+	tooMany := []*model.UserTag{}
+	for i := 0; i < 26; i++ {
+		tooMany = append(tooMany, &model.UserTag{Username: "user1", Publication: "AK", TagID: strconv.Itoa(i)})
+	}
+
+	tests := []struct {
+		name        string
+		items       []*model.UserTag
+		limit       string
+		mockDB      func() model.Models
+		wantRejects []*model.BatchReject
+		wantErr     error
+	}{
+		{
+			name:  "success - aliases are resolved and followed tags are left as is",
+			items: items,
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, aliasKeys).Return(rows(map[string]types.AttributeValue{
+					"SK":            &types.AttributeValueMemberS{Value: "ID#9"},
+					"CanonicalID":   &types.AttributeValueMemberS{Value: "2"},
+					"CanonicalName": &types.AttributeValueMemberS{Value: "tag2"},
+				}), nil).Once()
+				dmock.EXPECT().BatchGetItem(mock.Anything, followKeys).Return(rows(map[string]types.AttributeValue{
+					"TagID":   &types.AttributeValueMemberS{Value: "1"},
+					"TagName": &types.AttributeValueMemberS{Value: "tag1"},
+				}), nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(meta("1"), nil).Once()
+				dmock.EXPECT().TransactWriteItems(mock.Anything, mock.MatchedBy(func(in *dynamodb.TransactWriteItemsInput) bool {
+					items := in.TransactItems
+
+					return len(items) == 3 &&
+						items[0].Put.Item["TagID"].(*types.AttributeValueMemberS).Value == "2" &&
+						items[0].Put.Item["TagName"].(*types.AttributeValueMemberS).Value == "tag2" &&
+						*items[0].Put.ConditionExpression == "attribute_not_exists(PK) OR attribute_exists(DeletedAt)" &&
+						items[1].Put.Item["TagID"].(*types.AttributeValueMemberS).Value == "3" &&
+						items[2].Update.Key["PK"].(*types.AttributeValueMemberS).Value == "META#user1#AK" &&
+						*items[2].Update.ConditionExpression == "#v2 = :v3" &&
+						items[2].Update.ExpressionAttributeValues[":v2"].(*types.AttributeValueMemberN).Value == "2" &&
+						items[2].Update.ExpressionAttributeValues[":v3"].(*types.AttributeValueMemberN).Value == "1"
+				})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantRejects: []*model.BatchReject{},
+		},
+		{
+			name:  "tags over the follow limit are rejected",
+			items: items,
+			limit: "2",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, aliasKeys).Return(rows(), nil).Once()
+				dmock.EXPECT().BatchGetItem(mock.Anything, followKeys).Return(rows(), nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(meta("1"), nil).Once()
+				dmock.EXPECT().TransactWriteItems(mock.Anything, mock.MatchedBy(func(in *dynamodb.TransactWriteItemsInput) bool {
+					return len(in.TransactItems) == 2 &&
+						in.TransactItems[0].Put.Item["TagID"].(*types.AttributeValueMemberS).Value == "1"
+				})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantRejects: []*model.BatchReject{
+				{Item: items[1], Reason: "follow limit of 2 is reached"},
+				{Item: items[2], Reason: "follow limit of 2 is reached"},
+			},
+		},
+		{
+			name:  "a cancelled transaction is retried with the follow set read again",
+			items: items[:1],
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				cancelled := &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+					{Code: aws.String("None")},
+					{Code: aws.String("ConditionalCheckFailed")},
+				}}
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, aliasKeys).Return(rows(), nil).Once()
+				dmock.EXPECT().BatchGetItem(mock.Anything, followKeys).Return(rows(), nil).Twice()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(meta("0"), nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(meta("1"), nil).Once()
+				dmock.EXPECT().TransactWriteItems(mock.Anything, mock.Anything).Return(nil, cancelled).Once()
+				dmock.EXPECT().TransactWriteItems(mock.Anything, mock.MatchedBy(func(in *dynamodb.TransactWriteItemsInput) bool {
+					return *in.TransactItems[1].Update.ConditionExpression == "#v2 = :v3"
+				})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantRejects: []*model.BatchReject{},
+		},
+		{
+			name:  "Should fail when the batch is too large",
+			items: tooMany,
+			mockDB: func() model.Models {
+				return model.NewModel(nil, log)
+			},
+			wantErr: errors.New("batch of 26 items exceeds the limit of 25"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FOLLOW_LIMIT", "0")
+			if tt.limit != "" {
+				t.Setenv("FOLLOW_LIMIT", tt.limit)
+			}
+
+			a := tt.mockDB()
+			if a.Tag == nil {
+				a.Tag = model.NewTag(mocks.NewDynamoAPI(t), log)
+			}
+
+			got, err := a.Tag.BatchStore(context.TODO(), tt.items)
+
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantRejects, got)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"article-tag/internal/cache"
+	"article-tag/internal/config"
+	"article-tag/internal/constant"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Names of the cached reads, used by the metrics
+const (
+	CachePopular = "popular"
+)
+
+// cachedTag is a read-through cache of the popular rankings of a publication, invalidated when the counters
+// are rebuilt or the leaderboard is refreshed. Follows are visible once the entry expires.
+// The followed tags are not cached, their ETag is the version of the follow set read from the store.
+type cachedTag struct {
+	UserTagStore
+
+	cache   cache.Cache
+	metrics *cache.Metrics
+	ttl     time.Duration
+	logger  *zap.Logger
+}
+
+// NewCachedTag reads the time to live of the entries from CACHE_TTL
+func NewCachedTag(store UserTagStore, c cache.Cache, metrics *cache.Metrics, logger *zap.Logger) UserTagStore {
+	return &cachedTag{
+		UserTagStore: store,
+		cache:        c,
+		metrics:      metrics,
+		ttl:          config.Duration("CACHE_TTL", constant.CacheTTL),
+		logger:       logger,
+	}
+}
+
+// GetRanking
+func (c *cachedTag) GetRanking(ctx context.Context, publication string) ([]*UserTag, error) {
+	var ranking []*UserTag
+
+	err := c.readThrough(ctx, CachePopular, popularKey(publication), &ranking, func() (interface{}, error) {
+		return c.UserTagStore.GetRanking(ctx, publication)
+	})
+
+	return ranking, err
+}
+
+// GetPopularTags excludes the followed tags from the cached ranking, a user left with too few
+// popular tags gets the uncached ranking which falls back to every counter of the publication
+func (c *cachedTag) GetPopularTags(ctx context.Context, username, publication string) ([]string, error) {
+	ranking, err := c.GetRanking(ctx, publication)
+	if err != nil {
+		return nil, err
+	}
+
+	if username != "" {
+		existingTags, err := followedOf(ctx, c.UserTagStore, username, publication, ranking)
+		if err != nil {
+			return nil, err
+		}
+
+		remaining := excludeFollowed(ranking, existingTags)
+		if len(remaining) < constant.LeaderboardMinimum && len(remaining) < len(ranking) {
+			return c.UserTagStore.GetPopularTags(ctx, username, publication)
+		}
+
+		ranking = remaining
+	}
+
+	return tagNames(ranking), nil
+}
+
+// Merge
+func (c *cachedTag) Merge(ctx context.Context, publication, aliasID string) (*MergeResult, error) {
+	res, err := c.UserTagStore.Merge(ctx, publication, aliasID)
+	if res != nil {
+		c.delete(ctx, popularKey(publication))
+	}
+
+	return res, err
+}
+
+// RebuildCounters
+func (c *cachedTag) RebuildCounters(ctx context.Context, publication string) error {
+	defer c.delete(ctx, popularKey(publication))
+
+	return c.UserTagStore.RebuildCounters(ctx, publication)
+}
+
+// RefreshLeaderboard
+func (c *cachedTag) RefreshLeaderboard(ctx context.Context, publication string, size int) (*Leaderboard, error) {
+	defer c.delete(ctx, popularKey(publication))
+
+	return c.UserTagStore.RefreshLeaderboard(ctx, publication, size)
+}
+
+// readThrough decodes the cached value of the key into v, on a miss v is loaded and cached.
+// Cache failures are only logged, the store is the source of truth.
+func (c *cachedTag) readThrough(ctx context.Context, name, key string, v interface{}, load func() (interface{}, error)) error {
+	cached, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		c.logger.Error("error reading cache", zap.Error(err), zap.String("key", key))
+	}
+
+	if ok {
+		if err := json.Unmarshal(cached, v); err == nil {
+			c.metrics.Hit(name)
+
+			return nil
+		}
+
+		c.logger.Error("error decoding cached value", zap.Error(err), zap.String("key", key))
+	}
+
+	c.metrics.Miss(name)
+
+	value, err := load()
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if err := c.cache.Set(ctx, key, raw, c.ttl); err != nil {
+		c.logger.Error("error writing cache", zap.Error(err), zap.String("key", key))
+	}
+
+	return json.Unmarshal(raw, v)
+}
+
+func (c *cachedTag) delete(ctx context.Context, keys ...string) {
+	if err := c.cache.Delete(ctx, keys...); err != nil {
+		c.logger.Error("error invalidating cache", zap.Error(err), zap.Strings("keys", keys))
+	}
+}
+
+func popularKey(publication string) string {
+	return fmt.Sprintf("popular:%s", publication)
+}
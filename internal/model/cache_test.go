@@ -0,0 +1,146 @@
+package model_test
+
+import (
+	"article-tag/internal/cache"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// failingCache fails every call, the store is used instead
+type failingCache struct{}
+
+func (failingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("cache error")
+}
+
+func (failingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("cache error")
+}
+
+func (failingCache) Delete(ctx context.Context, keys ...string) error {
+	return errors.New("cache error")
+}
+
+func Test_CachedTag(t *testing.T) {
+	ranking := []*model.UserTag{
+		{TagID: "1", TagName: "cricket", Publication: "AK", TagCount: 5},
+		{TagID: "2", TagName: "football", Publication: "AK", TagCount: 3},
+	}
+
+	// a ranking leaving enough tags once the followed ones are excluded
+	leaderboard, names := []*model.UserTag{}, []string{}
+	for i := 0; i < 12; i++ {
+		id := strconv.Itoa(i)
+		leaderboard = append(leaderboard, &model.UserTag{TagID: id, TagName: "tag" + id, Publication: "AK", TagCount: 20 - i})
+		names = append(names, "tag"+id)
+	}
+
+	t.Run("reads are served from the cache", func(t *testing.T) {
+		tagStoreMock := mocks.NewUserTagStore(t)
+		tagStoreMock.EXPECT().GetRanking(mock.Anything, "AK").Return(leaderboard, nil).Once()
+		tagStoreMock.EXPECT().GetFollowed(mock.Anything, "john", "AK", mock.Anything).Return([]*model.UserTag{{TagID: "0", TagName: "tag0"}}, nil).Times(3)
+
+		metrics := cache.NewMetrics()
+		store := model.NewCachedTag(tagStoreMock, cache.NewLRU(10), metrics, zap.NewNop())
+
+		for i := 0; i < 3; i++ {
+			got, err := store.GetPopularTags(context.Background(), "john", "AK")
+			assert.Nil(t, err)
+			assert.Equal(t, names[1:], got)
+		}
+
+		got, err := store.GetPopularTags(context.Background(), "", "AK")
+		assert.Nil(t, err)
+		assert.Equal(t, names, got)
+
+		assert.Equal(t, map[string]cache.Counts{
+			model.CachePopular: {Hits: 3, Misses: 1},
+		}, metrics.Snapshot())
+	})
+
+	t.Run("a user left with too few tags gets the uncached ranking", func(t *testing.T) {
+		tagStoreMock := mocks.NewUserTagStore(t)
+		tagStoreMock.EXPECT().GetRanking(mock.Anything, "AK").Return(ranking, nil).Once()
+		tagStoreMock.EXPECT().GetFollowed(mock.Anything, "john", "AK", []string{"1", "2"}).Return([]*model.UserTag{{TagID: "1", TagName: "cricket"}}, nil).Once()
+		tagStoreMock.EXPECT().GetPopularTags(mock.Anything, "john", "AK").Return([]string{"football", "golf"}, nil).Once()
+
+		store := model.NewCachedTag(tagStoreMock, cache.NewLRU(10), cache.NewMetrics(), zap.NewNop())
+
+		got, err := store.GetPopularTags(context.Background(), "john", "AK")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"football", "golf"}, got)
+	})
+
+	t.Run("follows do not invalidate the ranking, a refresh of the leaderboard does", func(t *testing.T) {
+		tagStoreMock := mocks.NewUserTagStore(t)
+		tagStoreMock.EXPECT().GetRanking(mock.Anything, "AK").Return(ranking, nil).Twice()
+		tagStoreMock.EXPECT().Store(mock.Anything, "john", "AK", "golf", "3", false).Return(&model.UserTag{TagID: "3", TagName: "golf"}, nil).Once()
+		tagStoreMock.EXPECT().Get(mock.Anything, "john", "AK", "tagname").Return([]*model.UserTag{}, nil).Once()
+		tagStoreMock.EXPECT().Get(mock.Anything, "john", "AK", "tagname").Return([]*model.UserTag{{TagID: "3", TagName: "golf"}}, nil).Once()
+		tagStoreMock.EXPECT().RefreshLeaderboard(mock.Anything, "AK", 50).Return(&model.Leaderboard{}, nil).Once()
+
+		metrics := cache.NewMetrics()
+		store := model.NewCachedTag(tagStoreMock, cache.NewLRU(10), metrics, zap.NewNop())
+		ctx := context.Background()
+
+		store.Get(ctx, "john", "AK", "tagname")
+		store.GetRanking(ctx, "AK")
+
+		_, err := store.Store(ctx, "john", "AK", "golf", "3", false)
+		assert.Nil(t, err)
+
+		// the followed tags are read from the store
+		got, err := store.Get(ctx, "john", "AK", "tagname")
+		assert.Nil(t, err)
+		assert.Equal(t, []*model.UserTag{{TagID: "3", TagName: "golf"}}, got)
+
+		store.GetRanking(ctx, "AK")
+
+		_, err = store.RefreshLeaderboard(ctx, "AK", 50)
+		assert.Nil(t, err)
+
+		store.GetRanking(ctx, "AK")
+
+		assert.Equal(t, map[string]cache.Counts{model.CachePopular: {Hits: 1, Misses: 2}}, metrics.Snapshot())
+	})
+
+	t.Run("errors of the store are not cached", func(t *testing.T) {
+		tagStoreMock := mocks.NewUserTagStore(t)
+		tagStoreMock.EXPECT().GetRanking(mock.Anything, "AK").Return(nil, errors.New("db error")).Once()
+		tagStoreMock.EXPECT().GetRanking(mock.Anything, "AK").Return(ranking, nil).Once()
+
+		store := model.NewCachedTag(tagStoreMock, cache.NewLRU(10), cache.NewMetrics(), zap.NewNop())
+
+		_, err := store.GetPopularTags(context.Background(), "", "AK")
+		assert.EqualError(t, err, "db error")
+
+		got, err := store.GetPopularTags(context.Background(), "", "AK")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"cricket", "football"}, got)
+	})
+
+	t.Run("the store is used when the cache fails", func(t *testing.T) {
+		tagStoreMock := mocks.NewUserTagStore(t)
+		tagStoreMock.EXPECT().GetRanking(mock.Anything, "AK").Return(ranking, nil).Twice()
+		tagStoreMock.EXPECT().Delete(mock.Anything, "john", "AK", "1", "cricket").Return(nil).Once()
+
+		store := model.NewCachedTag(tagStoreMock, failingCache{}, cache.NewMetrics(), zap.NewNop())
+
+		for i := 0; i < 2; i++ {
+			got, err := store.GetRanking(context.Background(), "AK")
+			assert.Nil(t, err)
+			assert.Equal(t, ranking, got)
+		}
+
+		assert.Nil(t, store.Delete(context.Background(), "john", "AK", "1", "cricket"))
+	})
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/constant"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// GetCounters returns the popular tag counters of the tags of a publication with BatchGetItem,
+// 100 keys per call. Tags which were never followed have no counter and are not returned.
+func (t *tag) GetCounters(ctx context.Context, publication string, tagIDs []string) ([]*UserTag, error) {
+	counters := []*UserTag{}
+
+	for start := 0; start < len(tagIDs); start += constant.BatchGetLimit {
+		end := start + constant.BatchGetLimit
+		if end > len(tagIDs) {
+			end = len(tagIDs)
+		}
+
+		keys := []map[string]types.AttributeValue{}
+		for _, val := range tagIDs[start:end] {
+			keys = append(keys, map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("PUB#%s", publication)},
+				"SK": &types.AttributeValueMemberS{Value: val},
+			})
+		}
+
+		items, err := t.batchGet(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, val := range items {
+			var m UserTag
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				t.logger.Error("unmarshal failed while fetching counters", zap.Error(err))
+				return nil, err
+			}
+
+			counters = append(counters, &UserTag{
+				TagID:       m.TagID,
+				TagName:     m.TagName,
+				Publication: publication,
+				TagCount:    m.TagCount,
+			})
+		}
+	}
+
+	return counters, nil
+}
+
+// GetFollowed returns the tags among tagIDs followed by the user with BatchGetItem, 100 keys per call.
+// Used instead of Get when only a few tags are checked, the follow set of a heavy user is unbounded.
+func (t *tag) GetFollowed(ctx context.Context, username, publication string, tagIDs []string) ([]*UserTag, error) {
+	followed := []*UserTag{}
+
+	for start := 0; start < len(tagIDs); start += constant.BatchGetLimit {
+		end := start + constant.BatchGetLimit
+		if end > len(tagIDs) {
+			end = len(tagIDs)
+		}
+
+		keys := []map[string]types.AttributeValue{}
+		for _, val := range tagIDs[start:end] {
+			keys = append(keys, map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%s", username, publication)},
+				"SK": &types.AttributeValueMemberS{Value: val},
+			})
+		}
+
+		items, err := t.batchGet(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, val := range items {
+			var m UserTag
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				t.logger.Error("unmarshal failed while fetching followed tags", zap.Error(err))
+				return nil, err
+			}
+
+			// unfollowed tags are kept until the undo window passes
+			if m.DeletedAt != "" {
+				continue
+			}
+
+			followed = append(followed, &UserTag{
+				TagID:              m.TagID,
+				TagName:            m.TagName,
+				IncludeDescendants: m.IncludeDescendants,
+			})
+		}
+	}
+
+	return followed, nil
+}
+
+// batchGet reads the keys, the unprocessed keys are retried with an exponential backoff
+func (t *tag) batchGet(ctx context.Context, keys []map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	items := []map[string]types.AttributeValue{}
+
+	for attempt := 0; len(keys) > 0; attempt++ {
+		if attempt > constant.BatchWriteRetries {
+			return nil, apperror.New(apperror.KindThrottled, apperror.CodeStoreThrottled, "too many requests, retry later")
+		}
+
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(1<<attempt) * 50 * time.Millisecond):
+			}
+		}
+
+		res, err := t.db.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]types.KeysAndAttributes{tableName: {Keys: keys}},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, res.Responses[tableName]...)
+		keys = res.UnprocessedKeys[tableName].Keys
+	}
+
+	return items, nil
+}
@@ -0,0 +1,150 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// leaderboardSK is the sort key of the leaderboard item
+const leaderboardSK = "LEADERBOARD"
+
+// Leaderboard is the top tags of a publication materialized in a single item (PK LEADERBOARD#<publication>).
+// The item has no Publication attribute so that the scans of the follows of a publication skip it.
+type Leaderboard struct {
+	PK        string
+	SK        string
+	Entries   []LeaderboardEntry
+	UpdatedAt string
+	// Complete is set when every counter of the publication fits in the leaderboard
+	Complete bool
+}
+
+// LeaderboardEntry
+type LeaderboardEntry struct {
+	TagID    string
+	TagName  string
+	TagCount int
+	// Rank starts at 1
+	Rank int
+	// Delta is the previous rank minus the rank, positive when the tag moved up
+	Delta int
+	// New is set when the tag was not in the previous leaderboard
+	New bool
+}
+
+// GetLeaderboard returns nil when the leaderboard of the publication was never materialized
+func (t *tag) GetLeaderboard(ctx context.Context, publication string) (*Leaderboard, error) {
+	res, err := t.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key:       leaderboardKey(publication),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Item == nil {
+		return nil, nil
+	}
+
+	var m Leaderboard
+
+	err = attributevalue.UnmarshalMap(res.Item, &m)
+	if err != nil {
+		t.logger.Error("unmarshal failed while fetching leaderboard", zap.Error(err))
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// RefreshLeaderboard materializes the top tags of the publication from the TagIndex,
+// the rank deltas are computed against the previous leaderboard
+func (t *tag) RefreshLeaderboard(ctx context.Context, publication string, size int) (*Leaderboard, error) {
+	previous, err := t.GetLeaderboard(ctx, publication)
+	if err != nil {
+		return nil, err
+	}
+
+	previousRank := map[string]int{}
+	if previous != nil {
+		for _, val := range previous.Entries {
+			previousRank[val.TagID] = val.Rank
+		}
+	}
+
+	ranking, err := t.ranking(ctx, publication, size)
+	if err != nil {
+		return nil, err
+	}
+
+	leaderboard := Leaderboard{
+		PK:        fmt.Sprintf("LEADERBOARD#%s", publication),
+		SK:        leaderboardSK,
+		Entries:   []LeaderboardEntry{},
+		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+		Complete:  size <= 0 || len(ranking) < size,
+	}
+
+	for k, val := range ranking {
+		entry := LeaderboardEntry{
+			TagID:    val.TagID,
+			TagName:  val.TagName,
+			TagCount: val.TagCount,
+			Rank:     k + 1,
+		}
+
+		if rank, ok := previousRank[val.TagID]; ok {
+			entry.Delta = rank - entry.Rank
+		} else {
+			entry.New = true
+		}
+
+		leaderboard.Entries = append(leaderboard.Entries, entry)
+	}
+
+	// convert struct to map
+	item, err := attributevalue.MarshalMap(leaderboard)
+	if err != nil {
+		t.logger.Error("marshal failed", zap.Error(err))
+		return nil, err
+	}
+
+	_, err = t.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &leaderboard, nil
+}
+
+// tags returns the entries as counters of the publication
+func (l *Leaderboard) tags(publication string) []*UserTag {
+	res := []*UserTag{}
+	for _, val := range l.Entries {
+		res = append(res, &UserTag{
+			TagID:       val.TagID,
+			TagName:     val.TagName,
+			Publication: publication,
+			TagCount:    val.TagCount,
+		})
+	}
+
+	return res
+}
+
+func leaderboardKey(publication string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("LEADERBOARD#%s", publication)},
+		"SK": &types.AttributeValueMemberS{Value: leaderboardSK},
+	}
+}
@@ -0,0 +1,166 @@
+package model_test
+
+import (
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func leaderboardItem(t *testing.T, l model.Leaderboard) map[string]types.AttributeValue {
+	item, err := attributevalue.MarshalMap(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return item
+}
+
+func counterItem(tagID, tagName, tagCount string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"TagID":    &types.AttributeValueMemberS{Value: tagID},
+		"TagName":  &types.AttributeValueMemberS{Value: tagName},
+		"TagCount": &types.AttributeValueMemberN{Value: tagCount},
+	}
+}
+
+func Test_RefreshLeaderboard(t *testing.T) {
+	log := testSuite()
+
+	previous := model.Leaderboard{
+		PK: "LEADERBOARD#AK",
+		SK: "LEADERBOARD",
+		Entries: []model.LeaderboardEntry{
+			{TagID: "1", TagName: "tag1", TagCount: 5, Rank: 1},
+			{TagID: "2", TagName: "tag2", TagCount: 3, Rank: 2},
+			{TagID: "3", TagName: "tag3", TagCount: 2, Rank: 3},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		mockDB       func() model.Models
+		want         []model.LeaderboardEntry
+		wantComplete bool
+		wantErr      error
+	}{
+		{
+			name: "success with the rank deltas of the previous leaderboard",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: leaderboardItem(t, previous)}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return *in.Limit == 3
+				})).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{
+						counterItem("3", "tag3", "9"),
+						counterItem("1", "tag1", "6"),
+					},
+					LastEvaluatedKey: map[string]types.AttributeValue{
+						"SK":       &types.AttributeValueMemberS{Value: "1"},
+						"TagCount": &types.AttributeValueMemberN{Value: "6"},
+					},
+				}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{
+						counterItem("4", "tag4", "4"),
+						counterItem("2", "tag2", "3"),
+					},
+					LastEvaluatedKey: map[string]types.AttributeValue{
+						"SK":       &types.AttributeValueMemberS{Value: "2"},
+						"TagCount": &types.AttributeValueMemberN{Value: "3"},
+					},
+				}, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["PK"].(*types.AttributeValueMemberS).Value == "LEADERBOARD#AK" && in.Item["Publication"] == nil
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: []model.LeaderboardEntry{
+				{TagID: "3", TagName: "tag3", TagCount: 9, Rank: 1, Delta: 2},
+				{TagID: "1", TagName: "tag1", TagCount: 6, Rank: 2, Delta: -1},
+				{TagID: "4", TagName: "tag4", TagCount: 4, Rank: 3, New: true},
+			},
+		},
+		{
+			name: "success with every counter of the publication",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{
+						counterItem("1", "tag1", "6"),
+						counterItem("2", "tag2", "3"),
+					},
+				}, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["Complete"].(*types.AttributeValueMemberBOOL).Value
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: []model.LeaderboardEntry{
+				{TagID: "1", TagName: "tag1", TagCount: 6, Rank: 1, New: true},
+				{TagID: "2", TagName: "tag2", TagCount: 3, Rank: 2, New: true},
+			},
+			wantComplete: true,
+		},
+		{
+			name: "Should fail when received error in putItem call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			got, err := a.Tag.RefreshLeaderboard(context.TODO(), "AK", 3)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.want, got.Entries)
+				assert.Equal(t, tt.wantComplete, got.Complete)
+				assert.NotEmpty(t, got.UpdatedAt)
+			}
+		})
+	}
+}
+
+func Test_GetLeaderboard(t *testing.T) {
+	log := testSuite()
+
+	dmock := mocks.NewDynamoAPI(t)
+	dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+	got, err := model.NewTag(dmock, log).GetLeaderboard(context.TODO(), "AK")
+
+	assert.Nil(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,347 @@
+package model
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/config"
+	"article-tag/internal/constant"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// userMetaSK is the sort key of the meta item of a user, a single item per user and publication
+const userMetaSK = "META"
+
+// errFollowLimitReached matches the error of a follow over the limit
+var errFollowLimitReached = &apperror.Error{Kind: apperror.KindConflict, Code: apperror.CodeFollowLimitReached}
+
+// UserMeta is stored as META#<username>#<publication>, it has no Publication
+// attribute so the scans of the follow rows skip it
+type UserMeta struct {
+	PK string
+	SK string
+	// Version of the follow set, incremented by every follow and unfollow
+	Version int64
+	// FollowCount is the number of followed tags
+	FollowCount int
+	// FollowLimit overrides the limit of the publication for the user, 0 when it is not overridden
+	FollowLimit int `dynamodbav:",omitempty"`
+}
+
+// followLimits reads the maximum number of followed tags of every publication, FOLLOW_LIMIT_<publication>
+// overrides FOLLOW_LIMIT, 0 disables the limit
+func followLimits() map[string]int {
+	limit := config.Int("FOLLOW_LIMIT", constant.FollowLimit)
+
+	limits := map[string]int{}
+	for _, publication := range constant.AllowdedPublications {
+		limits[publication] = config.Int("FOLLOW_LIMIT_"+publication, limit)
+	}
+
+	return limits
+}
+
+// GetMeta returns the meta item of the user, empty until the user follows a tag
+func (t *tag) GetMeta(ctx context.Context, username, publication string) (*UserMeta, error) {
+	res, err := t.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key:       userMetaKey(username, publication),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var m UserMeta
+
+	err = attributevalue.UnmarshalMap(res.Item, &m)
+	if err != nil {
+		t.logger.Error("unmarshal failed while fetching user meta", zap.Error(err))
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Version returns the version of the follow set of the user, 0 until the user follows a tag
+func (t *tag) Version(ctx context.Context, username, publication string) (int64, error) {
+	m, err := t.GetMeta(ctx, username, publication)
+	if err != nil {
+		return 0, err
+	}
+
+	return m.Version, nil
+}
+
+// versionKey is the context key of the version of the follow set expected by the writes of a request
+type versionKey struct{}
+
+// versionGuard is the expected version, claimed by the first write of the request
+type versionGuard struct {
+	version int64
+	claimed bool
+}
+
+// WithVersion returns a context whose writes of the follow set fail with version_mismatch
+// unless the follow set is still at the given version
+func WithVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, versionKey{}, &versionGuard{version: version})
+}
+
+// expectedVersion returns the version expected by the context, unless a write of the request already claimed it
+func expectedVersion(ctx context.Context) (*versionGuard, bool) {
+	guard, ok := ctx.Value(versionKey{}).(*versionGuard)
+	if !ok || guard.claimed {
+		return nil, false
+	}
+
+	return guard, true
+}
+
+// errVersionMismatch is the error of a write holding an outdated version
+func errVersionMismatch(err error) error {
+	return apperror.Wrap(apperror.KindPreconditionFailed, apperror.CodeVersionMismatch, "follow set has changed since it was read", err)
+}
+
+// claimVersion increments the version of the follow set when it is still the one expected by the context,
+// two writers holding the same version can not both proceed. The returned func gives the version back
+// when the write fails afterwards. Nothing is claimed without an expected version.
+func (t *tag) claimVersion(ctx context.Context, username, publication string) (func(), error) {
+	guard, ok := expectedVersion(ctx)
+	if !ok {
+		return func() {}, nil
+	}
+
+	input := dynamodb.UpdateItemInput{
+		TableName:           aws.String(tableName),
+		Key:                 userMetaKey(username, publication),
+		UpdateExpression:    aws.String("ADD #v1 :incr"),
+		ConditionExpression: aws.String("#v1 = :v1"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "Version",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1":   &types.AttributeValueMemberN{Value: strconv.FormatInt(guard.version, 10)},
+			":incr": &types.AttributeValueMemberN{Value: "1"},
+		},
+	}
+
+	// the follow set of a user who never followed a tag has no meta item yet
+	if guard.version == 0 {
+		input.ConditionExpression = aws.String("attribute_not_exists(#v1) OR #v1 = :v1")
+	}
+
+	_, err := t.db.UpdateItem(ctx, &input)
+	if _, ok := conditionFailed(err); ok {
+		return nil, errVersionMismatch(err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	guard.claimed = true
+
+	return func() {
+		guard.claimed = false
+		t.restoreVersion(ctx, username, publication, guard.version)
+	}, nil
+}
+
+// restoreVersion gives back the version claimed by a failed write, unless another write changed it since
+func (t *tag) restoreVersion(ctx context.Context, username, publication string, version int64) {
+	_, err := t.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(tableName),
+		Key:                 userMetaKey(username, publication),
+		UpdateExpression:    aws.String("SET #v1 = :v1"),
+		ConditionExpression: aws.String("#v1 = :v2"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "Version",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberN{Value: strconv.FormatInt(version, 10)},
+			":v2": &types.AttributeValueMemberN{Value: strconv.FormatInt(version+1, 10)},
+		},
+	})
+	if _, ok := conditionFailed(err); !ok && err != nil {
+		t.logger.Error("error restoring follow set version", zap.Error(err))
+	}
+}
+
+// DefaultFollowLimit returns the maximum number of followed tags of the publication, 0 when it is not limited
+func (t *tag) DefaultFollowLimit(publication string) int {
+	return t.followLimits[publication]
+}
+
+// SetFollowLimit overrides the limit of the publication for the user, 0 removes the override
+func (t *tag) SetFollowLimit(ctx context.Context, username, publication string, limit int) error {
+	input := dynamodb.UpdateItemInput{
+		TableName:        aws.String(tableName),
+		Key:              userMetaKey(username, publication),
+		UpdateExpression: aws.String("SET #v1 = :v1"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "FollowLimit",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberN{Value: strconv.Itoa(limit)},
+		},
+	}
+
+	if limit == 0 {
+		input.UpdateExpression = aws.String("REMOVE #v1")
+		input.ExpressionAttributeValues = nil
+	}
+
+	_, err := t.db.UpdateItem(ctx, &input)
+	if err != nil {
+		t.logger.Error("error updating follow limit", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// reserveFollow counts a follow unless the user has reached the limit, the limit of the user
+// overrides the one of the publication. Release it when the tag is not followed after all.
+func (t *tag) reserveFollow(ctx context.Context, username, publication string) error {
+	input := dynamodb.UpdateItemInput{
+		TableName:           aws.String(tableName),
+		Key:                 userMetaKey(username, publication),
+		UpdateExpression:    aws.String("ADD #v1 :incr"),
+		ConditionExpression: aws.String("attribute_not_exists(#v1) OR #v1 < #v2 OR attribute_not_exists(#v2)"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "FollowCount",
+			"#v2": "FollowLimit",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":incr": &types.AttributeValueMemberN{Value: "1"},
+		},
+	}
+
+	if limit := t.followLimits[publication]; limit > 0 {
+		input.ConditionExpression = aws.String("attribute_not_exists(#v1) OR #v1 < #v2 OR (attribute_not_exists(#v2) AND #v1 < :v1)")
+		input.ExpressionAttributeValues[":v1"] = &types.AttributeValueMemberN{Value: strconv.Itoa(limit)}
+	}
+
+	_, err := t.db.UpdateItem(ctx, &input)
+	if _, ok := conditionFailed(err); ok {
+		return apperror.Wrap(apperror.KindConflict, apperror.CodeFollowLimitReached, "follow limit of the publication is reached", err)
+	}
+
+	return err
+}
+
+// releaseFollow uncounts a reserved follow
+func (t *tag) releaseFollow(ctx context.Context, username, publication string) error {
+	_, err := t.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(tableName),
+		Key:              userMetaKey(username, publication),
+		UpdateExpression: aws.String("ADD #v1 :decr"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "FollowCount",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":decr": &types.AttributeValueMemberN{Value: "-1"},
+		},
+	})
+	if err != nil {
+		t.logger.Error("error releasing reserved follow", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// isFollowed reports whether the tag is followed, an unfollowed tag waiting for its undo window is not
+func (t *tag) isFollowed(ctx context.Context, username, publication, tagID string) (bool, error) {
+	res, err := t.db.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%s", username, publication)},
+			"SK": &types.AttributeValueMemberS{Value: tagID},
+		},
+		ProjectionExpression: aws.String("PK, DeletedAt"),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	_, deleted := res.Item["DeletedAt"]
+
+	return res.Item != nil && !deleted, nil
+}
+
+// bumpVersion increments the version of the follow set once it has changed, follows is added to the follow count
+func (t *tag) bumpVersion(ctx context.Context, username, publication string, follows int) error {
+	input := dynamodb.UpdateItemInput{
+		TableName:        aws.String(tableName),
+		Key:              userMetaKey(username, publication),
+		UpdateExpression: aws.String("ADD #v1 :incr"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "Version",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":incr": &types.AttributeValueMemberN{Value: "1"},
+		},
+	}
+
+	if follows != 0 {
+		input.UpdateExpression = aws.String("ADD #v1 :incr, #v2 :v2")
+		input.ExpressionAttributeNames["#v2"] = "FollowCount"
+		input.ExpressionAttributeValues[":v2"] = &types.AttributeValueMemberN{Value: strconv.Itoa(follows)}
+	}
+
+	_, err := t.db.UpdateItem(ctx, &input)
+	if err != nil {
+		t.logger.Error("error updating follow set version", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// eraseMeta empties the meta items of the user in every publication. The items are kept as a tombstone
+// with their version, a follow set recreated after the erasure never reuses a version already handed out.
+func (t *tag) eraseMeta(ctx context.Context, username string) error {
+	for _, publication := range constant.AllowdedPublications {
+		_, err := t.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:           aws.String(tableName),
+			Key:                 userMetaKey(username, publication),
+			UpdateExpression:    aws.String("SET #v2 = :v2 REMOVE #v3 ADD #v1 :incr"),
+			ConditionExpression: aws.String("attribute_exists(PK)"),
+			ExpressionAttributeNames: map[string]string{
+				"#v1": "Version",
+				"#v2": "FollowCount",
+				"#v3": "FollowLimit",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":v2":   &types.AttributeValueMemberN{Value: "0"},
+				":incr": &types.AttributeValueMemberN{Value: "1"},
+			},
+		})
+
+		// the user never followed a tag of the publication
+		if _, ok := conditionFailed(err); ok {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// userMetaKey
+func userMetaKey(username, publication string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("META#%s#%s", username, publication)},
+		"SK": &types.AttributeValueMemberS{Value: userMetaSK},
+	}
+}
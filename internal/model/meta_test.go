@@ -0,0 +1,406 @@
+package model_test
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// metaUpdate matches the updates of the meta item of a user
+func metaUpdate(in *dynamodb.UpdateItemInput) bool {
+	return strings.HasPrefix(in.Key["PK"].(*types.AttributeValueMemberS).Value, "META#")
+}
+
+// metaTombstone matches the updates emptying the meta item of an erased user
+func metaTombstone(in *dynamodb.UpdateItemInput) bool {
+	return metaUpdate(in) && *in.UpdateExpression == "SET #v2 = :v2 REMOVE #v3 ADD #v1 :incr"
+}
+
+func Test_Version(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name    string
+		mockDB  func() model.Models
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.GetItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "META#user1#AK"
+				})).Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"Version": &types.AttributeValueMemberN{Value: "7"},
+				}}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: 7,
+		},
+		{
+			name: "success - user never followed a tag",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: 0,
+		},
+		{
+			name: "Should fail when received error in getItem call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			got, err := a.Tag.Version(context.TODO(), "user1", "AK")
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_WithVersion(t *testing.T) {
+	log := testSuite()
+
+	// claim matches the update of the version expected by the context
+	claim := func(condition, version string) interface{} {
+		return mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+			return metaUpdate(in) && *in.UpdateExpression == "ADD #v1 :incr" && in.ConditionExpression != nil &&
+				*in.ConditionExpression == condition && in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == version
+		})
+	}
+
+	// row matches the update of the follow row
+	row := mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+		return in.Key["PK"].(*types.AttributeValueMemberS).Value == "user1#AK"
+	})
+
+	tests := []struct {
+		name    string
+		version int64
+		mockDB  func() model.Models
+		wantErr error
+		wantIs  error
+	}{
+		{
+			name:    "success",
+			version: 7,
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, claim("#v1 = :v1", "7")).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, row).Return(&dynamodb.UpdateItemOutput{
+					Attributes: map[string]types.AttributeValue{"TagID": &types.AttributeValueMemberS{Value: "1"}},
+				}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Twice()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name:    "success - first version of the follow set",
+			version: 0,
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, claim("attribute_not_exists(#v1) OR #v1 = :v1", "0")).
+					Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, row).Return(&dynamodb.UpdateItemOutput{
+					Attributes: map[string]types.AttributeValue{"TagID": &types.AttributeValueMemberS{Value: "1"}},
+				}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Twice()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name:    "Should fail with precondition failed when version is stale",
+			version: 6,
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				// nothing else is written
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, claim("#v1 = :v1", "6")).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindPreconditionFailed, Code: apperror.CodeVersionMismatch},
+		},
+		{
+			name:    "Should give the version back when the write fails",
+			version: 7,
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, claim("#v1 = :v1", "7")).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, row).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return metaUpdate(in) && *in.UpdateExpression == "SET #v1 = :v1" && *in.ConditionExpression == "#v1 = :v2" &&
+						in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == "7" &&
+						in.ExpressionAttributeValues[":v2"].(*types.AttributeValueMemberN).Value == "8"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: apperror.ErrNotFound,
+		},
+		{
+			name:    "Should fail when received error in updateItem call",
+			version: 7,
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			err := a.Tag.Delete(model.WithVersion(context.TODO(), tt.version), "user1", "AK", "1", "")
+
+			if tt.wantIs != nil {
+				assert.ErrorIs(t, err, tt.wantIs)
+				return
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_WithVersionStoreRestore(t *testing.T) {
+	log := testSuite()
+
+	claim := mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+		return metaUpdate(in) && *in.UpdateExpression == "ADD #v1 :incr" && in.ExpressionAttributeNames["#v1"] == "Version" &&
+			in.ConditionExpression != nil && in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == "7"
+	})
+	reserve := mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+		return metaUpdate(in) && in.ExpressionAttributeNames["#v1"] == "FollowCount" && in.ConditionExpression != nil
+	})
+	restore := mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+		return metaUpdate(in) && *in.UpdateExpression == "SET #v1 = :v1" &&
+			in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == "7"
+	})
+
+	tests := []struct {
+		name    string
+		call    func(store model.UserTagStore, ctx context.Context) error
+		mockDB  func() model.Models
+		wantErr error
+		wantIs  error
+	}{
+		{
+			name: "following again a followed tag at the limit gives the version back",
+			call: func(store model.UserTagStore, ctx context.Context) error {
+				_, err := store.Store(ctx, "user1", "AK", "tag1", "1", false)
+				return err
+			},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, claim).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, reserve).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{
+					Item: map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "user1#AK"}},
+				}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, restore).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name: "restore claims the version",
+			call: func(store model.UserTagStore, ctx context.Context) error {
+				return store.Restore(ctx, "user1", "AK", "1")
+			},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				claimed := dmock.EXPECT().UpdateItem(mock.Anything, claim).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, reserve).Return(&dynamodb.UpdateItemOutput{}, nil).Once().NotBefore(claimed)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "user1#AK"
+				})).Return(&dynamodb.UpdateItemOutput{
+					Attributes: map[string]types.AttributeValue{"TagName": &types.AttributeValueMemberS{Value: "tag1"}},
+				}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Twice()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name: "Should fail to restore with precondition failed when version is stale",
+			call: func(store model.UserTagStore, ctx context.Context) error {
+				return store.Restore(ctx, "user1", "AK", "1")
+			},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				// nothing else is written
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, claim).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindPreconditionFailed, Code: apperror.CodeVersionMismatch},
+		},
+		{
+			name: "Should give the version back when the restore fails",
+			call: func(store model.UserTagStore, ctx context.Context) error {
+				return store.Restore(ctx, "user1", "AK", "1")
+			},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, claim).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, reserve).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, restore).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindConflict, Code: apperror.CodeFollowLimitReached},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			err := tt.call(a.Tag, model.WithVersion(context.TODO(), 7))
+
+			if tt.wantIs != nil {
+				assert.ErrorIs(t, err, tt.wantIs)
+				return
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_SetFollowLimit(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name    string
+		limit   int
+		mockDB  func() model.Models
+		wantErr error
+	}{
+		{
+			name:  "success",
+			limit: 1000,
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return metaUpdate(in) && *in.UpdateExpression == "SET #v1 = :v1" &&
+						in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == "1000"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name:  "success - override is removed",
+			limit: 0,
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return *in.UpdateExpression == "REMOVE #v1" && in.ExpressionAttributeValues == nil
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name:  "Should fail when received error in updateItem call",
+			limit: 1000,
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			err := a.Tag.SetFollowLimit(context.TODO(), "user1", "AK", tt.limit)
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
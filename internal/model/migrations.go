@@ -0,0 +1,54 @@
+package model
+
+import (
+	"article-tag/internal/migration"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// migrationAPI
+type migrationAPI interface {
+	dynamoAPI
+	migration.Client
+}
+
+// TableName
+func TableName() string {
+	return tableName
+}
+
+// Migrations returns the schema migrations of the table in order of their version.
+// New migrations are appended at the end, an applied migration must never be changed.
+func Migrations(db migrationAPI, logger *zap.Logger) []migration.Migration {
+	t := &tag{db: db, logger: logger}
+
+	return []migration.Migration{
+		{
+			Version:     1,
+			Description: "create table with TagIndex, LSI1 and LSI2",
+			Up: func(ctx context.Context) error {
+				// tables created before migrations were introduced already exist
+				if err := t.DescribeTable(ctx); err == nil {
+					return nil
+				}
+
+				if err := t.CreateTable(ctx); err != nil {
+					return err
+				}
+
+				return migration.WaitForTable(ctx, db, tableName)
+			},
+		},
+		{
+			Version:     2,
+			Description: "enable time to live on the TTL attribute",
+			Up:          t.EnableTTL,
+		},
+		{
+			Version:     3,
+			Description: "backfill the follow count of every user from the follow rows",
+			Up:          t.backfillFollowCounts,
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package model_test
+
+import (
+	"article-tag/internal/constant"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Migrations(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name   string
+		mockDB func() *mocks.MigrationAPI
+	}{
+		{
+			name: "table is created when missing",
+			mockDB: func() *mocks.MigrationAPI {
+				m := mocks.NewMigrationAPI(t)
+				m.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(nil, &types.ResourceNotFoundException{}).Once()
+				m.EXPECT().CreateTable(mock.Anything, mock.Anything).Return(&dynamodb.CreateTableOutput{}, nil).Once()
+				m.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(&dynamodb.DescribeTableOutput{
+					Table: &types.TableDescription{TableStatus: types.TableStatusActive},
+				}, nil).Once()
+
+				return m
+			},
+		},
+		{
+			name: "existing table is not created again",
+			mockDB: func() *mocks.MigrationAPI {
+				m := mocks.NewMigrationAPI(t)
+				m.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(&dynamodb.DescribeTableOutput{}, nil).Once()
+
+				return m
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations := model.Migrations(tt.mockDB(), log)
+
+			assert.Equal(t, 1, migrations[0].Version)
+
+			err := migrations[0].Up(context.TODO())
+
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func Test_BackfillFollowCounts(t *testing.T) {
+	log := testSuite()
+
+	row := func(username, publication, tagID string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"PK":          &types.AttributeValueMemberS{Value: username + "#" + publication},
+			"SK":          &types.AttributeValueMemberS{Value: tagID},
+			"Username":    &types.AttributeValueMemberS{Value: username},
+			"Publication": &types.AttributeValueMemberS{Value: publication},
+			"TagID":       &types.AttributeValueMemberS{Value: tagID},
+		}
+	}
+
+	unfollowed := row("user1", "AK", "3")
+	unfollowed["DeletedAt"] = &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00Z"}
+
+	table := append(auditedTable(t), row("user2", "AK", "1"), row("user2", "RS", "1"), unfollowed)
+
+	// followCount matches the follow count set on the meta item of the user
+	followCount := func(pk, count string) interface{} {
+		return mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+			return in.Key["PK"].(*types.AttributeValueMemberS).Value == pk && *in.UpdateExpression == "SET #v1 = :v1" &&
+				in.ExpressionAttributeNames["#v1"] == "FollowCount" &&
+				in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == count
+		})
+	}
+
+	m := mocks.NewMigrationAPI(t)
+	m.EXPECT().Scan(mock.Anything, mock.Anything).RunAndReturn(scanTable(table)).Times(len(constant.AllowdedPublications))
+	m.EXPECT().UpdateItem(mock.Anything, followCount("META#user1#AK", "2")).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	m.EXPECT().UpdateItem(mock.Anything, followCount("META#user2#AK", "1")).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	m.EXPECT().UpdateItem(mock.Anything, followCount("META#user2#RS", "1")).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	migrations := model.Migrations(m, log)
+
+	assert.Equal(t, 3, migrations[2].Version)
+
+	err := migrations[2].Up(context.TODO())
+
+	assert.Nil(t, err)
+}
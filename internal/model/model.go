@@ -1,19 +1,43 @@
 package model
 
 import (
+	"article-tag/internal/cache"
 	"context"
 
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"go.uber.org/zap"
 )
 
 type UserTagStore interface {
 	DescribeTable(ctx context.Context) error
 	CreateTable(ctx context.Context) error
-	Store(ctx context.Context, username, publication, tagID, tagName string) error
+	EnableTTL(ctx context.Context) error
+	Store(ctx context.Context, username, publication, tagName, tagID string, descendants bool) (*UserTag, error)
 	Get(ctx context.Context, username, publication, order string) ([]*UserTag, error)
+	GetFollowed(ctx context.Context, username, publication string, tagIDs []string) ([]*UserTag, error)
+	Version(ctx context.Context, username, publication string) (int64, error)
+	GetMeta(ctx context.Context, username, publication string) (*UserMeta, error)
+	DefaultFollowLimit(publication string) int
+	SetFollowLimit(ctx context.Context, username, publication string, limit int) error
 	Delete(ctx context.Context, username, publication, tagID, tagName string) error
+	Restore(ctx context.Context, username, publication, tagID string) error
+	Replace(ctx context.Context, username, publication string, tags []*UserTag) ([]*UserTag, []*UserTag, []*UserTag, error)
 	GetPopularTags(ctx context.Context, username, publication string) ([]string, error)
+	GetRanking(ctx context.Context, publication string) ([]*UserTag, error)
+	GetLeaderboard(ctx context.Context, publication string) (*Leaderboard, error)
+	RefreshLeaderboard(ctx context.Context, publication string, size int) (*Leaderboard, error)
+	GetCounters(ctx context.Context, publication string, tagIDs []string) ([]*UserTag, error)
+	BatchStore(ctx context.Context, items []*UserTag) ([]*BatchReject, error)
+	RebuildCounters(ctx context.Context, publication string) error
+	ScanPublication(ctx context.Context, publication string, segment, totalSegments int, fn func([]*UserTag) error) error
+	GetAll(ctx context.Context, username string) ([]*UserTag, error)
+	PutTaxonomyTag(ctx context.Context, publication, tagID, tagName, parentID string) error
+	GetTaxonomy(ctx context.Context, publication string) ([]*TaxonomyTag, error)
+	GetSubtree(ctx context.Context, publication, tagID string) ([]*TaxonomyTag, error)
+	ImplicitTags(ctx context.Context, publication string, followed []*UserTag) ([]*UserTag, error)
+	PutAliases(ctx context.Context, publication, canonicalID, canonicalName string, aliasIDs, aliasNames []string) error
+	GetAliases(ctx context.Context, publication string) ([]*TagAlias, error)
+	Merge(ctx context.Context, publication, aliasID string) (*MergeResult, error)
+	Erase(ctx context.Context, username string) (int, error)
 }
 
 type UserTag struct {
@@ -24,6 +48,11 @@ type UserTag struct {
 	CreatedAt   string
 	Username    string
 	Publication string
+	TagCount    int    `dynamodbav:",omitempty"`
+	DeletedAt   string `dynamodbav:",omitempty"`
+	TTL         int64  `dynamodbav:",omitempty"`
+	// IncludeDescendants follows the descendants of the tag in the taxonomy as well
+	IncludeDescendants bool `dynamodbav:",omitempty"`
 }
 
 // ExclusiveStartKey
@@ -33,11 +62,15 @@ type ExclusiveStartKey struct {
 }
 
 type Models struct {
-	Tag UserTagStore
+	Tag   UserTagStore
+	Audit AuditStore
+	// CacheMetrics are the hits and misses of the cached tag store, nil when the cache is disabled
+	CacheMetrics *cache.Metrics
 }
 
-func NewModel(db *dynamodb.Client, logger *zap.Logger) Models {
+func NewModel(db dynamoAPI, logger *zap.Logger) Models {
 	return Models{
-		Tag: NewTag(db, logger),
+		Tag:   NewTag(db, logger),
+		Audit: NewAudit(db, logger),
 	}
 }
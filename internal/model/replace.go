@@ -0,0 +1,332 @@
+package model
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/constant"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// Replace makes the given tags the followed tags of the user, the tags missing from the follow set are followed
+// and the followed tags missing from the given ones are unfollowed. Tags are compared by tag id, a followed tag
+// keeps the tag name it was followed with and is updated when its descendants flag changes, aliases are resolved
+// to their canonical tag. The changes are written in a single transaction, a replace whose changes do not fit
+// in one is rejected. Returns the followed, unfollowed and updated tags.
+func (t *tag) Replace(ctx context.Context, username, publication string, tags []*UserTag) ([]*UserTag, []*UserTag, []*UserTag, error) {
+	// resolved copies, the given tags are left as is
+	wanted := make([]*UserTag, 0, len(tags))
+	for _, val := range tags {
+		wanted = append(wanted, &UserTag{TagID: val.TagID, TagName: val.TagName, IncludeDescendants: val.IncludeDescendants})
+	}
+
+	err := t.resolveAliases(ctx, publication, wanted)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	current, err := t.Get(ctx, username, publication, "")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	added, removed, updated := diffTags(current, wanted)
+	unchanged := len(added) == 0 && len(removed) == 0 && len(updated) == 0
+
+	guard, guarded := expectedVersion(ctx)
+	if unchanged && !guarded {
+		return added, removed, updated, nil
+	}
+
+	meta, err := t.GetMeta(ctx, username, publication)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// nothing is written when the version is outdated, nor when nothing changes
+	if guarded && meta.Version != guard.version {
+		return nil, nil, nil, errVersionMismatch(nil)
+	}
+
+	if unchanged {
+		return added, removed, updated, nil
+	}
+
+	limit := meta.FollowLimit
+	if limit == 0 {
+		limit = t.followLimits[publication]
+	}
+
+	follows := len(current) + len(added) - len(removed)
+	if limit > 0 && follows > limit {
+		return nil, nil, nil, apperror.New(apperror.KindValidationFailed, apperror.CodeTooManyTags,
+			fmt.Sprintf("%d tags exceed the follow limit of %d", follows, limit))
+	}
+
+	// two actions per followed or unfollowed tag, the follow row and its counter, one per updated tag,
+	// and the meta item of the user. A replace is never written partially.
+	if actions := 2*(len(added)+len(removed)) + len(updated) + 1; actions > constant.TransactWriteLimit {
+		return nil, nil, nil, apperror.New(apperror.KindValidationFailed, apperror.CodeBatchTooLarge,
+			fmt.Sprintf("replace needs %d writes, at most %d are written at once", actions, constant.TransactWriteLimit))
+	}
+
+	err = t.replaceAtomically(ctx, username, publication, meta, follows, added, removed, updated)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return added, removed, updated, nil
+}
+
+// replaceAtomically writes the changes in a transaction, it is cancelled when the follow set changed since it was read
+// or when its version is not the one expected by the context. The follow count of the meta item is set to the number
+// of tags followed once replaced.
+func (t *tag) replaceAtomically(ctx context.Context, username, publication string, meta *UserMeta, follows int, added, removed, updated []*UserTag) error {
+	now := time.Now().UTC()
+	pk := fmt.Sprintf("%s#%s", username, publication)
+
+	items := []types.TransactWriteItem{}
+	for _, val := range added {
+		item := UserTag{
+			PK:                 pk,
+			SK:                 val.TagID,
+			TagID:              val.TagID,
+			TagName:            val.TagName,
+			CreatedAt:          now.Format(time.RFC3339Nano),
+			Username:           username,
+			Publication:        publication,
+			IncludeDescendants: val.IncludeDescendants,
+		}
+
+		// convert struct to map
+		inputMap, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			t.logger.Error("marshal failed", zap.Error(err))
+			return err
+		}
+
+		items = append(items, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:           aws.String(tableName),
+				Item:                inputMap,
+				ConditionExpression: aws.String("attribute_not_exists(PK) OR attribute_exists(DeletedAt)"),
+			},
+		}, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName: aws.String(tableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("PUB#%s", publication)},
+					"SK": &types.AttributeValueMemberS{Value: val.TagID},
+				},
+				UpdateExpression: aws.String("SET TagCount = if_not_exists(TagCount, :v1) + :incr, TagID = :v2, TagName = :v3"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":v1":   &types.AttributeValueMemberN{Value: "0"},
+					":incr": &types.AttributeValueMemberN{Value: "1"},
+					":v2":   &types.AttributeValueMemberS{Value: val.TagID},
+					":v3":   &types.AttributeValueMemberS{Value: val.TagName},
+				},
+			},
+		})
+	}
+
+	for _, val := range removed {
+		items = append(items, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName: aws.String(tableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: pk},
+					"SK": &types.AttributeValueMemberS{Value: val.TagID},
+				},
+				UpdateExpression:    aws.String("SET #v1 = :v1, #v2 = :v2"),
+				ConditionExpression: aws.String("attribute_exists(PK) AND attribute_not_exists(#v1)"),
+				ExpressionAttributeNames: map[string]string{
+					"#v1": "DeletedAt",
+					"#v2": constant.TTLAttribute,
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":v1": &types.AttributeValueMemberS{Value: now.Format(time.RFC3339Nano)},
+					":v2": &types.AttributeValueMemberN{Value: fmt.Sprint(now.Add(t.undoWindow).Unix())},
+				},
+			},
+		}, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName: aws.String(tableName),
+				Key: map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("PUB#%s", publication)},
+					"SK": &types.AttributeValueMemberS{Value: val.TagID},
+				},
+				UpdateExpression: aws.String("SET TagCount = TagCount - :decr"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":decr": &types.AttributeValueMemberN{Value: "1"},
+				},
+			},
+		})
+	}
+
+	for _, val := range updated {
+		update := descendantsUpdate(username, publication, val.TagID, val.IncludeDescendants)
+
+		items = append(items, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName:                 update.TableName,
+				Key:                       update.Key,
+				UpdateExpression:          update.UpdateExpression,
+				ConditionExpression:       update.ConditionExpression,
+				ExpressionAttributeNames:  update.ExpressionAttributeNames,
+				ExpressionAttributeValues: update.ExpressionAttributeValues,
+			},
+		})
+	}
+
+	// a concurrent follow changes the count
+	condition := "#v2 = :v3"
+	if meta.FollowCount == 0 {
+		condition = "attribute_not_exists(#v2) OR #v2 = :v3"
+	}
+
+	metaUpdate := &types.Update{
+		TableName:           aws.String(tableName),
+		Key:                 userMetaKey(username, publication),
+		UpdateExpression:    aws.String("SET #v2 = :v2 ADD #v1 :incr"),
+		ConditionExpression: aws.String(condition),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "Version",
+			"#v2": "FollowCount",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":incr": &types.AttributeValueMemberN{Value: "1"},
+			":v2":   &types.AttributeValueMemberN{Value: strconv.Itoa(follows)},
+			":v3":   &types.AttributeValueMemberN{Value: strconv.Itoa(meta.FollowCount)},
+		},
+	}
+
+	// the version is checked and incremented by the same write
+	guard, guarded := expectedVersion(ctx)
+	if guarded {
+		versionCondition := "#v1 = :v4"
+		if guard.version == 0 {
+			versionCondition = "(attribute_not_exists(#v1) OR #v1 = :v4)"
+		}
+
+		metaUpdate.ConditionExpression = aws.String(fmt.Sprintf("(%s) AND %s", condition, versionCondition))
+		metaUpdate.ExpressionAttributeValues[":v4"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(guard.version, 10)}
+		metaUpdate.ReturnValuesOnConditionCheckFailure = types.ReturnValuesOnConditionCheckFailureAllOld
+	}
+
+	items = append(items, types.TransactWriteItem{Update: metaUpdate})
+
+	_, err := t.db.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+
+	var cancelErr *types.TransactionCanceledException
+	if errors.As(err, &cancelErr) {
+		// the meta item is the last one of the transaction
+		if guarded && len(cancelErr.CancellationReasons) == len(items) {
+			reason := cancelErr.CancellationReasons[len(items)-1]
+			if aws.ToString(reason.Code) == "ConditionalCheckFailed" && !hasVersion(reason.Item, guard.version) {
+				return errVersionMismatch(err)
+			}
+		}
+
+		for _, val := range cancelErr.CancellationReasons {
+			switch aws.ToString(val.Code) {
+			case "ConditionalCheckFailed", "TransactionConflict":
+				return apperror.Wrap(apperror.KindConflict, apperror.CodeFollowSetChanged, "follow set has changed while it was replaced", err)
+			}
+		}
+	}
+
+	if err != nil {
+		t.logger.Error("error replacing user tags", zap.Error(err))
+		return err
+	}
+
+	if guarded {
+		guard.claimed = true
+	}
+
+	return nil
+}
+
+// hasVersion reports whether the meta item is at the version, an item which does not exist is at version 0
+func hasVersion(item map[string]types.AttributeValue, version int64) bool {
+	var m UserMeta
+
+	err := attributevalue.UnmarshalMap(item, &m)
+	if err != nil {
+		return false
+	}
+
+	return m.Version == version
+}
+
+// descendantsUpdate sets the descendants flag of a followed tag, the flag is removed rather than set to false
+func descendantsUpdate(username, publication, tagID string, descendants bool) *dynamodb.UpdateItemInput {
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%s", username, publication)},
+			"SK": &types.AttributeValueMemberS{Value: tagID},
+		},
+		UpdateExpression:    aws.String("SET #v1 = :v1"),
+		ConditionExpression: aws.String("attribute_exists(PK) AND attribute_not_exists(#v2)"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "IncludeDescendants",
+			"#v2": "DeletedAt",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberBOOL{Value: true},
+		},
+	}
+
+	if !descendants {
+		input.UpdateExpression = aws.String("REMOVE #v1")
+		input.ExpressionAttributeValues = nil
+	}
+
+	return input
+}
+
+// diffTags returns the wanted tags which are not followed, the followed tags which are not wanted
+// and the followed tags whose descendants flag changes, by tag id
+func diffTags(followed, wanted []*UserTag) ([]*UserTag, []*UserTag, []*UserTag) {
+	existing := make(map[string]*UserTag, len(followed))
+	for _, val := range followed {
+		existing[val.TagID] = val
+	}
+
+	added := []*UserTag{}
+	updated := []*UserTag{}
+	keep := make(map[string]struct{}, len(wanted))
+	for _, val := range wanted {
+		if _, ok := keep[val.TagID]; ok {
+			continue
+		}
+
+		keep[val.TagID] = struct{}{}
+
+		current, ok := existing[val.TagID]
+		switch {
+		case !ok:
+			added = append(added, &UserTag{TagID: val.TagID, TagName: val.TagName, IncludeDescendants: val.IncludeDescendants})
+		case current.IncludeDescendants != val.IncludeDescendants:
+			updated = append(updated, &UserTag{TagID: val.TagID, TagName: current.TagName, IncludeDescendants: val.IncludeDescendants})
+		}
+	}
+
+	removed := []*UserTag{}
+	for _, val := range followed {
+		if _, ok := keep[val.TagID]; !ok {
+			removed = append(removed, &UserTag{TagID: val.TagID, TagName: val.TagName})
+		}
+	}
+
+	return added, removed, updated
+}
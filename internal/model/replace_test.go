@@ -0,0 +1,296 @@
+package model_test
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Replace(t *testing.T) {
+	log := testSuite()
+
+	followed := &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+		{"TagID": &types.AttributeValueMemberS{Value: "1"}, "TagName": &types.AttributeValueMemberS{Value: "tag1"}},
+		{"TagID": &types.AttributeValueMemberS{Value: "2"}, "TagName": &types.AttributeValueMemberS{Value: "tag2"}},
+	}}
+
+	many := []*model.UserTag{}
+	for i := 1; i <= 50; i++ {
+		many = append(many, &model.UserTag{TagID: strconv.Itoa(i), TagName: "tag" + strconv.Itoa(i)})
+	}
+
+	// version expected by the context of the replace
+	version := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name        string
+		tags        []*model.UserTag
+		version     *int64
+		mockDB      func() model.Models
+		wantAdded   []*model.UserTag
+		wantRemoved []*model.UserTag
+		wantUpdated []*model.UserTag
+		wantErr     error
+		wantIs      error
+	}{
+		{
+			name: "success - changes are written in a transaction",
+			tags: []*model.UserTag{{TagID: "2", TagName: "renamed"}, {TagID: "3", TagName: "tag3"}, {TagID: "3", TagName: "tag3"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(followed, nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"FollowCount": &types.AttributeValueMemberN{Value: "2"},
+				}}, nil).Once()
+				dmock.EXPECT().TransactWriteItems(mock.Anything, mock.MatchedBy(func(in *dynamodb.TransactWriteItemsInput) bool {
+					items := in.TransactItems
+
+					return len(items) == 5 &&
+						items[0].Put.Item["TagID"].(*types.AttributeValueMemberS).Value == "3" &&
+						items[1].Update.Key["PK"].(*types.AttributeValueMemberS).Value == "PUB#AK" &&
+						items[2].Update.Key["SK"].(*types.AttributeValueMemberS).Value == "1" &&
+						*items[3].Update.UpdateExpression == "SET TagCount = TagCount - :decr" &&
+						items[4].Update.Key["PK"].(*types.AttributeValueMemberS).Value == "META#user1#AK" &&
+						*items[4].Update.ConditionExpression == "#v2 = :v3" &&
+						items[4].Update.ExpressionAttributeValues[":v2"].(*types.AttributeValueMemberN).Value == "2"
+				})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantAdded:   []*model.UserTag{{TagID: "3", TagName: "tag3"}},
+			wantRemoved: []*model.UserTag{{TagID: "1", TagName: "tag1"}},
+			wantUpdated: []*model.UserTag{},
+		},
+		{
+			name: "success - a changed descendants flag updates the followed tag",
+			tags: []*model.UserTag{{TagID: "1", TagName: "renamed", IncludeDescendants: true}, {TagID: "2", TagName: "tag2"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(followed, nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"FollowCount": &types.AttributeValueMemberN{Value: "2"},
+				}}, nil).Once()
+				dmock.EXPECT().TransactWriteItems(mock.Anything, mock.MatchedBy(func(in *dynamodb.TransactWriteItemsInput) bool {
+					items := in.TransactItems
+
+					return len(items) == 2 &&
+						items[0].Update.Key["SK"].(*types.AttributeValueMemberS).Value == "1" &&
+						*items[0].Update.UpdateExpression == "SET #v1 = :v1" &&
+						items[0].Update.ExpressionAttributeNames["#v1"] == "IncludeDescendants" &&
+						items[1].Update.Key["PK"].(*types.AttributeValueMemberS).Value == "META#user1#AK" &&
+						items[1].Update.ExpressionAttributeValues[":v2"].(*types.AttributeValueMemberN).Value == "2"
+				})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantAdded:   []*model.UserTag{},
+			wantRemoved: []*model.UserTag{},
+			wantUpdated: []*model.UserTag{{TagID: "1", TagName: "tag1", IncludeDescendants: true}},
+		},
+		{
+			name: "success - nothing is written without changes",
+			tags: []*model.UserTag{{TagID: "1", TagName: "tag1"}, {TagID: "2", TagName: "tag2"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(followed, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantAdded:   []*model.UserTag{},
+			wantRemoved: []*model.UserTag{},
+			wantUpdated: []*model.UserTag{},
+		},
+		{
+			name: "Should fail with validation failed when changes do not fit in a transaction",
+			tags: many,
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				// nothing is written
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindValidationFailed, Code: apperror.CodeBatchTooLarge},
+		},
+		{
+			name: "Should fail with validation failed when tags exceed the follow limit",
+			tags: []*model.UserTag{{TagID: "1"}, {TagID: "2"}, {TagID: "3"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(followed, nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"FollowCount": &types.AttributeValueMemberN{Value: "2"},
+					"FollowLimit": &types.AttributeValueMemberN{Value: "2"},
+				}}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindValidationFailed, Code: apperror.CodeTooManyTags},
+		},
+		{
+			name: "Should fail with conflict when follow set changed concurrently",
+			tags: []*model.UserTag{},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(followed, nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"FollowCount": &types.AttributeValueMemberN{Value: "2"},
+				}}, nil).Once()
+				dmock.EXPECT().TransactWriteItems(mock.Anything, mock.Anything).Return(nil, &types.TransactionCanceledException{
+					CancellationReasons: []types.CancellationReason{{Code: aws.String("None")}, {Code: aws.String("ConditionalCheckFailed")}},
+				}).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindConflict, Code: apperror.CodeFollowSetChanged},
+		},
+		{
+			name:    "success - version is checked by the transaction",
+			tags:    []*model.UserTag{},
+			version: version(4),
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(followed, nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"Version":     &types.AttributeValueMemberN{Value: "4"},
+					"FollowCount": &types.AttributeValueMemberN{Value: "2"},
+				}}, nil).Once()
+				dmock.EXPECT().TransactWriteItems(mock.Anything, mock.MatchedBy(func(in *dynamodb.TransactWriteItemsInput) bool {
+					meta := in.TransactItems[len(in.TransactItems)-1].Update
+
+					return *meta.ConditionExpression == "(#v2 = :v3) AND #v1 = :v4" &&
+						meta.ExpressionAttributeValues[":v4"].(*types.AttributeValueMemberN).Value == "4"
+				})).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantAdded:   []*model.UserTag{},
+			wantRemoved: []*model.UserTag{{TagID: "1", TagName: "tag1"}, {TagID: "2", TagName: "tag2"}},
+			wantUpdated: []*model.UserTag{},
+		},
+		{
+			name:    "Should fail with precondition failed when version is stale",
+			tags:    []*model.UserTag{{TagID: "1", TagName: "tag1"}, {TagID: "2", TagName: "tag2"}},
+			version: version(3),
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				// nothing is written
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(followed, nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"Version":     &types.AttributeValueMemberN{Value: "4"},
+					"FollowCount": &types.AttributeValueMemberN{Value: "2"},
+				}}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindPreconditionFailed, Code: apperror.CodeVersionMismatch},
+		},
+		{
+			name:    "Should fail with precondition failed when version changed concurrently",
+			tags:    []*model.UserTag{},
+			version: version(4),
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(followed, nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"Version":     &types.AttributeValueMemberN{Value: "4"},
+					"FollowCount": &types.AttributeValueMemberN{Value: "2"},
+				}}, nil).Once()
+				dmock.EXPECT().TransactWriteItems(mock.Anything, mock.Anything).Return(nil, &types.TransactionCanceledException{
+					CancellationReasons: []types.CancellationReason{
+						{Code: aws.String("None")}, {Code: aws.String("None")}, {Code: aws.String("None")}, {Code: aws.String("None")},
+						{Code: aws.String("ConditionalCheckFailed"), Item: map[string]types.AttributeValue{
+							"Version":     &types.AttributeValueMemberN{Value: "5"},
+							"FollowCount": &types.AttributeValueMemberN{Value: "2"},
+						}},
+					},
+				}).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindPreconditionFailed, Code: apperror.CodeVersionMismatch},
+		},
+		{
+			name: "Should fail when received error in query call",
+			tags: []*model.UserTag{},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			ctx := context.TODO()
+			if tt.version != nil {
+				ctx = model.WithVersion(ctx, *tt.version)
+			}
+
+			// call model function
+			added, removed, updated, err := a.Tag.Replace(ctx, "user1", "AK", tt.tags)
+
+			if tt.wantIs != nil {
+				assert.ErrorIs(t, err, tt.wantIs)
+				return
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantAdded, added)
+			assert.Equal(t, tt.wantRemoved, removed)
+			assert.Equal(t, tt.wantUpdated, updated)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"article-tag/internal/config"
+	"article-tag/internal/constant"
+	"article-tag/internal/schema"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// TableDefinition is the expected shape of the table, used to create it
+// and to detect drift of an existing table with `main schema diff`.
+// The billing mode and capacities are read from the environment:
+//
+//	TABLE_BILLING_MODE       PROVISIONED (default) or PAY_PER_REQUEST
+//	TABLE_READ_CAPACITY      TABLE_WRITE_CAPACITY
+//	TAG_INDEX_READ_CAPACITY  TAG_INDEX_WRITE_CAPACITY
+//	TABLE_AUTOSCALING        capacities are only used on creation, autoscaling owns them afterwards
+func TableDefinition() *schema.Table {
+	return &schema.Table{
+		Name: tableName,
+		Attributes: map[string]types.ScalarAttributeType{
+			"PK":        types.ScalarAttributeTypeS,
+			"SK":        types.ScalarAttributeTypeS,
+			"TagCount":  types.ScalarAttributeTypeN,
+			"TagName":   types.ScalarAttributeTypeS,
+			"CreatedAt": types.ScalarAttributeTypeS,
+		},
+		PartitionKey: "PK",
+		SortKey:      "SK",
+		BillingMode:  billingMode(),
+		Capacity: schema.Capacity{
+			Read:  int64(config.Int("TABLE_READ_CAPACITY", constant.TableReadCapacity)),
+			Write: int64(config.Int("TABLE_WRITE_CAPACITY", constant.TableWriteCapacity)),
+		},
+		Autoscaling: config.Bool("TABLE_AUTOSCALING", false),
+		GlobalIndex: []schema.Index{{
+			Name:         "TagIndex",
+			PartitionKey: "PK",
+			SortKey:      "TagCount",
+			Projection: schema.Projection{
+				Type:             types.ProjectionTypeInclude,
+				NonKeyAttributes: []string{"TagID", "TagName"},
+			},
+			Capacity: schema.Capacity{
+				Read:  int64(config.Int("TAG_INDEX_READ_CAPACITY", constant.TagIndexReadCapacity)),
+				Write: int64(config.Int("TAG_INDEX_WRITE_CAPACITY", constant.TagIndexWriteCapacity)),
+			},
+		}},
+		LocalIndex: []schema.Index{{
+			Name:         "LSI1",
+			PartitionKey: "PK",
+			SortKey:      "TagName",
+			Projection: schema.Projection{
+				Type:             types.ProjectionTypeInclude,
+				NonKeyAttributes: []string{"TagName"},
+			},
+		}, {
+			Name:         "LSI2",
+			PartitionKey: "PK",
+			SortKey:      "CreatedAt",
+			Projection: schema.Projection{
+				Type:             types.ProjectionTypeInclude,
+				NonKeyAttributes: []string{"CreatedAt"},
+			},
+		}},
+	}
+}
+
+// billingMode, anything other than PAY_PER_REQUEST is provisioned
+func billingMode() types.BillingMode {
+	if strings.EqualFold(config.String("TABLE_BILLING_MODE", ""), string(types.BillingModePayPerRequest)) {
+		return types.BillingModePayPerRequest
+	}
+
+	return types.BillingModeProvisioned
+}
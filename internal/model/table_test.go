@@ -0,0 +1,36 @@
+package model_test
+
+import (
+	"article-tag/internal/model"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TableDefinition(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		got := model.TableDefinition()
+
+		assert.Equal(t, types.BillingModeProvisioned, got.BillingMode)
+		assert.Equal(t, int64(10), got.Capacity.Read)
+		assert.Equal(t, int64(5), got.Capacity.Write)
+		assert.Equal(t, int64(5), got.GlobalIndex[0].Capacity.Read)
+		assert.False(t, got.Autoscaling)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		t.Setenv("TABLE_BILLING_MODE", "pay_per_request")
+		t.Setenv("TABLE_READ_CAPACITY", "100")
+		t.Setenv("TAG_INDEX_WRITE_CAPACITY", "50")
+		t.Setenv("TABLE_AUTOSCALING", "true")
+
+		got := model.TableDefinition()
+
+		assert.Equal(t, types.BillingModePayPerRequest, got.BillingMode)
+		assert.Equal(t, int64(100), got.Capacity.Read)
+		assert.Equal(t, int64(50), got.GlobalIndex[0].Capacity.Write)
+		assert.True(t, got.Autoscaling)
+		assert.Nil(t, got.CreateTableInput().ProvisionedThroughput)
+	})
+}
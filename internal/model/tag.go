@@ -1,10 +1,12 @@
 package model
 
 import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/config"
 	"article-tag/internal/constant"
 	"context"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -22,20 +24,35 @@ const tableName = "article-follow-tag-v5"
 type dynamoAPI interface {
 	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
 	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
 	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
 	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
 	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
 	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
+	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)
+	UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)
 }
 
 type tag struct {
 	db     dynamoAPI
 	logger *zap.Logger
 	// db dynamodb.Client
+	undoWindow time.Duration
+	// followLimits by publication, 0 when the publication is not limited
+	followLimits map[string]int
 }
 
 func NewTag(m dynamoAPI, logger *zap.Logger) UserTagStore {
-	return &tag{db: m, logger: logger}
+	return &tag{
+		db:           m,
+		logger:       logger,
+		undoWindow:   config.Duration("UNDO_WINDOW", constant.UndoWindow),
+		followLimits: followLimits(),
+	}
 }
 
 // DescribeTable
@@ -52,110 +69,79 @@ func (t *tag) DescribeTable(ctx context.Context) error {
 	return nil
 }
 
-// CreateTable
+// CreateTable creates the table from its definition
 func (t *tag) CreateTable(ctx context.Context) error {
-	i := dynamodb.CreateTableInput{
+	i := TableDefinition().CreateTableInput()
+
+	_, err := t.db.CreateTable(ctx, i)
+	if err != nil {
+		t.logger.Error("error creating table", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// EnableTTL turns on the time to live of the table when it is not enabled yet
+func (t *tag) EnableTTL(ctx context.Context) error {
+	res, err := t.db.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
 		TableName: aws.String(tableName),
-		AttributeDefinitions: []types.AttributeDefinition{{
-			AttributeName: aws.String("PK"),
-			AttributeType: types.ScalarAttributeTypeS,
-		}, {
-			AttributeName: aws.String("SK"),
-			AttributeType: types.ScalarAttributeTypeS,
-		}, {
-			AttributeName: aws.String("TagCount"),
-			AttributeType: types.ScalarAttributeTypeN,
-		},
-			// {
-			// 	AttributeName: aws.String("TagID"),
-			// 	AttributeType: types.ScalarAttributeTypeS,
-			// },
-			{
-				AttributeName: aws.String("TagName"),
-				AttributeType: types.ScalarAttributeTypeS,
-			}, {
-				AttributeName: aws.String("CreatedAt"),
-				AttributeType: types.ScalarAttributeTypeS,
-			}},
-		KeySchema: []types.KeySchemaElement{{
-			AttributeName: aws.String("PK"),
-			KeyType:       types.KeyTypeHash,
-		}, {
-			AttributeName: aws.String("SK"),
-			KeyType:       types.KeyTypeRange,
-		}},
-		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{{
-			IndexName: aws.String("TagIndex"),
-			KeySchema: []types.KeySchemaElement{{
-				AttributeName: aws.String("PK"),
-				KeyType:       types.KeyTypeHash,
-			}, {
-				AttributeName: aws.String("TagCount"),
-				KeyType:       types.KeyTypeRange,
-			}},
-			Projection: &types.Projection{
-				ProjectionType:   types.ProjectionTypeInclude,
-				NonKeyAttributes: []string{"TagID", "TagName"},
-			},
-			ProvisionedThroughput: &types.ProvisionedThroughput{
-				ReadCapacityUnits:  aws.Int64(5),
-				WriteCapacityUnits: aws.Int64(5),
-			},
-		}},
-		LocalSecondaryIndexes: []types.LocalSecondaryIndex{
-			{
-				IndexName: aws.String("LSI1"),
-				KeySchema: []types.KeySchemaElement{{
-					AttributeName: aws.String("PK"),
-					KeyType:       types.KeyTypeHash,
-				}, {
-					AttributeName: aws.String("TagName"),
-					KeyType:       types.KeyTypeRange,
-				}},
-				Projection: &types.Projection{
-					ProjectionType:   types.ProjectionTypeInclude,
-					NonKeyAttributes: []string{"TagName"},
-				},
-			},
-			{
-				IndexName: aws.String("LSI2"),
-				KeySchema: []types.KeySchemaElement{{
-					AttributeName: aws.String("PK"),
-					KeyType:       types.KeyTypeHash,
-				}, {
-					AttributeName: aws.String("CreatedAt"),
-					KeyType:       types.KeyTypeRange,
-				}},
-				Projection: &types.Projection{
-					ProjectionType:   types.ProjectionTypeInclude,
-					NonKeyAttributes: []string{"CreatedAt"},
-				},
-			},
-		},
-		ProvisionedThroughput: &types.ProvisionedThroughput{
-			ReadCapacityUnits:  aws.Int64(10),
-			WriteCapacityUnits: aws.Int64(5),
-		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if res.TimeToLiveDescription != nil {
+		switch res.TimeToLiveDescription.TimeToLiveStatus {
+		case types.TimeToLiveStatusEnabled, types.TimeToLiveStatusEnabling:
+			return nil
+		}
 	}
 
-	_, err := t.db.CreateTable(ctx, &i)
+	_, err = t.db.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(tableName),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(constant.TTLAttribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
 	if err != nil {
-		t.logger.Error("error creating table", zap.Error(err))
+		t.logger.Error("error enabling time to live", zap.Error(err))
 		return err
 	}
 
 	return nil
 }
 
-func (t *tag) Store(ctx context.Context, username, publication, tagName, tagID string) error {
+// Store follows the tag, descendants follows its descendants in the taxonomy as well.
+// An alias is resolved first, the canonical tag is followed instead. Returns the followed tag.
+func (t *tag) Store(ctx context.Context, username, publication, tagName, tagID string, descendants bool) (*UserTag, error) {
+	resolved := []*UserTag{{TagID: tagID, TagName: tagName, IncludeDescendants: descendants}}
+
+	err := t.resolveAliases(ctx, publication, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.store(ctx, username, publication, resolved[0].TagName, resolved[0].TagID, descendants)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved[0], nil
+}
+
+// store follows the tag as is
+func (t *tag) store(ctx context.Context, username, publication, tagName, tagID string, descendants bool) (err error) {
 	item := UserTag{
-		PK:          fmt.Sprintf("%v#%v", username, publication),
-		SK:          tagID,
-		TagID:       tagID,
-		TagName:     tagName,
-		CreatedAt:   time.Now().UTC().Format(time.RFC3339Nano),
-		Username:    username,
-		Publication: publication,
+		PK:                 fmt.Sprintf("%v#%v", username, publication),
+		SK:                 tagID,
+		TagID:              tagID,
+		TagName:            tagName,
+		CreatedAt:          time.Now().UTC().Format(time.RFC3339Nano),
+		Username:           username,
+		Publication:        publication,
+		IncludeDescendants: descendants,
 	}
 
 	// convert struct to map
@@ -171,14 +157,43 @@ func (t *tag) Store(ctx context.Context, username, publication, tagName, tagID s
 		ReturnValues: types.ReturnValueAllOld, // used to get old content
 	}
 
+	release, err := t.claimVersion(ctx, username, publication)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
+	// count the follow first, the limit is checked by the condition of the meta item
+	err = t.reserveFollow(ctx, username, publication)
+	if errors.Is(err, errFollowLimitReached) {
+		// following again an already followed tag is a no-op, the claimed version is given back as nothing changed
+		followed, ferr := t.isFollowed(ctx, username, publication, tagID)
+		if ferr == nil && followed {
+			release()
+			return nil
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
 	putItemOutput, err := t.db.PutItem(ctx, &input2)
 	if err != nil {
+		t.releaseFollow(ctx, username, publication)
 		return err
 	}
 
 	// update popular tag count only if the user is following new tag
-	// means when user follows already followed tag we dont need to update count
-	if putItemOutput.Attributes == nil {
+	// means when user follows already followed tag we dont need to update count,
+	// an unfollowed tag waiting for its undo window to pass is followed again
+	_, wasDeleted := putItemOutput.Attributes["DeletedAt"]
+	if putItemOutput.Attributes == nil || wasDeleted {
 		input3 := dynamodb.UpdateItemInput{
 			TableName: aws.String(tableName),
 			Key: map[string]types.AttributeValue{
@@ -199,9 +214,12 @@ func (t *tag) Store(ctx context.Context, username, publication, tagName, tagID s
 			t.logger.Error("error updating tag counter while storing item", zap.Error(err))
 			return err
 		}
+
+		return t.bumpVersion(ctx, username, publication, 0)
 	}
 
-	return nil
+	// the tag was already followed, its row is written again so the version changes and the reserved follow is released
+	return t.bumpVersion(ctx, username, publication, -1)
 }
 
 func (t *tag) Get(ctx context.Context, username, publication, order string) ([]*UserTag, error) {
@@ -221,33 +239,43 @@ func (t *tag) Get(ctx context.Context, username, publication, order string) ([]*
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":v1": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%s", username, publication)},
 		},
+		// unfollowed tags are kept until the undo window passes
+		FilterExpression:     aws.String("attribute_not_exists(DeletedAt)"),
 		ScanIndexForward:     aws.Bool(scanIndex),
-		ProjectionExpression: aws.String("PK, SK, TagID, TagName"),
-	}
-
-	// fetch item
-	res, err := t.db.Query(ctx, &queryInput)
-	if err != nil {
-		return nil, err
+		ProjectionExpression: aws.String("PK, SK, TagID, TagName, IncludeDescendants"),
 	}
 
 	userTags := []*UserTag{}
-	for _, val := range res.Items {
-		var m UserTag
 
-		err := attributevalue.UnmarshalMap(val, &m)
+	// a query reads at most 1MB, iterate until every followed tag is fetched
+	for {
+		res, err := t.db.Query(ctx, &queryInput)
 		if err != nil {
-			t.logger.Error("unmarshal failed while fetching user tags", zap.Error(err))
 			return nil, err
 		}
 
-		userTags = append(userTags, &UserTag{
-			TagID:   m.TagID,
-			TagName: m.TagName,
-		})
-	}
+		for _, val := range res.Items {
+			var m UserTag
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				t.logger.Error("unmarshal failed while fetching user tags", zap.Error(err))
+				return nil, err
+			}
+
+			userTags = append(userTags, &UserTag{
+				TagID:              m.TagID,
+				TagName:            m.TagName,
+				IncludeDescendants: m.IncludeDescendants,
+			})
+		}
 
-	return userTags, nil
+		if res.LastEvaluatedKey == nil {
+			return userTags, nil
+		}
+
+		queryInput.ExclusiveStartKey = res.LastEvaluatedKey
+	}
 }
 
 // getIndexNameAndScanOrder
@@ -284,26 +312,60 @@ func getIndexNameAndScanOrder(order string) (string, bool) {
 	return indexName, scanIndex
 }
 
-func (t *tag) Delete(ctx context.Context, username, publication, tagID, tagName string) error {
+// Delete marks the user tag as unfollowed, the row is kept for the undo window
+// and removed by the table time to live afterwards. The tag name is checked unless it is empty.
+func (t *tag) Delete(ctx context.Context, username, publication, tagID, tagName string) (err error) {
+	now := time.Now().UTC()
 
-	input := dynamodb.DeleteItemInput{
+	input := dynamodb.UpdateItemInput{
 		TableName: aws.String(tableName),
 		Key: map[string]types.AttributeValue{
 			"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%s", username, publication)},
 			"SK": &types.AttributeValueMemberS{Value: tagID},
 		},
-		ConditionExpression: aws.String("#v1 = :v1"),
+		UpdateExpression:    aws.String("SET #v2 = :v2, #v3 = :v3"),
+		ConditionExpression: aws.String("#v1 = :v1 AND attribute_not_exists(#v2)"),
 		ExpressionAttributeNames: map[string]string{
 			"#v1": "TagName",
+			"#v2": "DeletedAt",
+			"#v3": constant.TTLAttribute,
 		},
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":v1": &types.AttributeValueMemberS{Value: tagName},
+			":v2": &types.AttributeValueMemberS{Value: now.Format(time.RFC3339Nano)},
+			":v3": &types.AttributeValueMemberN{Value: fmt.Sprint(now.Add(t.undoWindow).Unix())},
 		},
-		ReturnValues: types.ReturnValueAllOld,
+		ReturnValues:                        types.ReturnValueAllOld,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+
+	if tagName == "" {
+		input.ConditionExpression = aws.String("attribute_exists(PK) AND attribute_not_exists(#v2)")
+		delete(input.ExpressionAttributeNames, "#v1")
+		delete(input.ExpressionAttributeValues, ":v1")
+	}
+
+	release, err := t.claimVersion(ctx, username, publication)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
+	// mark item as deleted
+	delItemResp, err := t.db.UpdateItem(ctx, &input)
+	if item, ok := conditionFailed(err); ok {
+		if item == nil || item["DeletedAt"] != nil {
+			return apperror.Wrap(apperror.KindNotFound, apperror.CodeTagNotFollowed, "tag is not followed", err)
+		}
+
+		return apperror.Wrap(apperror.KindConflict, apperror.CodeTagNameMismatch, "tag name does not match the followed tag", err)
 	}
 
-	// fetch item
-	delItemResp, err := t.db.DeleteItem(ctx, &input)
 	if err != nil {
 		return err
 	}
@@ -328,29 +390,182 @@ func (t *tag) Delete(ctx context.Context, username, publication, tagID, tagName
 			t.logger.Error("error updating tag counter while deleting item", zap.Error(err))
 			return err
 		}
+
+		return t.bumpVersion(ctx, username, publication, -1)
 	}
 
 	return nil
 }
 
+// Restore follows again a tag unfollowed within the undo window,
+// the original follow date is kept
+func (t *tag) Restore(ctx context.Context, username, publication, tagID string) (err error) {
+	input := dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%s", username, publication)},
+			"SK": &types.AttributeValueMemberS{Value: tagID},
+		},
+		UpdateExpression:    aws.String("REMOVE #v1, #v2"),
+		ConditionExpression: aws.String("attribute_exists(#v1) AND #v2 > :v2"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "DeletedAt",
+			"#v2": constant.TTLAttribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v2": &types.AttributeValueMemberN{Value: fmt.Sprint(time.Now().Unix())},
+		},
+		ReturnValues:                        types.ReturnValueAllNew,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+
+	release, err := t.claimVersion(ctx, username, publication)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
+	// count the follow first, the limit is checked by the condition of the meta item
+	err = t.reserveFollow(ctx, username, publication)
+	if err != nil {
+		return err
+	}
+
+	res, err := t.db.UpdateItem(ctx, &input)
+	if err != nil {
+		t.releaseFollow(ctx, username, publication)
+	}
+
+	if item, ok := conditionFailed(err); ok {
+		switch {
+		case item == nil:
+			return apperror.Wrap(apperror.KindNotFound, apperror.CodeTagNotFollowed, "tag was never followed", err)
+		case item["DeletedAt"] == nil:
+			return apperror.Wrap(apperror.KindConflict, apperror.CodeTagAlreadyFollowed, "tag is already followed", err)
+		default:
+			return apperror.Wrap(apperror.KindConflict, apperror.CodeUndoWindowExpired, "undo window has expired", err)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var m UserTag
+
+	err = attributevalue.UnmarshalMap(res.Attributes, &m)
+	if err != nil {
+		t.logger.Error("unmarshal failed while restoring user tag", zap.Error(err))
+		return err
+	}
+
+	input2 := dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]types.AttributeValue{
+			"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("PUB#%s", publication)},
+			"SK": &types.AttributeValueMemberS{Value: tagID},
+		},
+		UpdateExpression: aws.String("SET TagCount = if_not_exists(TagCount, :v1) + :incr, TagID = :v2, TagName = :v3"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1":   &types.AttributeValueMemberN{Value: "0"},
+			":incr": &types.AttributeValueMemberN{Value: "1"},
+			":v2":   &types.AttributeValueMemberS{Value: tagID},
+			":v3":   &types.AttributeValueMemberS{Value: m.TagName},
+		},
+	}
+
+	_, err = t.db.UpdateItem(ctx, &input2)
+	if err != nil {
+		t.logger.Error("error updating tag counter while restoring item", zap.Error(err))
+		return err
+	}
+
+	return t.bumpVersion(ctx, username, publication, 0)
+}
+
 func (t *tag) GetPopularTags(ctx context.Context, username, publication string) ([]string, error) {
 	// Steps:
-	// 1. Fetch the existing tags of the user
-	// 2. To get popular tags, excluded the existing tags using filterExpression
-	var (
-		existingTags []*UserTag
-		err          error
-		hasMoreItems = true
-		userTags     = []string{}
-	)
+	// 1. Fetch the ranking, a single read once the leaderboard is materialized
+	// 2. Exclude the existing tags of the user in memory by tag id, a filter expression
+	//    is limited to 100 operands which heavy users exceed. Only the ranked tags are read
+	//    when they fit in a BatchGetItem
+	// 3. The leaderboard only holds the top tags, a user following most of them gets the full ranking
+	//    unless the leaderboard holds every counter
+	leaderboard, err := t.GetLeaderboard(ctx, publication)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranking []*UserTag
+	if leaderboard != nil {
+		ranking = leaderboard.tags(publication)
+	} else {
+		ranking, err = t.ranking(ctx, publication, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// check for empty username, when no username passed
 	// return all tags of that particular publication
 	if username != "" {
-		existingTags, err = t.Get(ctx, username, publication, "")
+		existingTags, err := followedOf(ctx, t, username, publication, ranking)
 		if err != nil {
 			return nil, err
 		}
+
+		ranking = excludeFollowed(ranking, existingTags)
+
+		if leaderboard != nil && !leaderboard.Complete && len(ranking) < constant.LeaderboardMinimum {
+			ranking, err = t.ranking(ctx, publication, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			existingTags, err = followedOf(ctx, t, username, publication, ranking)
+			if err != nil {
+				return nil, err
+			}
+
+			ranking = excludeFollowed(ranking, existingTags)
+		}
+	}
+
+	return tagNames(ranking), nil
+}
+
+// GetRanking returns the counters of the publication by descending follower count,
+// from the leaderboard once it is materialized
+func (t *tag) GetRanking(ctx context.Context, publication string) ([]*UserTag, error) {
+	leaderboard, err := t.GetLeaderboard(ctx, publication)
+	if err != nil {
+		return nil, err
+	}
+
+	if leaderboard != nil {
+		return leaderboard.tags(publication), nil
+	}
+
+	return t.ranking(ctx, publication, 0)
+}
+
+// ranking queries the counters of the TagIndex by descending count.
+// A limit stops the query once the top counters are read, 0 reads every counter.
+func (t *tag) ranking(ctx context.Context, publication string, limit int) ([]*UserTag, error) {
+	var (
+		err          error
+		hasMoreItems = true
+		ranking      = []*UserTag{}
+		pageSize     = int32(constant.PopularTagLimit)
+	)
+
+	if limit > 0 {
+		pageSize = int32(limit)
 	}
 
 	var exclusiveStartKey map[string]types.AttributeValue = nil
@@ -370,7 +585,7 @@ func (t *tag) GetPopularTags(ctx context.Context, username, publication string)
 				":v2": &types.AttributeValueMemberN{Value: "0"},
 			},
 			ScanIndexForward: aws.Bool(false),
-			Limit:            aws.Int32(constant.PopularTagLimit),
+			Limit:            aws.Int32(pageSize),
 		}
 
 		// once we received lastEvaluatedKey from previous iteration
@@ -394,11 +609,6 @@ func (t *tag) GetPopularTags(ctx context.Context, username, publication string)
 			// {"PK":{"S":"PUB#AK"}, "SK":{"S":"2"},"TagCount":{"N":"1"}}
 		}
 
-		// exclude existing tags from the popular tags
-		if len(existingTags) > 0 {
-			prepareFilterExpression(&queryInput, existingTags)
-		}
-
 		// fetch item
 		res, err := t.db.Query(ctx, &queryInput)
 		if err != nil {
@@ -419,7 +629,12 @@ func (t *tag) GetPopularTags(ctx context.Context, username, publication string)
 				return nil, err
 			}
 
-			userTags = append(userTags, m.TagName)
+			ranking = append(ranking, &UserTag{
+				TagID:       m.TagID,
+				TagName:     m.TagName,
+				Publication: publication,
+				TagCount:    m.TagCount,
+			})
 		}
 
 		// break the loop once the last item is fetched
@@ -427,33 +642,71 @@ func (t *tag) GetPopularTags(ctx context.Context, username, publication string)
 			// set false to break the loop
 			hasMoreItems = false
 		}
+
+		// or once the top counters are read
+		if limit > 0 && len(ranking) >= limit {
+			ranking = ranking[:limit]
+			hasMoreItems = false
+		}
 	}
 
-	return userTags, nil
+	return ranking, nil
 }
 
-func prepareFilterExpression(queryInput *dynamodb.QueryInput, existingTags []*UserTag) {
-	// filterExpression := fmt.Sprintf("%s  NOT (Tag IN (", *queryInput.FilterExpression)
-	filterExpression := fmt.Sprintf("NOT (TagName IN (")
+// excludeFollowed removes the followed tags from the ranking by tag id, the tag name of a follow
+// is the one of the day it was followed and can differ from the one of the counter
+// followedOf returns the followed tags among the ranking, a ranking which fits in a BatchGetItem
+// is checked by key instead of reading every followed tag
+func followedOf(ctx context.Context, store UserTagStore, username, publication string, ranking []*UserTag) ([]*UserTag, error) {
+	if len(ranking) > constant.BatchGetLimit {
+		return store.Get(ctx, username, publication, "")
+	}
+
+	tagIDs := []string{}
+	for _, val := range ranking {
+		tagIDs = append(tagIDs, val.TagID)
+	}
 
-	filterAttr := []string{}
+	return store.GetFollowed(ctx, username, publication, tagIDs)
+}
+
+// tagNames of the ranking
+func tagNames(ranking []*UserTag) []string {
+	res := []string{}
+	for _, val := range ranking {
+		res = append(res, val.TagName)
+	}
 
-	for k, val := range existingTags {
+	return res
+}
 
-		// prepare key
-		key := fmt.Sprintf(":exclude%v", k)
+func excludeFollowed(ranking, followed []*UserTag) []*UserTag {
+	if len(followed) == 0 {
+		return ranking
+	}
 
-		filterAttr = append(filterAttr, key)
+	existing := make(map[string]struct{}, len(followed))
+	for _, val := range followed {
+		existing[val.TagID] = struct{}{}
+	}
 
-		queryInput.ExpressionAttributeValues[key] = &types.AttributeValueMemberS{Value: val.TagName}
+	res := []*UserTag{}
+	for _, val := range ranking {
+		if _, ok := existing[val.TagID]; !ok {
+			res = append(res, val)
+		}
 	}
 
-	// join the filter expression placeholder
-	filterExpression += strings.Join(filterAttr, ", ")
+	return res
+}
 
-	// end of bracket
-	filterExpression += "))"
+// conditionFailed reports whether the condition expression failed, with the item
+// returned by ReturnValuesOnConditionCheckFailure (nil when it does not exist)
+func conditionFailed(err error) (map[string]types.AttributeValue, bool) {
+	var condErr *types.ConditionalCheckFailedException
+	if !errors.As(err, &condErr) {
+		return nil, false
+	}
 
-	// update the filter expression
-	queryInput.FilterExpression = aws.String(filterExpression)
+	return condErr.Item, true
 }
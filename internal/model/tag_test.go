@@ -1,11 +1,13 @@
 package model_test
 
 import (
+	"article-tag/internal/apperror"
 	"article-tag/internal/mocks"
 	"article-tag/internal/model"
 	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -169,7 +171,9 @@ func Test_Store(t *testing.T) {
 		name    string
 		args    args
 		mockDB  func() model.Models
+		want    *model.UserTag
 		wantErr error
+		wantIs  error
 	}{
 		{
 			name: "success",
@@ -178,6 +182,7 @@ func Test_Store(t *testing.T) {
 				models := model.NewModel(nil, log)
 
 				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
 				dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
 				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil)
 				models.Tag = model.NewTag(dmock, log)
@@ -186,6 +191,153 @@ func Test_Store(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "success - following again an unfollowed tag increments counter",
+			args: args{item: model.UserTag{Username: "Mock username"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{
+					Attributes: map[string]types.AttributeValue{
+						"DeletedAt": &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00Z"},
+					},
+				}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil).Twice()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success - alias is resolved to its canonical tag",
+			args: args{item: model.UserTag{Username: "Mock username", Publication: "AK", TagID: "7", TagName: "A.I."}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.BatchGetItemInput) bool {
+					keys := in.RequestItems["article-follow-tag-v5"].Keys
+					return len(keys) == 2 && keys[0]["SK"].(*types.AttributeValueMemberS).Value == "ID#7" &&
+						keys[1]["SK"].(*types.AttributeValueMemberS).Value == "NAME#ai"
+				})).Return(&dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{
+					"article-follow-tag-v5": {{
+						"SK":            &types.AttributeValueMemberS{Value: "NAME#ai"},
+						"CanonicalID":   &types.AttributeValueMemberS{Value: "5"},
+						"CanonicalName": &types.AttributeValueMemberS{Value: "Artificial Intelligence"},
+					}},
+				}}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil).Twice()
+				dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["TagID"].(*types.AttributeValueMemberS).Value == "5" &&
+						in.Item["TagName"].(*types.AttributeValueMemberS).Value == "Artificial Intelligence"
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want:    &model.UserTag{TagID: "5", TagName: "Artificial Intelligence"},
+			wantErr: nil,
+		},
+		{
+			name: "Should fail when received error in batchGetItem call",
+			args: args{item: model.UserTag{Username: "Mock username"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+		{
+			name: "success - tag is followed with its descendants",
+			args: args{item: model.UserTag{Username: "Mock username", IncludeDescendants: true}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil).Twice()
+				dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["IncludeDescendants"].(*types.AttributeValueMemberBOOL).Value
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success - following again a followed tag releases the reserved follow and bumps the version",
+			args: args{item: model.UserTag{Username: "Mock username"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return metaUpdate(in) && *in.UpdateExpression == "ADD #v1 :incr" && in.ExpressionAttributeNames["#v1"] == "FollowCount"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{
+					Attributes: map[string]types.AttributeValue{
+						"TagName": &types.AttributeValueMemberS{Value: "tag1"},
+					},
+				}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return metaUpdate(in) && *in.UpdateExpression == "ADD #v1 :incr, #v2 :v2" &&
+						in.ExpressionAttributeValues[":v2"].(*types.AttributeValueMemberN).Value == "-1"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success - following again a followed tag once the limit is reached",
+			args: args{item: model.UserTag{Username: "Mock username"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{
+					Item: map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "Mock username#"}},
+				}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Should fail with conflict when the follow limit is reached",
+			args: args{item: model.UserTag{Username: "Mock username", Publication: "AK"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == "500"
+				})).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindConflict, Code: apperror.CodeFollowLimitReached},
+		},
 		{
 			name: "Should fail when received error in putItem call",
 			args: args{item: model.UserTag{Username: "Mock username"}},
@@ -193,6 +345,8 @@ func Test_Store(t *testing.T) {
 				models := model.NewModel(nil, log)
 
 				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil).Twice()
 				dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error"))
 				models.Tag = model.NewTag(dmock, log)
 
@@ -207,6 +361,8 @@ func Test_Store(t *testing.T) {
 				models := model.NewModel(nil, log)
 
 				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
 				dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
 				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, errors.New("mock error"))
 				models.Tag = model.NewTag(dmock, log)
@@ -222,7 +378,12 @@ func Test_Store(t *testing.T) {
 			a := tt.mockDB()
 
 			// call model function
-			err := a.Tag.Store(context.TODO(), tt.args.item.Username, tt.args.item.Publication, tt.args.item.TagID, tt.args.item.TagName)
+			got, err := a.Tag.Store(context.TODO(), tt.args.item.Username, tt.args.item.Publication, tt.args.item.TagName, tt.args.item.TagID, tt.args.item.IncludeDescendants)
+
+			if tt.wantIs != nil {
+				assert.ErrorIs(t, err, tt.wantIs)
+				return
+			}
 
 			if tt.wantErr == nil {
 				assert.Equal(t, tt.wantErr, err)
@@ -231,6 +392,10 @@ func Test_Store(t *testing.T) {
 			if tt.wantErr != nil {
 				assert.Equal(t, tt.wantErr, err)
 			}
+
+			if tt.want != nil {
+				assert.Equal(t, tt.want, got)
+			}
 		})
 	}
 }
@@ -320,6 +485,7 @@ func Test_Delete(t *testing.T) {
 		args    args
 		mockDB  func() model.Models
 		wantErr error
+		wantIs  error
 		want    []string
 	}{
 		{
@@ -329,13 +495,16 @@ func Test_Delete(t *testing.T) {
 				models := model.NewModel(nil, log)
 
 				dmock := mocks.NewDynamoAPI(t)
-				dmock.EXPECT().DeleteItem(mock.Anything, mock.Anything).Return(&dynamodb.DeleteItemOutput{
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return *in.UpdateExpression == "SET #v2 = :v2, #v3 = :v3"
+				})).Return(&dynamodb.UpdateItemOutput{
 					Attributes: map[string]types.AttributeValue{
 						"PK": &types.AttributeValueMemberS{Value: "Test#AA"},
 					},
-				}, nil)
+				}, nil).Once()
 
-				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
 				models.Tag = model.NewTag(dmock, log)
 
 				return models
@@ -343,6 +512,32 @@ func Test_Delete(t *testing.T) {
 			wantErr: nil,
 			want:    []string{"tag1"},
 		},
+		{
+			name: "success without checking the tag name",
+			args: args{item: model.UserTag{Username: "Mock username", TagID: "1"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					_, name := in.ExpressionAttributeNames["#v1"]
+					_, value := in.ExpressionAttributeValues[":v1"]
+
+					return *in.ConditionExpression == "attribute_exists(PK) AND attribute_not_exists(#v2)" && !name && !value
+				})).Return(&dynamodb.UpdateItemOutput{
+					Attributes: map[string]types.AttributeValue{
+						"PK": &types.AttributeValueMemberS{Value: "Test#AA"},
+					},
+				}, nil).Once()
+
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: nil,
+		},
 		{
 			name: "Should fail when received error in delete call",
 			args: args{item: model.UserTag{Username: "Mock username"}},
@@ -350,13 +545,43 @@ func Test_Delete(t *testing.T) {
 				models := model.NewModel(nil, log)
 
 				dmock := mocks.NewDynamoAPI(t)
-				dmock.EXPECT().DeleteItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error"))
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error"))
 				models.Tag = model.NewTag(dmock, log)
 
 				return models
 			},
 			wantErr: errors.New("mock error"),
 		},
+		{
+			name: "Should fail with not found when tag is not followed",
+			args: args{item: model.UserTag{Username: "Mock username"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindNotFound, Code: apperror.CodeTagNotFollowed},
+		},
+		{
+			name: "Should fail with conflict when tag name does not match",
+			args: args{item: model.UserTag{Username: "Mock username", TagName: "other"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{
+					Item: map[string]types.AttributeValue{"TagName": &types.AttributeValueMemberS{Value: "tag1"}},
+				}).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindConflict, Code: apperror.CodeTagNameMismatch},
+		},
 	}
 
 	for _, tt := range tests {
@@ -366,6 +591,11 @@ func Test_Delete(t *testing.T) {
 			// call model function
 			err := a.Tag.Delete(context.TODO(), tt.args.item.Username, tt.args.item.Publication, tt.args.item.TagID, tt.args.item.TagName)
 
+			if tt.wantIs != nil {
+				assert.ErrorIs(t, err, tt.wantIs)
+				return
+			}
+
 			if tt.wantErr == nil {
 				assert.Equal(t, tt.wantErr, err)
 			}
@@ -377,6 +607,118 @@ func Test_Delete(t *testing.T) {
 	}
 }
 
+func Test_Restore(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name    string
+		mockDB  func() model.Models
+		wantErr error
+		wantIs  error
+	}{
+		{
+			name: "success",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return *in.UpdateExpression == "REMOVE #v1, #v2"
+				})).Return(&dynamodb.UpdateItemOutput{
+					Attributes: map[string]types.AttributeValue{
+						"TagName":   &types.AttributeValueMemberS{Value: "tag1"},
+						"CreatedAt": &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00Z"},
+					},
+				}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "PUB#AK" &&
+						in.ExpressionAttributeValues[":v3"].(*types.AttributeValueMemberS).Value == "tag1"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil).Twice()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name: "Should fail when undo window has passed",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{
+					Item: map[string]types.AttributeValue{"DeletedAt": &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00Z"}},
+				}).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindConflict, Code: apperror.CodeUndoWindowExpired},
+		},
+		{
+			name: "Should fail when tag is still followed",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{
+					Item: map[string]types.AttributeValue{"TagName": &types.AttributeValueMemberS{Value: "tag1"}},
+				}).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindConflict, Code: apperror.CodeTagAlreadyFollowed},
+		},
+		{
+			name: "Should fail when tag was never followed",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: apperror.ErrNotFound,
+		},
+		{
+			name: "Should fail when received error in update call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaUpdate)).Return(&dynamodb.UpdateItemOutput{}, nil)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			err := a.Tag.Restore(context.TODO(), "user1", "AK", "1")
+
+			if tt.wantIs != nil {
+				assert.ErrorIs(t, err, tt.wantIs)
+				return
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
 func Test_GetPopularTags(t *testing.T) {
 	log := testSuite()
 
@@ -384,6 +726,53 @@ func Test_GetPopularTags(t *testing.T) {
 		item model.UserTag
 	}
 
+	// queries of the TagIndex
+	rankingQuery := mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return *in.IndexName == "TagIndex" && in.FilterExpression == nil
+	})
+
+	// the follow rows of the ranked tags are checked by key
+	followedKeys := func(keys int) interface{} {
+		return mock.MatchedBy(func(in *dynamodb.BatchGetItemInput) bool {
+			return len(in.RequestItems["article-follow-tag-v5"].Keys) == keys
+		})
+	}
+	followedRows := func(items ...map[string]types.AttributeValue) *dynamodb.BatchGetItemOutput {
+		return &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"article-follow-tag-v5": items}}
+	}
+	followRow := func(tagID, tagName string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"TagID":   &types.AttributeValueMemberS{Value: tagID},
+			"TagName": &types.AttributeValueMemberS{Value: tagName},
+		}
+	}
+
+	// a user following more tags than the 100 operands of an IN expression
+	followed, entries, wantNames := []map[string]types.AttributeValue{}, []model.LeaderboardEntry{}, []string{}
+	for i := 0; i < 160; i++ {
+		id := strconv.Itoa(i)
+		if i < 150 {
+			followed = append(followed, followRow(id, "tag"+id))
+		} else {
+			wantNames = append(wantNames, "tag"+id)
+		}
+
+		entries = append(entries, model.LeaderboardEntry{TagID: id, TagName: "tag" + id, TagCount: 200 - i, Rank: i + 1})
+	}
+
+	// a leaderboard of 50 tags, the user follows 40 of them among many more
+	top, topFollowed, topNames := []model.LeaderboardEntry{}, []map[string]types.AttributeValue{}, []string{}
+	for i := 0; i < 50; i++ {
+		id := strconv.Itoa(i)
+		top = append(top, model.LeaderboardEntry{TagID: id, TagName: "tag" + id, TagCount: 100 - i, Rank: i + 1})
+
+		if i < 40 {
+			topFollowed = append(topFollowed, followRow(id, "tag"+id))
+		} else {
+			topNames = append(topNames, "tag"+id)
+		}
+	}
+
 	tests := []struct {
 		name    string
 		args    args
@@ -398,18 +787,13 @@ func Test_GetPopularTags(t *testing.T) {
 				models := model.NewModel(nil, log)
 
 				dmock := mocks.NewDynamoAPI(t)
-				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
-					map[string]types.AttributeValue{
-						"TagName": &types.AttributeValueMemberS{Value: "tag1"},
-					},
-				}}, nil).Once()
+				dmock.EXPECT().BatchGetItem(mock.Anything, followedKeys(2)).Return(followedRows(followRow("1", "tag1")), nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
 
-				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
-					map[string]types.AttributeValue{
-						"TagName": &types.AttributeValueMemberS{Value: "tag101"},
-						"SK":      &types.AttributeValueMemberS{Value: "1"},
-					},
-				}}, nil)
+				dmock.EXPECT().Query(mock.Anything, rankingQuery).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+					counterItem("1", "tag1", "5"),
+					counterItem("2", "tag101", "3"),
+				}}, nil).Once()
 				models.Tag = model.NewTag(dmock, log)
 
 				return models
@@ -418,37 +802,174 @@ func Test_GetPopularTags(t *testing.T) {
 			want: []string{"tag101"},
 		},
 		{
-			name: "Should fail when received error in query call",
-			args: args{item: model.UserTag{Username: "Mock username"}},
+			name: "success with the leaderboard",
+			args: args{item: model.UserTag{Username: "Mock username", Publication: "AK"}},
 			mockDB: func() model.Models {
 				models := model.NewModel(nil, log)
 
 				dmock := mocks.NewDynamoAPI(t)
-				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
-					map[string]types.AttributeValue{
-						"TagName": &types.AttributeValueMemberS{Value: "tag1"},
+				dmock.EXPECT().BatchGetItem(mock.Anything, followedKeys(2)).Return(followedRows(followRow("1", "tag1")), nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.GetItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "LEADERBOARD#AK"
+				})).Return(&dynamodb.GetItemOutput{Item: leaderboardItem(t, model.Leaderboard{
+					PK: "LEADERBOARD#AK",
+					SK: "LEADERBOARD",
+					Entries: []model.LeaderboardEntry{
+						{TagID: "1", TagName: "tag1", TagCount: 5, Rank: 1},
+						{TagID: "2", TagName: "tag2", TagCount: 3, Rank: 2},
 					},
-				}}, nil).Once()
-				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+					Complete: true,
+				})}, nil).Once()
 				models.Tag = model.NewTag(dmock, log)
 
 				return models
 			},
-			wantErr: errors.New("mock error"),
+			want: []string{"tag2"},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			a := tt.mockDB()
+		{
+			name: "success with the full ranking when the user follows most of the leaderboard",
+			args: args{item: model.UserTag{Username: "Mock username", Publication: "AK"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
 
-			// call model function
-			userTags, err := a.Tag.GetPopularTags(context.TODO(), tt.args.item.Username, tt.args.item.Publication)
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, followedKeys(1)).Return(followedRows(followRow("1", "tag1")), nil).Once()
+				dmock.EXPECT().BatchGetItem(mock.Anything, followedKeys(3)).Return(followedRows(followRow("1", "tag1")), nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: leaderboardItem(t, model.Leaderboard{
+					PK: "LEADERBOARD#AK",
+					SK: "LEADERBOARD",
+					Entries: []model.LeaderboardEntry{
+						{TagID: "1", TagName: "tag1", TagCount: 5, Rank: 1},
+					},
+				})}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, rankingQuery).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+					counterItem("1", "tag1", "5"),
+					counterItem("2", "tag2", "3"),
+					counterItem("3", "tag3", "1"),
+				}}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
 
-			if tt.wantErr == nil {
-				assert.Equal(t, tt.wantErr, err)
-				assert.Equal(t, tt.want, userTags)
-			}
+				return models
+			},
+			want: []string{"tag2", "tag3"},
+		},
+		{
+			name: "followed tags are excluded by id when the tag was renamed",
+			args: args{item: model.UserTag{Username: "Mock username", Publication: "AK"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, followedKeys(2)).Return(followedRows(followRow("1", "old name")), nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, rankingQuery).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+					counterItem("1", "new name", "5"),
+					counterItem("2", "old name", "3"),
+				}}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: []string{"old name"},
+		},
+		{
+			name: "unfollowed tags within the undo window are not excluded",
+			args: args{item: model.UserTag{Username: "Mock username", Publication: "AK"}},
+			mockDB: func() model.Models {
+				unfollowed := followRow("1", "tag1")
+				unfollowed["DeletedAt"] = &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00Z"}
+
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, followedKeys(2)).Return(followedRows(unfollowed), nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, rankingQuery).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+					counterItem("1", "tag1", "5"),
+					counterItem("2", "tag2", "3"),
+				}}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: []string{"tag1", "tag2"},
+		},
+		{
+			name: "only the ranked tags are read when the user follows more tags than the leaderboard holds",
+			args: args{item: model.UserTag{Username: "Mock username", Publication: "AK"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				// a single BatchGetItem of the 50 ranked tags, the follow set is never queried
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, followedKeys(50)).Return(followedRows(topFollowed...), nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: leaderboardItem(t, model.Leaderboard{
+					PK:      "LEADERBOARD#AK",
+					SK:      "LEADERBOARD",
+					Entries: top,
+				})}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: topNames,
+		},
+		{
+			name: "success when the user follows more tags than the IN operand limit",
+			args: args{item: model.UserTag{Username: "Mock username", Publication: "AK"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				// the ranking exceeds a BatchGetItem, the follow set is read instead
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return *in.IndexName != "TagIndex" && in.ExclusiveStartKey == nil
+				})).Return(&dynamodb.QueryOutput{
+					Items:            followed[:100],
+					LastEvaluatedKey: map[string]types.AttributeValue{"SK": &types.AttributeValueMemberS{Value: "99"}},
+				}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return *in.IndexName != "TagIndex" && in.ExclusiveStartKey != nil
+				})).Return(&dynamodb.QueryOutput{Items: followed[100:]}, nil).Once()
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: leaderboardItem(t, model.Leaderboard{
+					PK:      "LEADERBOARD#AK",
+					SK:      "LEADERBOARD",
+					Entries: entries,
+				})}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: wantNames,
+		},
+		{
+			name: "Should fail when received error in query call",
+			args: args{item: model.UserTag{Username: "Mock username"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().GetItem(mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			userTags, err := a.Tag.GetPopularTags(context.TODO(), tt.args.item.Username, tt.args.item.Publication)
+
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Equal(t, tt.want, userTags)
+			}
 
 			if tt.wantErr != nil {
 				assert.Equal(t, tt.wantErr, err)
@@ -456,3 +977,419 @@ func Test_GetPopularTags(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetCounters(t *testing.T) {
+	log := testSuite()
+
+	tagIDs := []string{}
+	for i := 0; i < 150; i++ {
+		tagIDs = append(tagIDs, strconv.Itoa(i))
+	}
+
+	counter := map[string]types.AttributeValue{
+		"TagID":    &types.AttributeValueMemberS{Value: "1"},
+		"TagName":  &types.AttributeValueMemberS{Value: "tag1"},
+		"TagCount": &types.AttributeValueMemberN{Value: "3"},
+	}
+
+	tests := []struct {
+		name    string
+		tagIDs  []string
+		mockDB  func() model.Models
+		want    []*model.UserTag
+		wantErr error
+	}{
+		{
+			name:   "success with a call per 100 keys",
+			tagIDs: tagIDs,
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.BatchGetItemInput) bool {
+					return len(in.RequestItems["article-follow-tag-v5"].Keys) == 100
+				})).Return(&dynamodb.BatchGetItemOutput{
+					Responses: map[string][]map[string]types.AttributeValue{"article-follow-tag-v5": {counter}},
+				}, nil).Once()
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.BatchGetItemInput) bool {
+					return len(in.RequestItems["article-follow-tag-v5"].Keys) == 50
+				})).Return(&dynamodb.BatchGetItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: []*model.UserTag{{TagID: "1", TagName: "tag1", Publication: "AK", TagCount: 3}},
+		},
+		{
+			name:   "unprocessed keys are retried",
+			tagIDs: []string{"1"},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{
+					UnprocessedKeys: map[string]types.KeysAndAttributes{"article-follow-tag-v5": {Keys: []map[string]types.AttributeValue{{
+						"PK": &types.AttributeValueMemberS{Value: "PUB#AK"},
+						"SK": &types.AttributeValueMemberS{Value: "1"},
+					}}}},
+				}, nil).Once()
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(&dynamodb.BatchGetItemOutput{
+					Responses: map[string][]map[string]types.AttributeValue{"article-follow-tag-v5": {counter}},
+				}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: []*model.UserTag{{TagID: "1", TagName: "tag1", Publication: "AK", TagCount: 3}},
+		},
+		{
+			name:   "Should fail when received error in batchGetItem call",
+			tagIDs: []string{"1"},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().BatchGetItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			got, err := a.Tag.GetCounters(context.TODO(), "AK", tt.tagIDs)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+// auditedTable returns the follow rows of user1 in AK, the audit records stored by their follow
+// and an audit record stored before AuditPublication, which has a Publication attribute
+func auditedTable(t *testing.T) []map[string]types.AttributeValue {
+	table := []map[string]types.AttributeValue{
+		{"PK": &types.AttributeValueMemberS{Value: "user1#AK"}, "SK": &types.AttributeValueMemberS{Value: "1"}, "Username": &types.AttributeValueMemberS{Value: "user1"},
+			"Publication": &types.AttributeValueMemberS{Value: "AK"}, "TagID": &types.AttributeValueMemberS{Value: "1"}, "TagName": &types.AttributeValueMemberS{Value: "tag1"}},
+		{"PK": &types.AttributeValueMemberS{Value: "user1#AK"}, "SK": &types.AttributeValueMemberS{Value: "2"}, "Username": &types.AttributeValueMemberS{Value: "user1"},
+			"Publication": &types.AttributeValueMemberS{Value: "AK"}, "TagID": &types.AttributeValueMemberS{Value: "2"}, "TagName": &types.AttributeValueMemberS{Value: "tag2"}},
+		{"PK": &types.AttributeValueMemberS{Value: "AUDIT#USER#user1"}, "SK": &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00.000000000Z#00000000"},
+			"Action": &types.AttributeValueMemberS{Value: model.AuditActionFollow}, "Username": &types.AttributeValueMemberS{Value: "user1"},
+			"Publication": &types.AttributeValueMemberS{Value: "AK"}, "TagID": &types.AttributeValueMemberS{Value: "1"}},
+	}
+
+	dmock := mocks.NewDynamoAPI(t)
+	dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Run(func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) {
+		table = append(table, params.Item)
+	}).Return(&dynamodb.PutItemOutput{}, nil).Times(4)
+
+	for _, tagID := range []string{"1", "2"} {
+		err := model.NewAudit(dmock, testSuite()).Record(context.TODO(), &model.AuditRecord{
+			Actor:       "user1",
+			Action:      model.AuditActionFollow,
+			Username:    "user1",
+			Publication: "AK",
+			TagID:       tagID,
+		})
+		assert.Nil(t, err)
+	}
+
+	return table
+}
+
+// scanTable serves a scan from the items of table, the conditions of the scans of the follow rows
+// on the PK, Publication, DeletedAt and Action attributes are applied in memory
+func scanTable(table []map[string]types.AttributeValue) func(context.Context, *dynamodb.ScanInput, ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return func(ctx context.Context, in *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+		attr := func(item map[string]types.AttributeValue, name string) (string, bool) {
+			val, ok := item[name].(*types.AttributeValueMemberS)
+			if !ok {
+				return "", false
+			}
+
+			return val.Value, true
+		}
+
+		guarded := map[string]bool{}
+		for _, name := range in.ExpressionAttributeNames {
+			guarded[name] = true
+		}
+
+		out := &dynamodb.ScanOutput{}
+		for _, item := range table {
+			if pk, ok := in.ExpressionAttributeValues[":v2"].(*types.AttributeValueMemberS); ok && guarded["PK"] {
+				if val, _ := attr(item, "PK"); val == pk.Value {
+					out.Items = append(out.Items, item)
+					continue
+				}
+			}
+
+			publication, _ := attr(item, "Publication")
+			_, deleted := item["DeletedAt"]
+			_, action := item["Action"]
+			if publication != in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberS).Value || deleted || (action && guarded["Action"]) {
+				continue
+			}
+
+			out.Items = append(out.Items, item)
+		}
+
+		return out, nil
+	}
+}
+
+func Test_RebuildCounters(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name    string
+		mockDB  func() model.Models
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+					{"TagID": &types.AttributeValueMemberS{Value: "1"}, "TagName": &types.AttributeValueMemberS{Value: "tag1"}},
+					{"TagID": &types.AttributeValueMemberS{Value: "1"}, "TagName": &types.AttributeValueMemberS{Value: "tag1"}},
+				}}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+					{"TagID": &types.AttributeValueMemberS{Value: "2"}, "TagName": &types.AttributeValueMemberS{Value: "tag2"}},
+				}}, nil).Once()
+
+				// tag 1 is counted twice, tag 2 is reset to zero
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == "2"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == "0"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name: "success - follow counts of the users are recomputed",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+					{"TagID": &types.AttributeValueMemberS{Value: "1"}, "TagName": &types.AttributeValueMemberS{Value: "tag1"}, "Username": &types.AttributeValueMemberS{Value: "user1"}},
+					{"TagID": &types.AttributeValueMemberS{Value: "2"}, "TagName": &types.AttributeValueMemberS{Value: "tag2"}, "Username": &types.AttributeValueMemberS{Value: "user1"}},
+				}}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "META#user1#AK" &&
+						in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == "2"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "PUB#AK"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Twice()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name: "success - audit records are not counted",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).RunAndReturn(scanTable(auditedTable(t))).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "META#user1#AK" &&
+						in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == "2"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "PUB#AK" &&
+						in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberN).Value == "1"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Twice()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name: "Should fail when received error in scan call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			err := a.Tag.RebuildCounters(context.TODO(), "AK")
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_ScanPublication(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name    string
+		mockDB  func() model.Models
+		want    int
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{
+					Items: []map[string]types.AttributeValue{
+						{"PK": &types.AttributeValueMemberS{Value: "user1#AK"}, "TagID": &types.AttributeValueMemberS{Value: "1"}},
+					},
+					LastEvaluatedKey: map[string]types.AttributeValue{"PK": &types.AttributeValueMemberS{Value: "user1#AK"}},
+				}, nil).Once()
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{
+					Items: []map[string]types.AttributeValue{
+						{"PK": &types.AttributeValueMemberS{Value: "PUB#AK"}, "TagCount": &types.AttributeValueMemberN{Value: "1"}},
+					},
+				}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: 2,
+		},
+		{
+			name: "success - audit records are not exported",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).RunAndReturn(scanTable(append(auditedTable(t), map[string]types.AttributeValue{
+					"PK": &types.AttributeValueMemberS{Value: "PUB#AK"}, "SK": &types.AttributeValueMemberS{Value: "1"}, "TagCount": &types.AttributeValueMemberN{Value: "1"},
+				}))).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: 3,
+		},
+		{
+			name: "Should fail when received error in scan call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Scan(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			got := 0
+			err := a.Tag.ScanPublication(context.TODO(), "AK", 0, 1, func(items []*model.UserTag) error {
+				got += len(items)
+				return nil
+			})
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_EnableTTL(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name    string
+		mockDB  func() model.Models
+		wantErr error
+	}{
+		{
+			name: "success - ttl is enabled when disabled",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().DescribeTimeToLive(mock.Anything, mock.Anything).Return(&dynamodb.DescribeTimeToLiveOutput{
+					TimeToLiveDescription: &types.TimeToLiveDescription{TimeToLiveStatus: types.TimeToLiveStatusDisabled},
+				}, nil).Once()
+				dmock.EXPECT().UpdateTimeToLive(mock.Anything, mock.Anything).Return(&dynamodb.UpdateTimeToLiveOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name: "success - ttl is already enabled",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().DescribeTimeToLive(mock.Anything, mock.Anything).Return(&dynamodb.DescribeTimeToLiveOutput{
+					TimeToLiveDescription: &types.TimeToLiveDescription{TimeToLiveStatus: types.TimeToLiveStatusEnabled},
+				}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name: "Should fail when received error in updateTimeToLive call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().DescribeTimeToLive(mock.Anything, mock.Anything).Return(&dynamodb.DescribeTimeToLiveOutput{}, nil).Once()
+				dmock.EXPECT().UpdateTimeToLive(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			err := a.Tag.EnableTTL(context.TODO())
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
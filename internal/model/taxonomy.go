@@ -0,0 +1,210 @@
+package model
+
+import (
+	"article-tag/internal/apperror"
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// TaxonomyTag is stored as TAXONOMY#<publication>, one item per tag of the publication.
+// It has no Publication attribute so the scans of the follow rows skip it.
+type TaxonomyTag struct {
+	PK      string
+	SK      string
+	TagID   string
+	TagName string
+	// ParentID is empty for the top level tags
+	ParentID string `dynamodbav:",omitempty"`
+}
+
+// PutTaxonomyTag adds the tag to the taxonomy of the publication or moves it under another parent,
+// an empty parent makes it a top level tag. The parent must exist and must not be a descendant of the tag.
+func (t *tag) PutTaxonomyTag(ctx context.Context, publication, tagID, tagName, parentID string) error {
+	if parentID != "" {
+		taxonomy, err := t.GetTaxonomy(ctx, publication)
+		if err != nil {
+			return err
+		}
+
+		parents := make(map[string]string, len(taxonomy))
+		for _, val := range taxonomy {
+			parents[val.TagID] = val.ParentID
+		}
+
+		if _, ok := parents[parentID]; !ok {
+			return apperror.New(apperror.KindNotFound, apperror.CodeTagNotFound, fmt.Sprintf("parent tag %s does not exist", parentID))
+		}
+
+		// walk up from the parent, the taxonomy has no cycle so the walk ends at a top level tag
+		for id := parentID; id != ""; id = parents[id] {
+			if id == tagID {
+				return apperror.New(apperror.KindValidationFailed, apperror.CodeTagCycle, fmt.Sprintf("tag %s can not be a descendant of itself", tagID))
+			}
+		}
+	}
+
+	item := TaxonomyTag{
+		PK:       taxonomyPK(publication),
+		SK:       tagID,
+		TagID:    tagID,
+		TagName:  tagName,
+		ParentID: parentID,
+	}
+
+	// convert struct to map
+	inputMap, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		t.logger.Error("marshal failed", zap.Error(err))
+		return err
+	}
+
+	_, err = t.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item:      inputMap,
+	})
+	if err != nil {
+		t.logger.Error("error storing taxonomy tag", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetTaxonomy returns every tag of the taxonomy of the publication
+func (t *tag) GetTaxonomy(ctx context.Context, publication string) ([]*TaxonomyTag, error) {
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		KeyConditionExpression: aws.String("#v1 = :v1"),
+		ExpressionAttributeNames: map[string]string{
+			"#v1": "PK",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":v1": &types.AttributeValueMemberS{Value: taxonomyPK(publication)},
+		},
+	}
+
+	taxonomy := []*TaxonomyTag{}
+
+	for {
+		res, err := t.db.Query(ctx, &queryInput)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, val := range res.Items {
+			var m TaxonomyTag
+
+			err := attributevalue.UnmarshalMap(val, &m)
+			if err != nil {
+				t.logger.Error("unmarshal failed while fetching taxonomy", zap.Error(err))
+				return nil, err
+			}
+
+			taxonomy = append(taxonomy, &m)
+		}
+
+		if res.LastEvaluatedKey == nil {
+			return taxonomy, nil
+		}
+
+		queryInput.ExclusiveStartKey = res.LastEvaluatedKey
+	}
+}
+
+// GetSubtree returns the tag followed by its descendants, level by level
+func (t *tag) GetSubtree(ctx context.Context, publication, tagID string) ([]*TaxonomyTag, error) {
+	taxonomy, err := t.GetTaxonomy(ctx, publication)
+	if err != nil {
+		return nil, err
+	}
+
+	subtree := subtrees(taxonomy, []string{tagID})
+	if len(subtree) == 0 {
+		return nil, apperror.New(apperror.KindNotFound, apperror.CodeTagNotFound, fmt.Sprintf("tag %s does not exist", tagID))
+	}
+
+	return subtree, nil
+}
+
+// ImplicitTags returns the descendants of the followed tags which are followed including their descendants,
+// the followed tags themselves are excluded
+func (t *tag) ImplicitTags(ctx context.Context, publication string, followed []*UserTag) ([]*UserTag, error) {
+	roots := []string{}
+	for _, val := range followed {
+		if val.IncludeDescendants {
+			roots = append(roots, val.TagID)
+		}
+	}
+
+	implicit := []*UserTag{}
+	if len(roots) == 0 {
+		return implicit, nil
+	}
+
+	taxonomy, err := t.GetTaxonomy(ctx, publication)
+	if err != nil {
+		return nil, err
+	}
+
+	explicit := make(map[string]struct{}, len(followed))
+	for _, val := range followed {
+		explicit[val.TagID] = struct{}{}
+	}
+
+	for _, val := range subtrees(taxonomy, roots) {
+		if _, ok := explicit[val.TagID]; ok {
+			continue
+		}
+
+		implicit = append(implicit, &UserTag{TagID: val.TagID, TagName: val.TagName})
+	}
+
+	return implicit, nil
+}
+
+// subtrees returns the roots found in the taxonomy followed by their descendants, level by level,
+// a tag under several roots is returned once
+func subtrees(taxonomy []*TaxonomyTag, roots []string) []*TaxonomyTag {
+	byID := make(map[string]*TaxonomyTag, len(taxonomy))
+	children := map[string][]*TaxonomyTag{}
+	for _, val := range taxonomy {
+		byID[val.TagID] = val
+		children[val.ParentID] = append(children[val.ParentID], val)
+	}
+
+	res := []*TaxonomyTag{}
+	seen := map[string]struct{}{}
+
+	queue := []*TaxonomyTag{}
+	for _, id := range roots {
+		if val, ok := byID[id]; ok {
+			queue = append(queue, val)
+		}
+	}
+
+	for len(queue) > 0 {
+		val := queue[0]
+		queue = queue[1:]
+
+		if _, ok := seen[val.TagID]; ok {
+			continue
+		}
+
+		seen[val.TagID] = struct{}{}
+		res = append(res, val)
+		queue = append(queue, children[val.TagID]...)
+	}
+
+	return res
+}
+
+// taxonomyPK
+func taxonomyPK(publication string) string {
+	return fmt.Sprintf("TAXONOMY#%s", publication)
+}
@@ -0,0 +1,320 @@
+package model_test
+
+import (
+	"article-tag/internal/apperror"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// taxonomy of the tests, sports > cricket > ipl and sports > football
+var taxonomy = &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+	{"TagID": &types.AttributeValueMemberS{Value: "1"}, "TagName": &types.AttributeValueMemberS{Value: "sports"}},
+	{"TagID": &types.AttributeValueMemberS{Value: "2"}, "TagName": &types.AttributeValueMemberS{Value: "cricket"}, "ParentID": &types.AttributeValueMemberS{Value: "1"}},
+	{"TagID": &types.AttributeValueMemberS{Value: "3"}, "TagName": &types.AttributeValueMemberS{Value: "ipl"}, "ParentID": &types.AttributeValueMemberS{Value: "2"}},
+	{"TagID": &types.AttributeValueMemberS{Value: "4"}, "TagName": &types.AttributeValueMemberS{Value: "football"}, "ParentID": &types.AttributeValueMemberS{Value: "1"}},
+}}
+
+func Test_PutTaxonomyTag(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name     string
+		tagID    string
+		parentID string
+		mockDB   func() model.Models
+		wantErr  error
+		wantIs   error
+	}{
+		{
+			name:  "success - top level tag",
+			tagID: "5",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					_, ok := in.Item["ParentID"]
+					return in.Item["PK"].(*types.AttributeValueMemberS).Value == "TAXONOMY#AK" && !ok
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name:     "success - tag is moved under another parent",
+			tagID:    "3",
+			parentID: "4",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(taxonomy, nil).Once()
+				dmock.EXPECT().PutItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					return in.Item["ParentID"].(*types.AttributeValueMemberS).Value == "4"
+				})).Return(&dynamodb.PutItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+		},
+		{
+			name:     "Should fail with not found when parent does not exist",
+			tagID:    "5",
+			parentID: "9",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(taxonomy, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindNotFound, Code: apperror.CodeTagNotFound},
+		},
+		{
+			name:     "Should fail with validation failed when parent is a descendant of the tag",
+			tagID:    "1",
+			parentID: "3",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(taxonomy, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindValidationFailed, Code: apperror.CodeTagCycle},
+		},
+		{
+			name:     "Should fail with validation failed when tag is its own parent",
+			tagID:    "2",
+			parentID: "2",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(taxonomy, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindValidationFailed, Code: apperror.CodeTagCycle},
+		},
+		{
+			name:  "Should fail when received error in putItem call",
+			tagID: "5",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().PutItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			err := a.Tag.PutTaxonomyTag(context.TODO(), "AK", tt.tagID, "tag", tt.parentID)
+
+			if tt.wantIs != nil {
+				assert.ErrorIs(t, err, tt.wantIs)
+				return
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_GetSubtree(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name    string
+		tagID   string
+		mockDB  func() model.Models
+		want    []string
+		wantErr error
+		wantIs  error
+	}{
+		{
+			name:  "success",
+			tagID: "1",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberS).Value == "TAXONOMY#AK"
+				})).Return(taxonomy, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: []string{"1", "2", "4", "3"},
+		},
+		{
+			name:  "success - tag without descendants",
+			tagID: "3",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(taxonomy, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: []string{"3"},
+		},
+		{
+			name:  "Should fail with not found when tag is not in the taxonomy",
+			tagID: "9",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(taxonomy, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantIs: &apperror.Error{Kind: apperror.KindNotFound, Code: apperror.CodeTagNotFound},
+		},
+		{
+			name:  "Should fail when received error in query call",
+			tagID: "1",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			got, err := a.Tag.GetSubtree(context.TODO(), "AK", tt.tagID)
+
+			if tt.wantIs != nil {
+				assert.ErrorIs(t, err, tt.wantIs)
+				return
+			}
+
+			assert.Equal(t, tt.wantErr, err)
+
+			ids := []string{}
+			for _, val := range got {
+				ids = append(ids, val.TagID)
+			}
+
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.want, ids)
+			}
+		})
+	}
+}
+
+func Test_ImplicitTags(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name     string
+		followed []*model.UserTag
+		mockDB   func() model.Models
+		want     []*model.UserTag
+		wantErr  error
+	}{
+		{
+			name:     "success - descendants of the tags followed with their descendants",
+			followed: []*model.UserTag{{TagID: "2", TagName: "cricket", IncludeDescendants: true}, {TagID: "4", TagName: "football"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(taxonomy, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: []*model.UserTag{{TagID: "3", TagName: "ipl"}},
+		},
+		{
+			name:     "success - explicitly followed descendants are excluded",
+			followed: []*model.UserTag{{TagID: "1", TagName: "sports", IncludeDescendants: true}, {TagID: "2", TagName: "cricket", IncludeDescendants: true}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(taxonomy, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: []*model.UserTag{{TagID: "4", TagName: "football"}, {TagID: "3", TagName: "ipl"}},
+		},
+		{
+			name:     "success - taxonomy is not read without tags followed with their descendants",
+			followed: []*model.UserTag{{TagID: "1", TagName: "sports"}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+				models.Tag = model.NewTag(mocks.NewDynamoAPI(t), log)
+
+				return models
+			},
+			want: []*model.UserTag{},
+		},
+		{
+			name:     "Should fail when received error in query call",
+			followed: []*model.UserTag{{TagID: "1", TagName: "sports", IncludeDescendants: true}},
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			got, err := a.Tag.ImplicitTags(context.TODO(), "AK", tt.followed)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"article-tag/internal/constant"
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// GetAll returns every follow row stored for the username across all the publications
+func (t *tag) GetAll(ctx context.Context, username string) ([]*UserTag, error) {
+	userTags := []*UserTag{}
+
+	// follows are partitioned by username#publication
+	for _, publication := range constant.AllowdedPublications {
+		queryInput := dynamodb.QueryInput{
+			TableName:              aws.String(tableName),
+			KeyConditionExpression: aws.String("#v1 = :v1"),
+			ExpressionAttributeNames: map[string]string{
+				"#v1": "PK",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":v1": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%s", username, publication)},
+			},
+		}
+
+		for {
+			res, err := t.db.Query(ctx, &queryInput)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, val := range res.Items {
+				var m UserTag
+
+				err := attributevalue.UnmarshalMap(val, &m)
+				if err != nil {
+					t.logger.Error("unmarshal failed while fetching all user tags", zap.Error(err))
+					return nil, err
+				}
+
+				userTags = append(userTags, &m)
+			}
+
+			if res.LastEvaluatedKey == nil {
+				break
+			}
+
+			queryInput.ExclusiveStartKey = res.LastEvaluatedKey
+		}
+	}
+
+	return userTags, nil
+}
+
+// Erase deletes every follow row of the username, including the unfollowed ones, and empties its meta items,
+// decrements the popularity counters of the followed tags, returns the number of rows deleted
+func (t *tag) Erase(ctx context.Context, username string) (int, error) {
+	userTags, err := t.GetAll(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+
+	erased := 0
+	for _, val := range userTags {
+		input := dynamodb.DeleteItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: val.PK},
+				"SK": &types.AttributeValueMemberS{Value: val.SK},
+			},
+			ReturnValues: types.ReturnValueAllOld,
+		}
+
+		delItemResp, err := t.db.DeleteItem(ctx, &input)
+		if err != nil {
+			return erased, err
+		}
+
+		// row was already removed by a concurrent delete
+		if delItemResp.Attributes == nil {
+			continue
+		}
+
+		erased++
+
+		// counter of an unfollowed tag is already decremented
+		if _, ok := delItemResp.Attributes["DeletedAt"]; ok {
+			continue
+		}
+
+		input2 := dynamodb.UpdateItemInput{
+			TableName: aws.String(tableName),
+			Key: map[string]types.AttributeValue{
+				"PK": &types.AttributeValueMemberS{Value: fmt.Sprintf("PUB#%s", val.Publication)},
+				"SK": &types.AttributeValueMemberS{Value: val.TagID},
+			},
+			UpdateExpression: aws.String("SET TagCount = TagCount - :decr"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":decr": &types.AttributeValueMemberN{Value: "1"},
+			},
+		}
+
+		_, err = t.db.UpdateItem(ctx, &input2)
+		if err != nil {
+			t.logger.Error("error updating tag counter while erasing user", zap.Error(err))
+			return erased, err
+		}
+	}
+
+	return erased, t.eraseMeta(ctx, username)
+}
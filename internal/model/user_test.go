@@ -0,0 +1,182 @@
+package model_test
+
+import (
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_GetAll(t *testing.T) {
+	log := testSuite()
+
+	tests := []struct {
+		name    string
+		mockDB  func() model.Models
+		want    int
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return in.ExpressionAttributeValues[":v1"].(*types.AttributeValueMemberS).Value == "user1#AK"
+				})).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{
+					{"TagID": &types.AttributeValueMemberS{Value: "1"}, "Publication": &types.AttributeValueMemberS{Value: "AK"}},
+				}}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Times(3)
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: 1,
+		},
+		{
+			name: "Should fail when received error in query call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			got, err := a.Tag.GetAll(context.TODO(), "user1")
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, len(got))
+		})
+	}
+}
+
+func Test_Erase(t *testing.T) {
+	log := testSuite()
+
+	follow := map[string]types.AttributeValue{
+		"PK":          &types.AttributeValueMemberS{Value: "user1#AK"},
+		"SK":          &types.AttributeValueMemberS{Value: "1"},
+		"TagID":       &types.AttributeValueMemberS{Value: "1"},
+		"Publication": &types.AttributeValueMemberS{Value: "AK"},
+	}
+
+	tests := []struct {
+		name    string
+		mockDB  func() model.Models
+		want    int
+		wantErr error
+	}{
+		{
+			name: "success",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{follow},
+				}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Times(3)
+				dmock.EXPECT().DeleteItem(mock.Anything, mock.Anything).Return(&dynamodb.DeleteItemOutput{Attributes: follow}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaTombstone)).Return(&dynamodb.UpdateItemOutput{}, nil).Times(4)
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return in.Key["PK"].(*types.AttributeValueMemberS).Value == "PUB#AK"
+				})).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: 1,
+		},
+		{
+			name: "counter is not decremented when row is already deleted",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{follow},
+				}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Times(3)
+				dmock.EXPECT().DeleteItem(mock.Anything, mock.Anything).Return(&dynamodb.DeleteItemOutput{}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaTombstone)).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+				// publications the user never followed a tag of have no meta item
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaTombstone)).Return(nil, &types.ConditionalCheckFailedException{}).Times(3)
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: 0,
+		},
+		{
+			name: "counter is not decremented for an unfollowed tag",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				deleted := map[string]types.AttributeValue{
+					"PK":        &types.AttributeValueMemberS{Value: "user1#AK"},
+					"SK":        &types.AttributeValueMemberS{Value: "1"},
+					"DeletedAt": &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00Z"},
+				}
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{deleted},
+				}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Times(3)
+				dmock.EXPECT().DeleteItem(mock.Anything, mock.Anything).Return(&dynamodb.DeleteItemOutput{Attributes: deleted}, nil).Once()
+				dmock.EXPECT().UpdateItem(mock.Anything, mock.MatchedBy(metaTombstone)).Return(&dynamodb.UpdateItemOutput{}, nil).Times(4)
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			want: 1,
+		},
+		{
+			name: "Should fail when received error in delete call",
+			mockDB: func() model.Models {
+				models := model.NewModel(nil, log)
+
+				dmock := mocks.NewDynamoAPI(t)
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{follow},
+				}, nil).Once()
+				dmock.EXPECT().Query(mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Times(3)
+				dmock.EXPECT().DeleteItem(mock.Anything, mock.Anything).Return(nil, errors.New("mock error")).Once()
+				models.Tag = model.NewTag(dmock, log)
+
+				return models
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := tt.mockDB()
+
+			// call model function
+			got, err := a.Tag.Erase(context.TODO(), "user1")
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,446 @@
+package openapi
+
+import (
+	"article-tag/internal/response"
+	"article-tag/internal/types"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// adminSecurity is the requirement of the admin routes
+var adminSecurity = []map[string][]string{{"AdminToken": {}}}
+
+// errorResponses are the components of the error responses by status
+var errorResponses = map[int]string{
+	http.StatusBadRequest:          "BadRequest",
+	http.StatusUnauthorized:        "Unauthorized",
+	http.StatusForbidden:           "Forbidden",
+	http.StatusNotFound:            "NotFound",
+	http.StatusConflict:            "Conflict",
+	http.StatusPreconditionFailed:  "PreconditionFailed",
+	http.StatusUnprocessableEntity: "UnprocessableEntity",
+	http.StatusTooManyRequests:     "TooManyRequests",
+	http.StatusInternalServerError: "InternalServerError",
+	http.StatusServiceUnavailable:  "ServiceUnavailable",
+}
+
+// Spec describes every route of routes.InitRouter, except the documentation itself
+func Spec() *Document {
+	g := &generator{schemas: map[string]*Schema{}}
+	d := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "article-tag",
+			Description: "Follow tags of a publication and get the popular tags to follow.",
+			Version:     "1.0.0",
+		},
+		Paths: map[string]*PathItem{},
+		Components: Components{
+			Schemas:   g.schemas,
+			Responses: g.errorResponses(),
+			SecuritySchemes: map[string]*SecurityScheme{
+				"AdminToken": {Type: "apiKey", In: "header", Name: "X-Admin-Token"},
+			},
+		},
+	}
+
+	// tags, the unversioned routes are the deprecated v1 routes
+	for path, item := range g.tagsV1() {
+		d.Paths["/v1"+path] = item
+		d.Paths[path] = item.deprecated("Unversioned")
+	}
+
+	for path, item := range g.tagsV2() {
+		d.Paths["/v2"+path] = item
+	}
+
+	// users
+	d.add(http.MethodGet, "/users/{username}/data", &Operation{
+		OperationID: "getUserData",
+		Summary:     "Export everything stored for a user",
+		Tags:        []string{"users"},
+		Parameters:  g.parameters(types.UserDataRequest{}, "path"),
+		Responses:   g.responses(http.StatusOK, types.UserDataResponse{}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden),
+		Security:    adminSecurity,
+	})
+	d.add(http.MethodDelete, "/users/{username}", &Operation{
+		OperationID: "eraseUser",
+		Summary:     "Erase everything stored for a user",
+		Tags:        []string{"users"},
+		Parameters:  g.parameters(types.UserDataRequest{}, "path"),
+		Responses:   g.responses(http.StatusOK, types.EraseUserResponse{}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden),
+		Security:    adminSecurity,
+	})
+
+	// admin
+	d.add(http.MethodGet, "/admin/publications/{publication}/export", &Operation{
+		OperationID: "exportPublication",
+		Summary:     "Export the follows and popular tag counters of a publication",
+		Tags:        []string{"admin"},
+		Parameters: append(g.parameters(types.GetPopularTagRequest{}, "path", "username"), &Parameter{
+			Name:   "format",
+			In:     "query",
+			Schema: &Schema{Type: "string", Enum: []string{"jsonl", "csv"}},
+		}),
+		Responses: g.exportResponses(),
+		Security:  adminSecurity,
+	})
+	d.add(http.MethodGet, "/admin/audit", &Operation{
+		OperationID: "getAuditLog",
+		Summary:     "Query the audit log of a user, a tag or a publication",
+		Tags:        []string{"admin"},
+		Parameters:  g.parameters(types.AuditQueryRequest{}, "query"),
+		Responses:   g.responses(http.StatusOK, types.AuditQueryResponse{}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden),
+		Security:    adminSecurity,
+	})
+
+	d.add(http.MethodGet, "/admin/cache", &Operation{
+		OperationID: "getCacheStats",
+		Summary:     "Hits and misses of the cached popular rankings and followed tags",
+		Tags:        []string{"admin"},
+		Responses:   g.responses(http.StatusOK, types.CacheStatsResponse{}, http.StatusUnauthorized, http.StatusForbidden),
+		Security:    adminSecurity,
+	})
+	d.add(http.MethodPut, "/admin/publications/{publication}/tags/{tagID}", &Operation{
+		OperationID: "putTaxonomyTag",
+		Summary:     "Add a tag to the taxonomy of the publication or move it under another parent",
+		Tags:        []string{"admin"},
+		Parameters:  g.tagPathParameters(types.TaxonomyTagRequest{}, "tag_name", "parent_id"),
+		RequestBody: g.body(types.TaxonomyTagRequest{}, "publication", "tag_id"),
+		Responses: g.responses(http.StatusOK, types.TaxonomyTag{}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden,
+			http.StatusNotFound, http.StatusUnprocessableEntity),
+		Security: adminSecurity,
+	})
+	d.add(http.MethodPut, "/admin/publications/{publication}/tags/{tagID}/aliases", &Operation{
+		OperationID: "putAliases",
+		Summary:     "Map alias tag ids and names to the canonical tag, a follow of an alias follows the canonical tag",
+		Tags:        []string{"admin"},
+		Parameters:  g.tagPathParameters(types.AliasRequest{}, "tag_name", "alias_ids", "alias_names"),
+		RequestBody: g.body(types.AliasRequest{}, "publication", "tag_id"),
+		Responses: g.responses(http.StatusOK, types.AliasResponse{}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden,
+			http.StatusUnprocessableEntity),
+		Security: adminSecurity,
+	})
+	d.add(http.MethodPost, "/admin/publications/{publication}/tags/{tagID}/merge", &Operation{
+		OperationID: "mergeTag",
+		Summary:     "Move the follows and the counter of an alias tag to its canonical tag",
+		Tags:        []string{"admin"},
+		Parameters:  g.tagPathParameters(types.MergeRequest{}),
+		Responses: g.responses(http.StatusOK, types.MergeResponse{}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden,
+			http.StatusNotFound),
+		Security: adminSecurity,
+	})
+	d.add(http.MethodGet, "/admin/users/{username}/publications/{publication}/limit", &Operation{
+		OperationID: "getFollowLimit",
+		Summary:     "Get the followed tags count and the follow limit of a user",
+		Tags:        []string{"admin"},
+		Parameters:  g.parameters(types.FollowLimitRequest{}, "path", "limit"),
+		Responses:   g.responses(http.StatusOK, types.FollowLimitResponse{}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden),
+		Security:    adminSecurity,
+	})
+	d.add(http.MethodPut, "/admin/users/{username}/publications/{publication}/limit", &Operation{
+		OperationID: "setFollowLimit",
+		Summary:     "Override the follow limit of the publication for a user, a limit of 0 removes the override",
+		Tags:        []string{"admin"},
+		Parameters:  g.parameters(types.FollowLimitRequest{}, "path", "limit"),
+		RequestBody: g.body(types.FollowLimitRequest{}, "username", "publication"),
+		Responses:   g.responses(http.StatusOK, types.FollowLimitResponse{}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden),
+		Security:    adminSecurity,
+	})
+
+	// graphql
+	d.add(http.MethodPost, "/graphql", &Operation{
+		OperationID: "graphql",
+		Summary:     "Query publications, users and tags, follow and unfollow with GraphQL",
+		Tags:        []string{"graphql"},
+		RequestBody: g.body(types.GraphQLRequest{}),
+		Responses:   g.graphqlResponses(),
+	})
+
+	return d
+}
+
+// tagsV1 returns the tag operations of v1 by path
+func (g *generator) tagsV1() map[string]*PathItem {
+	d := &Document{Paths: map[string]*PathItem{}}
+
+	d.add(http.MethodPost, "/tags/{publication}", versioned(&Operation{
+		OperationID: "followTags",
+		Summary:     "Follow tags",
+		Tags:        []string{"tags"},
+		Parameters:  g.parameters(types.StoreTagRequest{}, "path", "username", "tags"),
+		RequestBody: g.body(types.StoreTagRequest{}, "publication"),
+		Responses:   g.responses(http.StatusCreated, nil, http.StatusBadRequest, http.StatusConflict),
+	}))
+	d.add(http.MethodPut, "/tags/{publication}", versioned(&Operation{
+		OperationID: "replaceTags",
+		Summary:     "Replace the followed tags, returns the followed, unfollowed and updated tags",
+		Tags:        []string{"tags"},
+		Parameters:  g.parameters(types.ReplaceTagRequest{}, "path", "username", "tags"),
+		RequestBody: g.body(types.ReplaceTagRequest{}, "publication"),
+		Responses:   g.responses(http.StatusOK, types.ReplaceTagResponse{}, http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity),
+	}))
+	d.add(http.MethodGet, "/tags/{publication}", conditional(&Operation{
+		OperationID: "getTags",
+		Summary:     "Get the followed tags of a user",
+		Tags:        []string{"tags"},
+		Parameters: append(g.parameters(types.GetTagRequest{}, "path", "username", "order"),
+			g.parameters(types.GetTagRequest{}, "query", "publication")...),
+		Responses: g.responses(http.StatusOK, types.GetTagResponse{}, http.StatusBadRequest),
+	}))
+
+	d.add(http.MethodDelete, "/tags/{publication}", versioned(&Operation{
+		OperationID: "unfollowTags",
+		Summary:     "Unfollow tags, they can be followed again with undo",
+		Tags:        []string{"tags"},
+		Parameters:  g.parameters(types.DeleteTagRequest{}, "path", "username", "tags"),
+		RequestBody: g.body(types.DeleteTagRequest{}, "publication"),
+		Responses:   g.responses(http.StatusOK, nil, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	}))
+	d.add(http.MethodPost, "/tags/{publication}/undo", versioned(&Operation{
+		OperationID: "undoUnfollowTags",
+		Summary:     "Follow again tags unfollowed within the undo window",
+		Tags:        []string{"tags"},
+		Parameters:  g.parameters(types.UndoTagRequest{}, "path", "username", "tags"),
+		RequestBody: g.body(types.UndoTagRequest{}, "publication"),
+		Responses:   g.responses(http.StatusOK, nil, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	}))
+	d.add(http.MethodGet, "/tags/{publication}/popular", conditional(&Operation{
+		OperationID: "getPopularTags",
+		Summary:     "Get the popular tags not followed by the user",
+		Tags:        []string{"tags"},
+		Parameters: append(g.parameters(types.GetPopularTagRequest{}, "path", "username"),
+			g.parameters(types.GetPopularTagRequest{}, "query", "publication")...),
+		Responses: g.responses(http.StatusOK, []string{}, http.StatusBadRequest),
+	}))
+
+	return d.Paths
+}
+
+// tagsV2 returns the tag operations of v2 by path
+func (g *generator) tagsV2() map[string]*PathItem {
+	d := &Document{Paths: map[string]*PathItem{}}
+
+	d.add(http.MethodGet, "/publications/{publication}/users/{username}/tags", conditional(&Operation{
+		OperationID: "listUserTags",
+		Summary:     "Get the followed tags of a user",
+		Tags:        []string{"tags"},
+		Parameters: append(g.parameters(types.GetTagRequest{}, "path", "order"),
+			g.parameters(types.GetTagRequest{}, "query", "publication", "username")...),
+		Responses: g.responses(http.StatusOK, types.GetTagResponse{}, http.StatusBadRequest),
+	}))
+
+	d.add(http.MethodPut, "/publications/{publication}/users/{username}/tags/{tagID}", versioned(&Operation{
+		OperationID: "followUserTag",
+		Summary:     "Follow a tag, following it again has no effect",
+		Tags:        []string{"tags"},
+		Parameters:  g.userTagParameters(),
+		RequestBody: g.body(types.PutTagRequest{}, "publication", "username", "tag_id"),
+		Responses:   g.responses(http.StatusOK, types.Tag{}, http.StatusBadRequest, http.StatusConflict),
+	}))
+	d.add(http.MethodDelete, "/publications/{publication}/users/{username}/tags/{tagID}", versioned(&Operation{
+		OperationID: "unfollowUserTag",
+		Summary:     "Unfollow a tag, it can be followed again with restore",
+		Tags:        []string{"tags"},
+		Parameters:  g.userTagParameters(),
+		Responses:   g.responses(http.StatusOK, nil, http.StatusBadRequest, http.StatusNotFound),
+	}))
+	d.add(http.MethodPost, "/publications/{publication}/users/{username}/tags/{tagID}/restore", versioned(&Operation{
+		OperationID: "restoreUserTag",
+		Summary:     "Follow again a tag unfollowed within the undo window",
+		Tags:        []string{"tags"},
+		Parameters:  g.userTagParameters(),
+		Responses:   g.responses(http.StatusOK, nil, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict),
+	}))
+	d.add(http.MethodGet, "/publications/{publication}/tags/popular", conditional(&Operation{
+		OperationID: "listPopularTags",
+		Summary:     "Get the popular tags not followed by the user",
+		Tags:        []string{"tags"},
+		Parameters: append(g.parameters(types.GetPopularTagRequest{}, "path", "username"),
+			g.parameters(types.GetPopularTagRequest{}, "query", "publication")...),
+		Responses: g.responses(http.StatusOK, []string{}, http.StatusBadRequest),
+	}))
+
+	d.add(http.MethodGet, "/publications/{publication}/tags/leaderboard", conditional(&Operation{
+		OperationID: "getLeaderboard",
+		Summary:     "Get the top tags of the publication with their rank changes since the previous refresh",
+		Tags:        []string{"tags"},
+		Parameters:  g.parameters(types.GetPopularTagRequest{}, "path", "username"),
+		Responses:   g.responses(http.StatusOK, types.LeaderboardResponse{}, http.StatusBadRequest),
+	}))
+	d.add(http.MethodGet, "/publications/{publication}/tags/{tagID}/subtree", &Operation{
+		OperationID: "getSubtree",
+		Summary:     "Get a tag and its descendants in the taxonomy of the publication",
+		Tags:        []string{"tags"},
+		Parameters:  g.tagPathParameters(types.SubtreeRequest{}),
+		Responses:   g.responses(http.StatusOK, types.SubtreeResponse{}, http.StatusBadRequest, http.StatusNotFound),
+	})
+
+	return d.Paths
+}
+
+// conditional documents the etag of a read operation, sent back in If-None-Match to get 304 when unchanged
+func conditional(op *Operation) *Operation {
+	op.Parameters = append(op.Parameters, &Parameter{
+		Name:        "If-None-Match",
+		In:          "header",
+		Description: "etag of a previous response",
+		Schema:      &Schema{Type: "string"},
+	})
+
+	headers := map[string]*Header{
+		"ETag":          {Description: "strong etag of the response body", Schema: &Schema{Type: "string"}},
+		"Cache-Control": {Description: "configured per route", Schema: &Schema{Type: "string"}},
+	}
+
+	op.Responses[strconv.Itoa(http.StatusOK)].Headers = headers
+	op.Responses[strconv.Itoa(http.StatusNotModified)] = &Response{
+		Description: http.StatusText(http.StatusNotModified),
+		Headers:     headers,
+	}
+
+	return op
+}
+
+// versioned documents the If-Match of the writes, the version of the follow set returned with the followed tags
+func versioned(op *Operation) *Operation {
+	op.Parameters = append(op.Parameters, &Parameter{
+		Name:        "If-Match",
+		In:          "header",
+		Description: "version of the follow set the write is based on, not checked when missing",
+		Schema:      &Schema{Type: "string"},
+	})
+
+	op.Responses[strconv.Itoa(http.StatusPreconditionFailed)] = &Response{Ref: "#/components/responses/" + errorResponses[http.StatusPreconditionFailed]}
+
+	return op
+}
+
+// userTagParameters of the v2 paths
+func (g *generator) userTagParameters() []*Parameter {
+	return g.tagPathParameters(types.UserTagRequest{})
+}
+
+// tagPathParameters returns the struct fields as path parameters, the tag id is named tagID in the path
+func (g *generator) tagPathParameters(v interface{}, omit ...string) []*Parameter {
+	params := g.parameters(v, "path", omit...)
+	for _, val := range params {
+		if val.Name == "tag_id" {
+			val.Name = "tagID"
+		}
+	}
+
+	return params
+}
+
+// deprecated returns a copy of the operations marked as deprecated,
+// the suffix keeps the operation ids unique
+func (p *PathItem) deprecated(suffix string) *PathItem {
+	item := PathItem{}
+	for method, op := range *p {
+		dep := *op
+		dep.OperationID += suffix
+		dep.Deprecated = true
+		item[method] = &dep
+	}
+
+	return &item
+}
+
+// add the operation, the method is lower cased as required by the specification
+func (d *Document) add(method, path string, op *Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+
+	(*item)[strings.ToLower(method)] = op
+}
+
+// body returns the json request body of the struct without the fields read from the path
+func (g *generator) body(v interface{}, omit ...string) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content: map[string]*MediaType{
+			"application/json": {Schema: g.object(reflectType(v), omit)},
+		},
+	}
+}
+
+// responses returns the success response wrapping data in the body, followed by the errors.
+// Store errors (429, 500, 503) can be returned by every operation.
+func (g *generator) responses(status int, data interface{}, errors ...int) map[string]*Response {
+	body := g.ref(response.Body{})
+	if data != nil {
+		body = &Schema{AllOf: []*Schema{body, {
+			Type:       "object",
+			Properties: map[string]*Schema{"data": g.ref(data)},
+		}}}
+	}
+
+	res := map[string]*Response{
+		strconv.Itoa(status): {
+			Description: http.StatusText(status),
+			Content:     map[string]*MediaType{"application/json": {Schema: body}},
+		},
+	}
+
+	errors = append(errors, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable)
+	for _, val := range errors {
+		res[strconv.Itoa(val)] = &Response{Ref: "#/components/responses/" + errorResponses[val]}
+	}
+
+	return res
+}
+
+// exportResponses, the export is streamed as json lines or csv
+func (g *generator) exportResponses() map[string]*Response {
+	res := g.responses(http.StatusOK, nil, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden)
+	res[strconv.Itoa(http.StatusOK)] = &Response{
+		Description: "follows and counters of the publication, one record per line",
+		Content: map[string]*MediaType{
+			"application/x-ndjson": {Schema: &Schema{Type: "string"}},
+			"text/csv":             {Schema: &Schema{Type: "string"}},
+		},
+	}
+
+	return res
+}
+
+// graphqlResponses, errors of the query are returned with 200 in the errors of the body
+func (g *generator) graphqlResponses() map[string]*Response {
+	res := g.responses(http.StatusOK, nil, http.StatusBadRequest)
+	res[strconv.Itoa(http.StatusOK)] = &Response{
+		Description: "data of the query and the errors of the resolvers",
+		Content:     map[string]*MediaType{"application/json": {Schema: g.ref(types.GraphQLResponse{})}},
+	}
+
+	return res
+}
+
+// errorResponses returns the error components, as the default body or as problem details
+func (g *generator) errorResponses() map[string]*Response {
+	res := map[string]*Response{}
+
+	for status, name := range errorResponses {
+		r := Response{
+			Description: http.StatusText(status),
+			Content: map[string]*MediaType{
+				"application/json":          {Schema: g.ref(response.Body{})},
+				response.ProblemContentType: {Schema: g.ref(response.Problem{})},
+			},
+		}
+
+		if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+			r.Headers = map[string]*Header{
+				"Retry-After": {Description: "seconds to wait before retrying", Schema: &Schema{Type: "integer"}},
+			}
+		}
+
+		res[name] = &r
+	}
+
+	return res
+}
@@ -0,0 +1,118 @@
+package openapi_test
+
+import (
+	"article-tag/internal/openapi"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Spec(t *testing.T) {
+	spec := openapi.Spec()
+
+	t.Run("request body from the validation rules", func(t *testing.T) {
+		op := spec.Operation("post", "/tags/{publication}")
+		assert.NotNil(t, op)
+
+		body := op.RequestBody.Content["application/json"].Schema
+		assert.Equal(t, []string{"username", "tags"}, body.Required)
+		assert.NotContains(t, body.Properties, "publication")
+
+		tag := spec.Components.Schemas["Tag"]
+		assert.Equal(t, []string{"tag_id", "tag_name"}, tag.Required)
+		assert.NotEmpty(t, tag.Properties["tag_id"].Pattern)
+	})
+
+	t.Run("parameters from the validation rules", func(t *testing.T) {
+		op := spec.Operation("get", "/tags/{publication}")
+		assert.NotNil(t, op)
+
+		params := map[string]*openapi.Parameter{}
+		for _, val := range op.Parameters {
+			params[val.In+" "+val.Name] = val
+		}
+
+		assert.True(t, params["path publication"].Required)
+		assert.Equal(t, []string{"RS", "AK", "ST", "BC"}, params["path publication"].Schema.Enum)
+		assert.True(t, params["query username"].Required)
+		assert.False(t, params["query order"].Required)
+		assert.Equal(t, []string{"createdatdesc", "createdatasc", "tagname"}, params["query order"].Schema.Enum)
+	})
+
+	t.Run("limits and formats", func(t *testing.T) {
+		op := spec.Operation("get", "/admin/audit")
+		assert.NotNil(t, op)
+		assert.NotEmpty(t, op.Security)
+
+		for _, val := range op.Parameters {
+			switch val.Name {
+			case "limit":
+				assert.Equal(t, 1.0, *val.Schema.Minimum)
+				assert.Equal(t, 1000.0, *val.Schema.Maximum)
+			case "from", "to":
+				assert.Equal(t, "date-time", val.Schema.Format)
+			}
+		}
+	})
+
+	t.Run("versions", func(t *testing.T) {
+		v1 := spec.Operation("post", "/v1/tags/{publication}")
+		assert.NotNil(t, v1)
+		assert.False(t, v1.Deprecated)
+
+		unversioned := spec.Operation("post", "/tags/{publication}")
+		assert.True(t, unversioned.Deprecated)
+		assert.NotEqual(t, v1.OperationID, unversioned.OperationID)
+
+		v2 := spec.Operation("delete", "/v2/publications/{publication}/users/{username}/tags/{tagID}")
+		assert.NotNil(t, v2)
+		assert.Nil(t, v2.RequestBody)
+
+		params := []string{}
+		for _, val := range v2.Parameters {
+			params = append(params, val.In+" "+val.Name)
+		}
+
+		assert.ElementsMatch(t, []string{"path publication", "path username", "path tagID", "header If-Match"}, params)
+		assert.Equal(t, "#/components/responses/PreconditionFailed", v2.Responses["412"].Ref)
+	})
+
+	t.Run("error responses as body and problem details", func(t *testing.T) {
+		op := spec.Operation("delete", "/tags/{publication}")
+		assert.NotNil(t, op)
+		assert.Equal(t, "#/components/responses/Conflict", op.Responses["409"].Ref)
+		assert.Equal(t, "#/components/responses/TooManyRequests", op.Responses["429"].Ref)
+
+		conflict := spec.Components.Responses["Conflict"]
+		assert.Contains(t, conflict.Content, "application/problem+json")
+		assert.Contains(t, spec.Components.Schemas, "Problem")
+		assert.Contains(t, spec.Components.Responses["TooManyRequests"].Headers, "Retry-After")
+	})
+}
+
+func Test_Handler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	openapi.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, openapi.SpecPath, nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	doc := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+}
+
+func Test_SwaggerUI(t *testing.T) {
+	ui := openapi.SwaggerUI("/docs")
+
+	rec := httptest.NewRecorder()
+	ui.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "swagger-ui")
+
+	rec = httptest.NewRecorder()
+	ui.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/swagger-initializer.js", nil))
+	assert.Contains(t, rec.Body.String(), openapi.SpecPath)
+}
@@ -0,0 +1,66 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// SpecPath is where the document is served
+const SpecPath = "/openapi.json"
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+	specErr  error
+)
+
+// Handler serves the document as json, it is generated once
+func Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		specOnce.Do(func() {
+			specJSON, specErr = json.MarshalIndent(Spec(), "", "  ")
+		})
+
+		if specErr != nil {
+			http.Error(w, specErr.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(specJSON)
+	}
+}
+
+// swaggerInitializer loads the document of the service instead of the default petstore
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "` + SpecPath + `",
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// SwaggerUI serves the embedded swagger ui under the prefix, e.g. /docs
+func SwaggerUI(prefix string) http.Handler {
+	files := http.StripPrefix(prefix, http.FileServer(http.FS(swaggerFiles.FS)))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, prefix) {
+		case "":
+			http.Redirect(w, r, prefix+"/", http.StatusMovedPermanently)
+		case "/swagger-initializer.js":
+			w.Header().Set("Content-Type", "application/javascript")
+			w.Write([]byte(swaggerInitializer))
+		default:
+			files.ServeHTTP(w, r)
+		}
+	})
+}
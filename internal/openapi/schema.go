@@ -0,0 +1,199 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// numericPattern is the format accepted by the numeric validation
+const numericPattern = `^[-+]?[0-9]+(?:\.[0-9]+)?$`
+
+// generator builds the schemas of go types, structs are added to the components and referenced
+type generator struct {
+	schemas map[string]*Schema
+}
+
+// ref returns the schema of the value, registering the structs it uses as components
+func (g *generator) ref(v interface{}) *Schema {
+	return g.schemaOf(reflect.TypeOf(v))
+}
+
+// schemaOf
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schemaOf(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := g.schemas[name]; !ok {
+			// registered first so recursive types terminate
+			g.schemas[name] = &Schema{}
+			*g.schemas[name] = *g.object(t, nil)
+		}
+
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	// interface{}, any value
+	return &Schema{}
+}
+
+// object returns the inline schema of the struct without the omitted json fields
+func (g *generator) object(t reflect.Type, omit []string) *Schema {
+	s := Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for _, f := range fields(t, omit) {
+		prop := g.schemaOf(f.field.Type)
+		if applyRules(prop, f.field) {
+			s.Required = append(s.Required, f.name)
+		}
+
+		s.Properties[f.name] = prop
+	}
+
+	return &s
+}
+
+// parameters returns the struct fields as parameters of the location
+func (g *generator) parameters(v interface{}, in string, omit ...string) []*Parameter {
+	var params []*Parameter
+
+	for _, f := range fields(reflectType(v), omit) {
+		schema := g.schemaOf(f.field.Type)
+
+		params = append(params, &Parameter{
+			Name:     f.name,
+			In:       in,
+			Required: applyRules(schema, f.field),
+			Schema:   schema,
+		})
+	}
+
+	return params
+}
+
+// field of a struct with its json name
+type field struct {
+	name  string
+	field reflect.StructField
+}
+
+// fields returns the exported fields of the struct serialized to json
+func fields(t reflect.Type, omit []string) []field {
+	var res []field
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		if contains(omit, name) {
+			continue
+		}
+
+		res = append(res, field{name: name, field: f})
+	}
+
+	return res
+}
+
+// applyRules adds the validation rules of the field to the schema and reports whether the field is required.
+// Rules after dive apply to the elements and are documented by the schema of the element.
+func applyRules(s *Schema, f reflect.StructField) bool {
+	required := false
+
+	for _, rule := range strings.Split(f.Tag.Get("validate"), ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "dive":
+			return required
+		case "required":
+			required = true
+		case "oneof":
+			s.Enum = strings.Fields(param)
+		case "numeric":
+			s.Pattern = numericPattern
+		case "datetime":
+			s.Format = "date-time"
+		case "min", "max":
+			limit(s, name, param)
+		case "required_with":
+			s.Description = fmt.Sprintf("required when %s is set", param)
+		case "required_without":
+			s.Description = fmt.Sprintf("required when %s is not set", param)
+		}
+	}
+
+	return required
+}
+
+// limit sets the bound of a number or of the length of a string
+func limit(s *Schema, rule, param string) {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case s.Type == "integer" || s.Type == "number":
+		v := float64(n)
+		if rule == "min" {
+			s.Minimum = &v
+		} else {
+			s.Maximum = &v
+		}
+	case s.Type == "string":
+		if rule == "min" {
+			s.MinLength = &n
+		} else {
+			s.MaxLength = &n
+		}
+	}
+}
+
+// contains
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reflectType
+func reflectType(v interface{}) reflect.Type {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,91 @@
+package resilience
+
+import (
+	"sync"
+	"time"
+)
+
+// state of the circuit breaker
+type state int
+
+const (
+	stateClosed state = iota
+	stateOpen
+	stateHalfOpen
+)
+
+// Breaker opens after a number of consecutive failures and rejects calls until the cooldown has passed,
+// then a single trial call is let through which closes it again on success
+type Breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	state     state
+	openedAt  time.Time
+	now       func() time.Time
+}
+
+// NewBreaker, a threshold of 0 disables the breaker
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call can be made
+func (b *Breaker) Allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+
+		b.state = stateHalfOpen
+
+		return true
+	case stateHalfOpen:
+		// only the trial call is allowed
+		return false
+	}
+
+	return true
+}
+
+// Success closes the breaker
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.state = stateClosed
+}
+
+// Failure opens the breaker once the threshold is reached or when the trial call failed
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == stateHalfOpen || (b.threshold > 0 && b.failures >= b.threshold) {
+		b.state = stateOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Open reports whether calls are currently rejected
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state != stateClosed
+}
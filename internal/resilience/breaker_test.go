@@ -0,0 +1,50 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Breaker(t *testing.T) {
+	now := time.Now()
+	b := NewBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	assert.True(t, b.Allow())
+	b.Failure()
+	assert.True(t, b.Allow())
+	b.Failure()
+
+	// threshold reached
+	assert.True(t, b.Open())
+	assert.False(t, b.Allow())
+
+	// cooldown passed, a single trial call is allowed
+	now = now.Add(time.Minute)
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow())
+
+	// the trial call failed
+	b.Failure()
+	assert.False(t, b.Allow())
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.Allow())
+	b.Success()
+
+	assert.False(t, b.Open())
+	assert.True(t, b.Allow())
+	assert.True(t, b.Allow())
+}
+
+func Test_BreakerDisabled(t *testing.T) {
+	b := NewBreaker(0, time.Minute)
+
+	for i := 0; i < 10; i++ {
+		b.Failure()
+	}
+
+	assert.True(t, b.Allow())
+}
@@ -0,0 +1,229 @@
+package resilience
+
+import (
+	"article-tag/internal/config"
+	"article-tag/internal/constant"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+// dynamoAPI are the dynamodb operations used by the application
+type dynamoAPI interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+	UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
+	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)
+	UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)
+}
+
+// Client wraps the dynamodb client with retries, backoff, per call deadlines and a circuit breaker
+type Client struct {
+	db      dynamoAPI
+	policy  Policy
+	breaker *Breaker
+	logger  *zap.Logger
+}
+
+// NewClient reads the policy from the environment:
+//
+//	DYNAMO_MAX_ATTEMPTS, DYNAMO_BASE_DELAY, DYNAMO_MAX_DELAY, DYNAMO_CALL_TIMEOUT
+//	DYNAMO_BREAKER_THRESHOLD (0 disables the breaker), DYNAMO_BREAKER_COOLDOWN
+func NewClient(db dynamoAPI, logger *zap.Logger) *Client {
+	return &Client{
+		db: db,
+		policy: Policy{
+			MaxAttempts: config.Int("DYNAMO_MAX_ATTEMPTS", constant.RetryMaxAttempts),
+			BaseDelay:   config.Duration("DYNAMO_BASE_DELAY", constant.RetryBaseDelay),
+			MaxDelay:    config.Duration("DYNAMO_MAX_DELAY", constant.RetryMaxDelay),
+			CallTimeout: config.Duration("DYNAMO_CALL_TIMEOUT", constant.CallTimeout),
+		},
+		breaker: NewBreaker(
+			config.Int("DYNAMO_BREAKER_THRESHOLD", constant.BreakerThreshold),
+			config.Duration("DYNAMO_BREAKER_COOLDOWN", constant.BreakerCooldown),
+		),
+		logger: logger,
+	}
+}
+
+// call runs the operation with the policy of the client, an operation which is not idempotent
+// is not retried once timed out
+func call[T any](ctx context.Context, c *Client, operation string, idempotent bool, fn func(ctx context.Context) (T, error)) (T, error) {
+	var out T
+
+	do := c.policy.Do
+	if !idempotent {
+		do = c.policy.DoNonIdempotent
+	}
+
+	err := do(ctx, c.breaker, func(ctx context.Context) error {
+		var err error
+		out, err = fn(ctx)
+
+		return err
+	})
+	if err != nil {
+		c.logger.Debug("dynamodb call failed", zap.String("operation", operation), zap.Error(err))
+	}
+
+	return out, err
+}
+
+// DescribeTable
+func (c *Client) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	return call(ctx, c, "DescribeTable", true, func(ctx context.Context) (*dynamodb.DescribeTableOutput, error) {
+		return c.db.DescribeTable(ctx, params, optFns...)
+	})
+}
+
+// CreateTable
+func (c *Client) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	return call(ctx, c, "CreateTable", true, func(ctx context.Context) (*dynamodb.CreateTableOutput, error) {
+		return c.db.CreateTable(ctx, params, optFns...)
+	})
+}
+
+// UpdateTable
+func (c *Client) UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error) {
+	return call(ctx, c, "UpdateTable", true, func(ctx context.Context) (*dynamodb.UpdateTableOutput, error) {
+		return c.db.UpdateTable(ctx, params, optFns...)
+	})
+}
+
+// GetItem
+func (c *Client) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return call(ctx, c, "GetItem", true, func(ctx context.Context) (*dynamodb.GetItemOutput, error) {
+		return c.db.GetItem(ctx, params, optFns...)
+	})
+}
+
+// PutItem
+func (c *Client) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return call(ctx, c, "PutItem", repeatable(params.ConditionExpression, params.ReturnValues), func(ctx context.Context) (*dynamodb.PutItemOutput, error) {
+		return c.db.PutItem(ctx, params, optFns...)
+	})
+}
+
+// UpdateItem
+func (c *Client) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return call(ctx, c, "UpdateItem", idempotentUpdate(params), func(ctx context.Context) (*dynamodb.UpdateItemOutput, error) {
+		return c.db.UpdateItem(ctx, params, optFns...)
+	})
+}
+
+// Query
+func (c *Client) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return call(ctx, c, "Query", true, func(ctx context.Context) (*dynamodb.QueryOutput, error) {
+		return c.db.Query(ctx, params, optFns...)
+	})
+}
+
+// DeleteItem
+func (c *Client) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	return call(ctx, c, "DeleteItem", repeatable(params.ConditionExpression, params.ReturnValues), func(ctx context.Context) (*dynamodb.DeleteItemOutput, error) {
+		return c.db.DeleteItem(ctx, params, optFns...)
+	})
+}
+
+// BatchGetItem, unprocessed keys are returned to the caller which retries them
+func (c *Client) BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	return call(ctx, c, "BatchGetItem", true, func(ctx context.Context) (*dynamodb.BatchGetItemOutput, error) {
+		return c.db.BatchGetItem(ctx, params, optFns...)
+	})
+}
+
+// TransactWriteItems, a cancelled transaction is returned to the caller. Every attempt sends the same
+// client request token, so dynamodb applies a retried transaction once.
+func (c *Client) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	if params.ClientRequestToken == nil {
+		input := *params
+		input.ClientRequestToken = aws.String(requestToken())
+		params = &input
+	}
+
+	return call(ctx, c, "TransactWriteItems", true, func(ctx context.Context) (*dynamodb.TransactWriteItemsOutput, error) {
+		return c.db.TransactWriteItems(ctx, params, optFns...)
+	})
+}
+
+// BatchWriteItem, unprocessed items are returned to the caller which retries them
+func (c *Client) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	return call(ctx, c, "BatchWriteItem", true, func(ctx context.Context) (*dynamodb.BatchWriteItemOutput, error) {
+		return c.db.BatchWriteItem(ctx, params, optFns...)
+	})
+}
+
+// Scan
+func (c *Client) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return call(ctx, c, "Scan", true, func(ctx context.Context) (*dynamodb.ScanOutput, error) {
+		return c.db.Scan(ctx, params, optFns...)
+	})
+}
+
+// DescribeTimeToLive
+func (c *Client) DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	return call(ctx, c, "DescribeTimeToLive", true, func(ctx context.Context) (*dynamodb.DescribeTimeToLiveOutput, error) {
+		return c.db.DescribeTimeToLive(ctx, params, optFns...)
+	})
+}
+
+// UpdateTimeToLive
+func (c *Client) UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	return call(ctx, c, "UpdateTimeToLive", true, func(ctx context.Context) (*dynamodb.UpdateTimeToLiveOutput, error) {
+		return c.db.UpdateTimeToLive(ctx, params, optFns...)
+	})
+}
+
+// idempotentUpdate tells whether applying the update twice leaves the item as applying it once,
+// an ADD or an arithmetic SET changes it again
+func idempotentUpdate(params *dynamodb.UpdateItemInput) bool {
+	if !repeatable(params.ConditionExpression, params.ReturnValues) {
+		return false
+	}
+
+	if params.UpdateExpression == nil {
+		return true
+	}
+
+	expr := *params.UpdateExpression
+	if strings.ContainsAny(expr, "+-") {
+		return false
+	}
+
+	for _, val := range strings.Fields(expr) {
+		if strings.EqualFold(val, "ADD") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// repeatable tells whether a write answers a retry as it answered the first attempt. A retry of an applied
+// conditional write fails its condition and a retry asking for the old item gets the item it wrote.
+func repeatable(condition *string, returnValues types.ReturnValue) bool {
+	return condition == nil && (returnValues == "" || returnValues == types.ReturnValueNone)
+}
+
+// requestToken returns a random client request token
+func requestToken() string {
+	token := make([]byte, 16)
+	_, _ = rand.Read(token)
+
+	return hex.EncodeToString(token)
+}
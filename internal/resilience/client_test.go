@@ -0,0 +1,252 @@
+package resilience_test
+
+import (
+	"article-tag/internal/mocks"
+	"article-tag/internal/resilience"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func testSuite() *zap.Logger {
+	rawJSON := []byte(`{
+		"level": "debug",
+		"encoding": "json",
+		"outputPaths": ["stdout", "/tmp/logs"],
+		"errorOutputPaths": ["stderr"],
+		"encoderConfig": {
+		  "messageKey": "message",
+		  "levelKey": "level",
+		  "levelEncoder": "lowercase"
+		}
+	  }`)
+
+	var cfg zap.Config
+	if err := json.Unmarshal(rawJSON, &cfg); err != nil {
+		panic(err)
+	}
+
+	return zap.Must(cfg.Build())
+}
+
+func Test_ClientPutItem(t *testing.T) {
+	t.Setenv("DYNAMO_MAX_ATTEMPTS", "2")
+	t.Setenv("DYNAMO_BASE_DELAY", "1ms")
+
+	throttled := &types.ProvisionedThroughputExceededException{Message: new(string)}
+
+	tests := []struct {
+		name    string
+		mockDB  func(db *mocks.MigrationAPI)
+		wantErr error
+	}{
+		{
+			name: "retried after throttling",
+			mockDB: func(db *mocks.MigrationAPI) {
+				db.EXPECT().PutItem(mock.Anything, mock.Anything).Return(nil, throttled).Once()
+				db.EXPECT().PutItem(mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+			},
+		},
+		{
+			name: "throttled",
+			mockDB: func(db *mocks.MigrationAPI) {
+				db.EXPECT().PutItem(mock.Anything, mock.Anything).Return(nil, throttled).Times(2)
+			},
+			wantErr: resilience.ErrThrottled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := mocks.NewMigrationAPI(t)
+			tt.mockDB(db)
+
+			c := resilience.NewClient(db, testSuite())
+
+			_, err := c.PutItem(context.Background(), &dynamodb.PutItemInput{})
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ClientUpdateItem(t *testing.T) {
+	t.Setenv("DYNAMO_MAX_ATTEMPTS", "2")
+	t.Setenv("DYNAMO_BASE_DELAY", "1ms")
+	t.Setenv("DYNAMO_CALL_TIMEOUT", "1ms")
+
+	timeout := func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	tests := []struct {
+		name      string
+		params    *dynamodb.UpdateItemInput
+		wantCalls int
+	}{
+		{
+			name:      "timed out SET is retried",
+			params:    &dynamodb.UpdateItemInput{UpdateExpression: aws.String("SET TagName = :v1")},
+			wantCalls: 2,
+		},
+		{
+			name:      "timed out ADD is not retried",
+			params:    &dynamodb.UpdateItemInput{UpdateExpression: aws.String("ADD #v1 :incr")},
+			wantCalls: 1,
+		},
+		{
+			name:      "timed out increment is not retried",
+			params:    &dynamodb.UpdateItemInput{UpdateExpression: aws.String("SET TagCount = if_not_exists(TagCount, :v1) + :incr")},
+			wantCalls: 1,
+		},
+		{
+			name: "timed out conditional SET is not retried",
+			params: &dynamodb.UpdateItemInput{
+				UpdateExpression:    aws.String("SET DeletedAt = :v1"),
+				ConditionExpression: aws.String("attribute_exists(PK) AND attribute_not_exists(DeletedAt)"),
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "timed out SET returning the old item is not retried",
+			params: &dynamodb.UpdateItemInput{
+				UpdateExpression: aws.String("SET TagName = :v1"),
+				ReturnValues:     types.ReturnValueAllOld,
+			},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := mocks.NewMigrationAPI(t)
+			db.EXPECT().UpdateItem(mock.Anything, mock.Anything).RunAndReturn(timeout).Times(tt.wantCalls)
+
+			c := resilience.NewClient(db, testSuite())
+
+			_, err := c.UpdateItem(context.Background(), tt.params)
+			assert.ErrorIs(t, err, resilience.ErrUnavailable)
+		})
+	}
+}
+
+func Test_ClientConditionalWrites(t *testing.T) {
+	t.Setenv("DYNAMO_MAX_ATTEMPTS", "2")
+	t.Setenv("DYNAMO_BASE_DELAY", "1ms")
+	t.Setenv("DYNAMO_CALL_TIMEOUT", "1ms")
+
+	putTimeout := func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	deleteTimeout := func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	tests := []struct {
+		name   string
+		call   func(c *resilience.Client) error
+		mockDB func(db *mocks.MigrationAPI)
+	}{
+		{
+			name: "timed out put is retried",
+			call: func(c *resilience.Client) error {
+				_, err := c.PutItem(context.Background(), &dynamodb.PutItemInput{})
+				return err
+			},
+			mockDB: func(db *mocks.MigrationAPI) {
+				db.EXPECT().PutItem(mock.Anything, mock.Anything).RunAndReturn(putTimeout).Times(2)
+			},
+		},
+		{
+			name: "timed out put returning the old item is not retried",
+			call: func(c *resilience.Client) error {
+				_, err := c.PutItem(context.Background(), &dynamodb.PutItemInput{ReturnValues: types.ReturnValueAllOld})
+				return err
+			},
+			mockDB: func(db *mocks.MigrationAPI) {
+				db.EXPECT().PutItem(mock.Anything, mock.Anything).RunAndReturn(putTimeout).Once()
+			},
+		},
+		{
+			name: "timed out conditional put is not retried",
+			call: func(c *resilience.Client) error {
+				_, err := c.PutItem(context.Background(), &dynamodb.PutItemInput{ConditionExpression: aws.String("attribute_not_exists(PK)")})
+				return err
+			},
+			mockDB: func(db *mocks.MigrationAPI) {
+				db.EXPECT().PutItem(mock.Anything, mock.Anything).RunAndReturn(putTimeout).Once()
+			},
+		},
+		{
+			name: "timed out delete is retried",
+			call: func(c *resilience.Client) error {
+				_, err := c.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{})
+				return err
+			},
+			mockDB: func(db *mocks.MigrationAPI) {
+				db.EXPECT().DeleteItem(mock.Anything, mock.Anything).RunAndReturn(deleteTimeout).Times(2)
+			},
+		},
+		{
+			name: "timed out conditional delete is not retried",
+			call: func(c *resilience.Client) error {
+				_, err := c.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{ConditionExpression: aws.String("attribute_exists(PK)")})
+				return err
+			},
+			mockDB: func(db *mocks.MigrationAPI) {
+				db.EXPECT().DeleteItem(mock.Anything, mock.Anything).RunAndReturn(deleteTimeout).Once()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := mocks.NewMigrationAPI(t)
+			tt.mockDB(db)
+
+			c := resilience.NewClient(db, testSuite())
+
+			assert.ErrorIs(t, tt.call(c), resilience.ErrUnavailable)
+		})
+	}
+}
+
+func Test_ClientTransactWriteItems(t *testing.T) {
+	t.Setenv("DYNAMO_MAX_ATTEMPTS", "2")
+	t.Setenv("DYNAMO_BASE_DELAY", "1ms")
+
+	server := &types.InternalServerError{Message: new(string)}
+
+	// every attempt sends the same token, so dynamodb applies the transaction once
+	tokens := []string{}
+	db := mocks.NewMigrationAPI(t)
+	db.EXPECT().TransactWriteItems(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) {
+			tokens = append(tokens, aws.ToString(params.ClientRequestToken))
+		}).Return(nil, server).Once()
+	db.EXPECT().TransactWriteItems(mock.Anything, mock.Anything).
+		Run(func(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) {
+			tokens = append(tokens, aws.ToString(params.ClientRequestToken))
+		}).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+
+	c := resilience.NewClient(db, testSuite())
+
+	_, err := c.TransactWriteItems(context.Background(), &dynamodb.TransactWriteItemsInput{})
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+	assert.NotEmpty(t, tokens[0])
+	assert.Equal(t, tokens[0], tokens[1])
+}
@@ -0,0 +1,81 @@
+package resilience
+
+import (
+	"article-tag/internal/apperror"
+	"context"
+	"errors"
+	"net/http"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+)
+
+var (
+	// ErrThrottled is returned when dynamodb keeps throttling the call after every retry
+	ErrThrottled = apperror.New(apperror.KindThrottled, apperror.CodeStoreThrottled, "too many requests, retry later")
+	// ErrUnavailable is returned when dynamodb keeps failing or the circuit breaker is open
+	ErrUnavailable = apperror.New(apperror.KindUnavailable, apperror.CodeStoreUnavailable, "service unavailable, retry later")
+)
+
+// throttlingCodes are the error codes dynamodb returns when the capacity or request rate is exceeded
+var throttlingCodes = map[string]bool{
+	"ProvisionedThroughputExceededException": true,
+	"RequestLimitExceeded":                   true,
+	"ThrottlingException":                    true,
+	"LimitExceededException":                 true,
+}
+
+// serverCodes are the error codes of transient dynamodb failures
+var serverCodes = map[string]bool{
+	"InternalServerError": true,
+	"ServiceUnavailable":  true,
+}
+
+// errorClass
+type errorClass int
+
+const (
+	classPermanent errorClass = iota
+	classThrottled
+	classTransient
+	// classTimeout is transient, but the call may have been applied by dynamodb
+	classTimeout
+)
+
+// classify tells whether the error can be retried. parent is the context of the caller,
+// a deadline of the single call is a timeout while the deadline of the caller is permanent.
+func classify(parent context.Context, err error) errorClass {
+	if parent.Err() != nil {
+		return classPermanent
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if throttlingCodes[apiErr.ErrorCode()] {
+			return classThrottled
+		}
+
+		if serverCodes[apiErr.ErrorCode()] {
+			return classTransient
+		}
+	}
+
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		if respErr.HTTPStatusCode() == http.StatusTooManyRequests {
+			return classThrottled
+		}
+
+		if respErr.HTTPStatusCode() >= http.StatusInternalServerError {
+			return classTransient
+		}
+
+		return classPermanent
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return classTimeout
+	}
+
+	return classPermanent
+}
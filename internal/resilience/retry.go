@@ -0,0 +1,121 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Policy of the retries of a single dynamodb call
+type Policy struct {
+	// MaxAttempts including the first call
+	MaxAttempts int
+	// BaseDelay is doubled on every retry up to MaxDelay, the actual delay is a random duration up to it
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// CallTimeout is the deadline of a single attempt, bounded by the deadline of the caller
+	CallTimeout time.Duration
+}
+
+// Do calls fn until it succeeds, fails permanently or the attempts are exhausted.
+// The breaker is consulted before every attempt and told about the outcome of the call.
+func (p Policy) Do(ctx context.Context, breaker *Breaker, fn func(ctx context.Context) error) error {
+	return p.do(ctx, breaker, true, fn)
+}
+
+// DoNonIdempotent is Do for a call which changes the item again when applied twice, e.g. an ADD to a counter.
+// An attempt which timed out may have been applied, so it is not retried.
+func (p Policy) DoNonIdempotent(ctx context.Context, breaker *Breaker, fn func(ctx context.Context) error) error {
+	return p.do(ctx, breaker, false, fn)
+}
+
+// do retries the timed out attempts only when idempotent
+func (p Policy) do(ctx context.Context, breaker *Breaker, idempotent bool, fn func(ctx context.Context) error) error {
+	attempts := p.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if werr := sleep(ctx, p.backoff(attempt)); werr != nil {
+				return werr
+			}
+		}
+
+		if !breaker.Allow() {
+			return fmt.Errorf("%w: circuit breaker is open", ErrUnavailable)
+		}
+
+		err = p.call(ctx, fn)
+		if err == nil {
+			breaker.Success()
+			return nil
+		}
+
+		switch classify(ctx, err) {
+		case classPermanent:
+			// dynamodb answered, the error is not a sign of overload
+			breaker.Success()
+			return err
+		case classTimeout:
+			breaker.Failure()
+			if !idempotent {
+				return fmt.Errorf("%w: %w", ErrUnavailable, err)
+			}
+		default:
+			breaker.Failure()
+		}
+	}
+
+	if classify(ctx, err) == classThrottled {
+		return fmt.Errorf("%w: %w", ErrThrottled, err)
+	}
+
+	return fmt.Errorf("%w: %w", ErrUnavailable, err)
+}
+
+// call runs a single attempt with its own deadline
+func (p Policy) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.CallTimeout <= 0 {
+		return fn(ctx)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, p.CallTimeout)
+	defer cancel()
+
+	return fn(callCtx)
+}
+
+// backoff returns the jittered delay before the retry
+func (p Policy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// sleep waits for the delay unless the context is done first
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,190 @@
+package resilience_test
+
+import (
+	"article-tag/internal/resilience"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PolicyDo(t *testing.T) {
+	throttled := &types.ProvisionedThroughputExceededException{Message: new(string)}
+	server := &types.InternalServerError{Message: new(string)}
+	conditional := &types.ConditionalCheckFailedException{Message: new(string)}
+
+	tests := []struct {
+		name         string
+		errs         []error
+		wantCalls    int
+		wantErr      error
+		wantOriginal error
+	}{
+		{
+			name:      "success",
+			errs:      []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "success after throttling",
+			errs:      []error{throttled, throttled, nil},
+			wantCalls: 3,
+		},
+		{
+			name:         "throttled on every attempt",
+			errs:         []error{throttled, throttled, throttled},
+			wantCalls:    3,
+			wantErr:      resilience.ErrThrottled,
+			wantOriginal: throttled,
+		},
+		{
+			name:         "server error on every attempt",
+			errs:         []error{server, server, server},
+			wantCalls:    3,
+			wantErr:      resilience.ErrUnavailable,
+			wantOriginal: server,
+		},
+		{
+			name:         "permanent error is not retried",
+			errs:         []error{conditional},
+			wantCalls:    1,
+			wantOriginal: conditional,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := resilience.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+			calls := 0
+			err := p.Do(context.Background(), resilience.NewBreaker(0, 0), func(ctx context.Context) error {
+				calls++
+				return tt.errs[calls-1]
+			})
+
+			assert.Equal(t, tt.wantCalls, calls)
+
+			if tt.wantErr == nil && tt.wantOriginal == nil {
+				assert.NoError(t, err)
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+
+			if tt.wantOriginal != nil {
+				assert.ErrorIs(t, err, tt.wantOriginal)
+			}
+		})
+	}
+}
+
+func Test_PolicyDoCallTimeout(t *testing.T) {
+	p := resilience.Policy{MaxAttempts: 2, CallTimeout: time.Millisecond}
+
+	calls := 0
+	err := p.Do(context.Background(), resilience.NewBreaker(0, 0), func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	// the deadline of a single attempt is retried
+	assert.Equal(t, 2, calls)
+	assert.ErrorIs(t, err, resilience.ErrUnavailable)
+}
+
+func Test_PolicyDoNonIdempotent(t *testing.T) {
+	throttled := &types.ProvisionedThroughputExceededException{Message: new(string)}
+
+	tests := []struct {
+		name      string
+		fn        func(ctx context.Context, calls int) error
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name: "timed out attempt is not retried",
+			fn: func(ctx context.Context, calls int) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			wantCalls: 1,
+			wantErr:   resilience.ErrUnavailable,
+		},
+		{
+			name: "throttled attempt is retried",
+			fn: func(ctx context.Context, calls int) error {
+				if calls == 1 {
+					return throttled
+				}
+
+				return nil
+			},
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := resilience.Policy{MaxAttempts: 2, CallTimeout: time.Millisecond}
+
+			calls := 0
+			err := p.DoNonIdempotent(context.Background(), resilience.NewBreaker(0, 0), func(ctx context.Context) error {
+				calls++
+				return tt.fn(ctx, calls)
+			})
+
+			assert.Equal(t, tt.wantCalls, calls)
+
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_PolicyDoCanceled(t *testing.T) {
+	p := resilience.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	err := p.Do(ctx, resilience.NewBreaker(0, 0), func(ctx context.Context) error {
+		calls++
+		cancel()
+
+		return ctx.Err()
+	})
+
+	// the caller gave up, the call is not retried
+	assert.Equal(t, 1, calls)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, errors.Is(err, resilience.ErrUnavailable))
+}
+
+func Test_PolicyDoBreaker(t *testing.T) {
+	p := resilience.Policy{MaxAttempts: 1}
+	breaker := resilience.NewBreaker(2, time.Minute)
+	server := &types.InternalServerError{Message: new(string)}
+
+	calls := 0
+	fn := func(ctx context.Context) error {
+		calls++
+		return server
+	}
+
+	for i := 0; i < 3; i++ {
+		err := p.Do(context.Background(), breaker, fn)
+		assert.ErrorIs(t, err, resilience.ErrUnavailable)
+	}
+
+	// the third call is rejected by the open breaker
+	assert.Equal(t, 2, calls)
+}
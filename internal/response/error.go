@@ -0,0 +1,47 @@
+package response
+
+import (
+	"article-tag/internal/apperror"
+	"net/http"
+	"strconv"
+)
+
+// retryAfter is the number of seconds clients are asked to wait when the store is overloaded
+const retryAfter = 1
+
+// kindStatus
+var kindStatus = map[apperror.Kind]int{
+	apperror.KindNotFound:           http.StatusNotFound,
+	apperror.KindConflict:           http.StatusConflict,
+	apperror.KindValidationFailed:   http.StatusUnprocessableEntity,
+	apperror.KindThrottled:          http.StatusTooManyRequests,
+	apperror.KindUnavailable:        http.StatusServiceUnavailable,
+	apperror.KindPreconditionFailed: http.StatusPreconditionFailed,
+}
+
+// Error maps a domain error to its status and code, any other error is an internal server error with the message
+func Error(w http.ResponseWriter, err error, msg string) {
+	e, ok := apperror.As(err)
+	if !ok {
+		InternalServerError(w, msg)
+		return
+	}
+
+	status, ok := kindStatus[e.Kind]
+	if !ok {
+		InternalServerError(w, msg)
+		return
+	}
+
+	if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+
+	b := Body{
+		Status:  status,
+		Message: e.Message,
+		Code:    e.Code,
+	}
+
+	sendResponse(w, &b)
+}
@@ -0,0 +1,128 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	Code          string         `json:"code,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+}
+
+// InvalidParam is a request parameter which failed the validation
+type InvalidParam struct {
+	// Name is the path of the parameter in the request, e.g. tags[0].tag_id
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	// Rule is the validation rule which failed, e.g. numeric
+	Rule string `json:"rule,omitempty"`
+	// Field is the struct field, used as the key of the default error format
+	Field string `json:"-"`
+}
+
+// problemWriter marks the response writer of a client accepting problem details
+type problemWriter struct {
+	http.ResponseWriter
+	instance string
+}
+
+// ProblemWriter makes the error responses written to w problem details, instance identifies the request
+func ProblemWriter(w http.ResponseWriter, instance string) http.ResponseWriter {
+	return &problemWriter{ResponseWriter: w, instance: instance}
+}
+
+// Unwrap returns the original response writer, used by http.ResponseController
+func (p *problemWriter) Unwrap() http.ResponseWriter {
+	return p.ResponseWriter
+}
+
+// Flush keeps streamed responses working
+func (p *problemWriter) Flush() {
+	if f, ok := p.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// AcceptsProblem reports whether the Accept header lists application/problem+json
+func AcceptsProblem(accept string) bool {
+	for _, val := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(val, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), ProblemContentType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// asProblemWriter finds the problem writer among the wrapped response writers
+func asProblemWriter(w http.ResponseWriter) (*problemWriter, bool) {
+	for {
+		if pw, ok := w.(*problemWriter); ok {
+			return pw, true
+		}
+
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil, false
+		}
+
+		w = u.Unwrap()
+	}
+}
+
+// sendProblem writes the error body as problem details
+func sendProblem(w http.ResponseWriter, instance string, b *Body) {
+	p := Problem{
+		Type:          "about:blank",
+		Title:         http.StatusText(b.Status),
+		Status:        b.Status,
+		Detail:        b.Message,
+		Instance:      instance,
+		Code:          b.Code,
+		InvalidParams: invalidParams(b.Errors),
+	}
+
+	if b.Code != "" {
+		p.Type = "/problems/" + b.Code
+	}
+
+	if p.Detail == "" && len(p.InvalidParams) > 0 {
+		p.Detail = "one or more request parameters are invalid"
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(b.Status)
+
+	json.NewEncoder(w).Encode(p)
+}
+
+// invalidParams of the errors of the body
+func invalidParams(errs interface{}) []InvalidParam {
+	params, _ := errs.([]InvalidParam)
+
+	return params
+}
+
+// fieldErrors is the default format of the invalid params, the error message by struct field
+func fieldErrors(params []InvalidParam) []map[string]interface{} {
+	errorBag := []map[string]interface{}{}
+	for _, v := range params {
+		errorBag = append(errorBag, map[string]interface{}{
+			v.Field: v.Reason,
+		})
+	}
+
+	return errorBag
+}
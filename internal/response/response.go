@@ -8,6 +8,7 @@ import (
 type Body struct {
 	Status  int         `json:"status"`
 	Message string      `json:"message"`
+	Code    string      `json:"code,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Errors  interface{} `json:"errors,omitempty"`
 }
@@ -53,8 +54,31 @@ func InternalServerError(w http.ResponseWriter, msg string) {
 	sendResponse(w, &b)
 }
 
+// defaultCode of the error responses without a specific code
+var defaultCode = map[int]string{
+	http.StatusBadRequest:          "invalid_request",
+	http.StatusUnauthorized:        "unauthorized",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "not_found",
+	http.StatusMethodNotAllowed:    "method_not_allowed",
+	http.StatusInternalServerError: "internal_error",
+}
+
 // sendResponse
 func sendResponse(w http.ResponseWriter, b *Body) {
+	if b.Code == "" {
+		b.Code = defaultCode[b.Status]
+	}
+
+	if pw, ok := asProblemWriter(w); ok && b.Status >= http.StatusBadRequest {
+		sendProblem(w, pw.instance, b)
+		return
+	}
+
+	if params, ok := b.Errors.([]InvalidParam); ok {
+		b.Errors = fieldErrors(params)
+	}
+
 	w.Header().Set("content-Type", "application/json")
 	w.WriteHeader(b.Status)
 
@@ -80,3 +104,23 @@ func NotAllowded(w http.ResponseWriter, msg string) {
 
 	sendResponse(w, &b)
 }
+
+// Unauthorized
+func Unauthorized(w http.ResponseWriter, msg string) {
+	b := Body{
+		Status:  http.StatusUnauthorized,
+		Message: msg,
+	}
+
+	sendResponse(w, &b)
+}
+
+// Forbidden
+func Forbidden(w http.ResponseWriter, msg string) {
+	b := Body{
+		Status:  http.StatusForbidden,
+		Message: msg,
+	}
+
+	sendResponse(w, &b)
+}
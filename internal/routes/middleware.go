@@ -2,10 +2,15 @@ package routes
 
 import (
 	"article-tag/internal/handler"
+	"article-tag/internal/response"
 	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"go.uber.org/zap/zapcore"
 )
@@ -31,3 +36,119 @@ func LogRequest(app *handler.Application) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// AdminOnly allows the request only when the X-Admin-Token header matches the token,
+// admin routes are disabled when no token is configured
+func AdminOnly(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				response.Forbidden(w, "admin api is disabled")
+				return
+			}
+
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Token")), []byte(token)) != 1 {
+				response.Unauthorized(w, "invalid admin token")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ProblemDetails responds errors as application/problem+json (RFC 7807) to clients
+// listing it in the Accept header, other clients keep the default error body
+func ProblemDetails(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if response.AcceptsProblem(r.Header.Get("Accept")) {
+			w = response.ProblemWriter(w, r.URL.Path)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Deprecated flags the responses of routes kept for compatibility with a Deprecation header,
+// the Link header points to the same route under the version replacing it
+func Deprecated(version string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", "<"+version+r.URL.Path+`>; rel="successor-version"`)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ETag sets a strong etag computed from the body of successful responses along with the Cache-Control header,
+// an etag set by the handler is kept. A request whose If-None-Match matches the etag gets 304 without the body.
+func ETag(cacheControl string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			buf := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(buf, r)
+
+			if buf.status != http.StatusOK {
+				w.WriteHeader(buf.status)
+				w.Write(buf.body.Bytes())
+
+				return
+			}
+
+			etag := w.Header().Get("ETag")
+			if etag == "" {
+				etag = fmt.Sprintf(`"%x"`, sha256.Sum256(buf.body.Bytes()))
+				w.Header().Set("ETag", etag)
+			}
+
+			if cacheControl != "" {
+				w.Header().Set("Cache-Control", cacheControl)
+			}
+
+			if etagMatch(r.Header.Get("If-None-Match"), etag) {
+				w.Header().Del("Content-Type")
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			w.WriteHeader(buf.status)
+			w.Write(buf.body.Bytes())
+		})
+	}
+}
+
+// bufferedWriter holds the response until the etag is computed, the headers are written to the wrapped writer
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedWriter) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+// Unwrap returns the wrapped response writer, so the response helpers find the problem writer
+func (b *bufferedWriter) Unwrap() http.ResponseWriter {
+	return b.ResponseWriter
+}
+
+// etagMatch compares the etags of an If-None-Match header with the weak comparison of RFC 7232
+func etagMatch(ifNoneMatch, etag string) bool {
+	for _, val := range strings.Split(ifNoneMatch, ",") {
+		val = strings.TrimPrefix(strings.TrimSpace(val), "W/")
+		if val == "*" || val == etag {
+			return true
+		}
+	}
+
+	return false
+}
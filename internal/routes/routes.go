@@ -1,19 +1,33 @@
 package routes
 
 import (
+	"article-tag/internal/config"
+	"article-tag/internal/constant"
+	"article-tag/internal/graph"
 	"article-tag/internal/handler"
+	"article-tag/internal/openapi"
 	"article-tag/internal/response"
 	"net/http"
+	"os"
 
 	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
 )
 
 func InitRouter(app *handler.Application) *chi.Mux {
 	r := chi.NewRouter()
+	models := handler.GetModels(app)
+
+	// middleware request id and client ip, used by the audit trail
+	r.Use(middleware.RequestID)
+	r.Use(middleware.RealIP)
 
 	// middleware log request
 	r.Use(LogRequest(app))
 
+	// middleware error format negotiation
+	r.Use(ProblemDetails)
+
 	// sets a custom message for 404 error status code
 	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
 		response.NotFound(w, "requested url is unavailable")
@@ -26,13 +40,76 @@ func InitRouter(app *handler.Application) *chi.Mux {
 		return
 	})
 
-	// route group
-	r.Route("/tags", func(r chi.Router) {
-		r.Post("/{publication}", app.Store())
-		r.Get("/{publication}", app.Get())
-		r.Delete("/{publication}", app.Delete())
-		r.Get("/{publication}/popular", app.PopularTag())
+	// api documentation
+	r.Get(openapi.SpecPath, openapi.Handler())
+	r.Handle("/docs", openapi.SwaggerUI("/docs"))
+	r.Handle("/docs/*", openapi.SwaggerUI("/docs"))
+
+	// v1 route group
+	r.Route("/v1", func(r chi.Router) {
+		r.Route("/tags", tagRoutes(app))
+	})
+
+	// v2 route group, the user and the tag are resources of the path
+	r.Route("/v2/publications/{publication}", func(r chi.Router) {
+		r.With(ETag(tagsCacheControl())).Get("/users/{username}/tags", app.ListTags())
+		r.Put("/users/{username}/tags/{tagID}", app.PutTag())
+		r.Delete("/users/{username}/tags/{tagID}", app.DeleteTag())
+		r.Post("/users/{username}/tags/{tagID}/restore", app.RestoreTag())
+		r.With(ETag(popularCacheControl())).Get("/tags/popular", app.PopularTag())
+		r.With(ETag(popularCacheControl())).Get("/tags/leaderboard", app.Leaderboard())
+		r.Get("/tags/{tagID}/subtree", app.Subtree())
+	})
+
+	// unversioned routes of v1, kept until clients move to /v1
+	r.With(Deprecated("/v1")).Route("/tags", tagRoutes(app))
+
+	// graphql, resolved with the models of the rest api
+	r.Post(graph.Path, graph.New(&models, handler.GetLogger(app)).Handler())
+
+	// user data route group, used for gdpr requests
+	r.Route("/users", func(r chi.Router) {
+		r.Use(AdminOnly(os.Getenv("ADMIN_TOKEN")))
+
+		r.Get("/{username}/data", app.UserData())
+		r.Delete("/{username}", app.EraseUser())
+	})
+
+	// admin route group
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(AdminOnly(os.Getenv("ADMIN_TOKEN")))
+
+		r.Get("/publications/{publication}/export", app.Export())
+		r.Get("/audit", app.AuditLog())
+		r.Get("/cache", app.CacheStats())
+		r.Get("/users/{username}/publications/{publication}/limit", app.FollowLimit())
+		r.Put("/users/{username}/publications/{publication}/limit", app.SetFollowLimit())
+		r.Put("/publications/{publication}/tags/{tagID}", app.PutTaxonomyTag())
+		r.Put("/publications/{publication}/tags/{tagID}/aliases", app.PutAliases())
+		r.Post("/publications/{publication}/tags/{tagID}/merge", app.Merge())
 	})
 
 	return r
 }
+
+// tagRoutes of v1
+func tagRoutes(app *handler.Application) func(r chi.Router) {
+	return func(r chi.Router) {
+		r.Post("/{publication}", app.Store())
+		r.Put("/{publication}", app.Replace())
+		r.With(ETag(tagsCacheControl())).Get("/{publication}", app.Get())
+		r.Delete("/{publication}", app.Delete())
+		r.Post("/{publication}/undo", app.Undo())
+		r.With(ETag(popularCacheControl())).Get("/{publication}/popular", app.PopularTag())
+	}
+}
+
+// tagsCacheControl of the followed tags, they are private to the user and revalidated with their etag
+func tagsCacheControl() string {
+	return config.String("CACHE_CONTROL_TAGS", constant.CacheControlTags)
+}
+
+// popularCacheControl of the popular tags and leaderboard
+func popularCacheControl() string {
+	return config.String("CACHE_CONTROL_POPULAR", constant.CacheControlPopular)
+}
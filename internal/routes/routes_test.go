@@ -0,0 +1,162 @@
+package routes_test
+
+import (
+	"article-tag/internal/handler"
+	"article-tag/internal/mocks"
+	"article-tag/internal/model"
+	"article-tag/internal/openapi"
+	"article-tag/internal/routes"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+// Test_OpenAPIDrift fails when a route is added without documenting it, or the other way around
+func Test_OpenAPIDrift(t *testing.T) {
+	r := routes.InitRouter(handler.New(nil, &model.Models{}, zap.NewNop()))
+
+	routed := []string{}
+	err := chi.Walk(r, func(method, route string, h http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		// the documentation itself is not documented
+		if route == openapi.SpecPath || route == "/docs" || strings.HasPrefix(route, "/docs/") {
+			return nil
+		}
+
+		routed = append(routed, method+" "+route)
+
+		return nil
+	})
+	assert.Nil(t, err)
+
+	documented := []string{}
+	for path, item := range openapi.Spec().Paths {
+		for method := range *item {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	sort.Strings(routed)
+	sort.Strings(documented)
+
+	assert.Equal(t, routed, documented)
+}
+
+// Test_Versions checks the unversioned routes are the deprecated v1 routes and v2 takes no body to delete
+func Test_Versions(t *testing.T) {
+	r := routes.InitRouter(handler.New(nil, &model.Models{}, zap.NewNop()))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		wantStatus     int
+		wantDeprecated bool
+	}{
+		{name: "v1", method: http.MethodGet, path: "/v1/tags/XX", wantStatus: http.StatusBadRequest},
+		{name: "unversioned", method: http.MethodGet, path: "/tags/XX", wantStatus: http.StatusBadRequest, wantDeprecated: true},
+		{name: "v2 delete without body", method: http.MethodDelete, path: "/v2/publications/XX/users/john/tags/1", wantStatus: http.StatusBadRequest},
+		{name: "v2 unknown route", method: http.MethodPost, path: "/v2/publications/AK/users/john/tags", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			if tt.wantDeprecated {
+				assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+				assert.Equal(t, `</v1/tags/XX>; rel="successor-version"`, rec.Header().Get("Link"))
+			} else {
+				assert.Empty(t, rec.Header().Get("Deprecation"))
+			}
+		})
+	}
+}
+
+func Test_ETag(t *testing.T) {
+	tagStoreMock := mocks.NewUserTagStore(t)
+	tagStoreMock.EXPECT().Get(mock.Anything, "john", "AK", "").Return([]*model.UserTag{{TagID: "1", TagName: "cricket"}}, nil).Twice()
+	tagStoreMock.EXPECT().Get(mock.Anything, "john", "AK", "").Return([]*model.UserTag{{TagID: "2", TagName: "football"}}, nil).Once()
+	tagStoreMock.EXPECT().Version(mock.Anything, "john", "AK").Return(int64(1), nil).Twice()
+	tagStoreMock.EXPECT().Version(mock.Anything, "john", "AK").Return(int64(2), nil).Once()
+
+	r := routes.InitRouter(handler.New(nil, &model.Models{Tag: tagStoreMock}, zap.NewNop()))
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	first := get("/v1/tags/AK?username=john", "")
+	etag := first.Header().Get("ETag")
+
+	// the etag of the followed tags is the version of the follow set, sent back as If-Match by the writes
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, `"1"`, etag)
+	assert.Equal(t, "private, no-cache", first.Header().Get("Cache-Control"))
+
+	// unchanged
+	notModified := get("/v1/tags/AK?username=john", `"other", W/`+etag)
+
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.String())
+	assert.Equal(t, etag, notModified.Header().Get("ETag"))
+
+	// changed
+	changed := get("/v1/tags/AK?username=john", etag)
+
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.Equal(t, `"2"`, changed.Header().Get("ETag"))
+	assert.Contains(t, changed.Body.String(), "football")
+
+	// errors are not cached
+	invalid := get("/v1/tags/XX?username=john", "*")
+
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+	assert.Empty(t, invalid.Header().Get("ETag"))
+	assert.Empty(t, invalid.Header().Get("Cache-Control"))
+}
+
+func Test_ETagProblem(t *testing.T) {
+	r := routes.InitRouter(handler.New(nil, &model.Models{}, zap.NewNop()))
+
+	paths := []string{
+		"/v1/tags/XX?username=john",
+		"/v1/tags/XX/popular?username=john",
+		"/v2/publications/XX/users/john/tags",
+		"/v2/publications/XX/tags/popular?username=john",
+		"/v2/publications/XX/tags/leaderboard",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("Accept", "application/problem+json")
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			// the etag middleware keeps the negotiated error format
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+			assert.Contains(t, rec.Body.String(), `"title":"Bad Request"`)
+			assert.Empty(t, rec.Header().Get("ETag"))
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"article-tag/internal/migration"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// TableUpdater
+type TableUpdater interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)
+}
+
+// Drift is a difference between the definition and the existing table
+type Drift struct {
+	Resource string
+	Field    string
+	Expected string
+	Actual   string
+	// Online is true when the change can be applied with UpdateTable,
+	// otherwise the table has to be recreated or migrated
+	Online bool
+	// Prune is true when applying the change removes an index which is not defined
+	Prune bool
+
+	update *dynamodb.UpdateTableInput
+}
+
+func (d Drift) String() string {
+	mode := "requires a new table"
+	if d.Online {
+		mode = "online"
+	}
+
+	return fmt.Sprintf("%s %s: expected %q, actual %q (%s)", d.Resource, d.Field, d.Expected, d.Actual, mode)
+}
+
+// Diff compares the definition with the description of the existing table
+func Diff(t *Table, desc *types.TableDescription) []Drift {
+	drifts := []Drift{}
+
+	// key schema can only be set when the table is created
+	expectedKeys := formatKeySchema(keySchema(t.PartitionKey, t.SortKey))
+	if actual := formatKeySchema(desc.KeySchema); actual != expectedKeys {
+		drifts = append(drifts, Drift{Resource: "table", Field: "key schema", Expected: expectedKeys, Actual: actual})
+	}
+
+	actualBilling := types.BillingModeProvisioned
+	if desc.BillingModeSummary != nil && desc.BillingModeSummary.BillingMode != "" {
+		actualBilling = desc.BillingModeSummary.BillingMode
+	}
+
+	expectedBilling := types.BillingModeProvisioned
+	if !t.provisioned() {
+		expectedBilling = types.BillingModePayPerRequest
+	}
+
+	if actualBilling != expectedBilling {
+		drifts = append(drifts, Drift{
+			Resource: "table",
+			Field:    "billing mode",
+			Expected: string(expectedBilling),
+			Actual:   string(actualBilling),
+			Online:   true,
+			update:   t.billingModeUpdate(desc),
+		})
+	} else if t.provisioned() && !t.Autoscaling && formatCapacity(desc.ProvisionedThroughput) != t.Capacity.String() {
+		drifts = append(drifts, Drift{
+			Resource: "table",
+			Field:    "capacity",
+			Expected: t.Capacity.String(),
+			Actual:   formatCapacity(desc.ProvisionedThroughput),
+			Online:   true,
+			update: &dynamodb.UpdateTableInput{
+				TableName:             aws.String(t.Name),
+				ProvisionedThroughput: t.Capacity.throughput(),
+			},
+		})
+	}
+
+	drifts = append(drifts, t.diffGlobalIndexes(desc, actualBilling == expectedBilling)...)
+	drifts = append(drifts, t.diffLocalIndexes(desc)...)
+
+	return drifts
+}
+
+// diffGlobalIndexes, capacity is only compared when the billing mode does not drift
+// as the billing mode update sets the capacity of every index
+func (t *Table) diffGlobalIndexes(desc *types.TableDescription, compareCapacity bool) []Drift {
+	drifts := []Drift{}
+	actual := map[string]types.GlobalSecondaryIndexDescription{}
+
+	for _, val := range desc.GlobalSecondaryIndexes {
+		actual[aws.ToString(val.IndexName)] = val
+	}
+
+	for _, val := range t.GlobalIndex {
+		resource := fmt.Sprintf("index %s", val.Name)
+
+		gsi, ok := actual[val.Name]
+		if !ok {
+			create := types.CreateGlobalSecondaryIndexAction{
+				IndexName:  aws.String(val.Name),
+				KeySchema:  keySchema(val.PartitionKey, val.SortKey),
+				Projection: val.Projection.projection(),
+			}
+
+			if t.provisioned() {
+				create.ProvisionedThroughput = val.Capacity.throughput()
+			}
+
+			drifts = append(drifts, Drift{
+				Resource: resource,
+				Field:    "existence",
+				Expected: "present",
+				Actual:   "missing",
+				Online:   true,
+				update: &dynamodb.UpdateTableInput{
+					TableName:                   aws.String(t.Name),
+					AttributeDefinitions:        t.attributeDefinitions(keyNames(val)),
+					GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{Create: &create}},
+				},
+			})
+
+			continue
+		}
+
+		// changing the keys or projection of an index means deleting and creating it again
+		if expected, got := formatKeySchema(keySchema(val.PartitionKey, val.SortKey)), formatKeySchema(gsi.KeySchema); expected != got {
+			drifts = append(drifts, Drift{Resource: resource, Field: "key schema", Expected: expected, Actual: got})
+		}
+
+		if expected, got := val.Projection.String(), formatProjection(gsi.Projection); expected != got {
+			drifts = append(drifts, Drift{Resource: resource, Field: "projection", Expected: expected, Actual: got})
+		}
+
+		if compareCapacity && t.provisioned() && !t.Autoscaling && formatCapacity(gsi.ProvisionedThroughput) != val.Capacity.String() {
+			drifts = append(drifts, Drift{
+				Resource: resource,
+				Field:    "capacity",
+				Expected: val.Capacity.String(),
+				Actual:   formatCapacity(gsi.ProvisionedThroughput),
+				Online:   true,
+				update: &dynamodb.UpdateTableInput{
+					TableName: aws.String(t.Name),
+					GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{Update: &types.UpdateGlobalSecondaryIndexAction{
+						IndexName:             aws.String(val.Name),
+						ProvisionedThroughput: val.Capacity.throughput(),
+					}}},
+				},
+			})
+		}
+	}
+
+	for name := range actual {
+		if _, ok := t.globalIndex(name); ok {
+			continue
+		}
+
+		drifts = append(drifts, Drift{
+			Resource: fmt.Sprintf("index %s", name),
+			Field:    "existence",
+			Expected: "missing",
+			Actual:   "present",
+			Online:   true,
+			Prune:    true,
+			update: &dynamodb.UpdateTableInput{
+				TableName: aws.String(t.Name),
+				GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{Delete: &types.DeleteGlobalSecondaryIndexAction{
+					IndexName: aws.String(name),
+				}}},
+			},
+		})
+	}
+
+	return drifts
+}
+
+// diffLocalIndexes, local indexes can only be defined when the table is created
+func (t *Table) diffLocalIndexes(desc *types.TableDescription) []Drift {
+	drifts := []Drift{}
+	actual := map[string]types.LocalSecondaryIndexDescription{}
+
+	for _, val := range desc.LocalSecondaryIndexes {
+		actual[aws.ToString(val.IndexName)] = val
+	}
+
+	expected := map[string]bool{}
+	for _, val := range t.LocalIndex {
+		resource := fmt.Sprintf("index %s", val.Name)
+		expected[val.Name] = true
+
+		lsi, ok := actual[val.Name]
+		if !ok {
+			drifts = append(drifts, Drift{Resource: resource, Field: "existence", Expected: "present", Actual: "missing"})
+			continue
+		}
+
+		if expected, got := formatKeySchema(keySchema(val.PartitionKey, val.SortKey)), formatKeySchema(lsi.KeySchema); expected != got {
+			drifts = append(drifts, Drift{Resource: resource, Field: "key schema", Expected: expected, Actual: got})
+		}
+
+		if expected, got := val.Projection.String(), formatProjection(lsi.Projection); expected != got {
+			drifts = append(drifts, Drift{Resource: resource, Field: "projection", Expected: expected, Actual: got})
+		}
+	}
+
+	for name := range actual {
+		if !expected[name] {
+			drifts = append(drifts, Drift{Resource: fmt.Sprintf("index %s", name), Field: "existence", Expected: "missing", Actual: "present"})
+		}
+	}
+
+	return drifts
+}
+
+// billingModeUpdate, switching to provisioned requires the capacity of the table and every index
+func (t *Table) billingModeUpdate(desc *types.TableDescription) *dynamodb.UpdateTableInput {
+	if !t.provisioned() {
+		return &dynamodb.UpdateTableInput{
+			TableName:   aws.String(t.Name),
+			BillingMode: types.BillingModePayPerRequest,
+		}
+	}
+
+	update := dynamodb.UpdateTableInput{
+		TableName:             aws.String(t.Name),
+		BillingMode:           types.BillingModeProvisioned,
+		ProvisionedThroughput: t.Capacity.throughput(),
+	}
+
+	for _, val := range desc.GlobalSecondaryIndexes {
+		capacity := Capacity{Read: 1, Write: 1}
+		if gsi, ok := t.globalIndex(aws.ToString(val.IndexName)); ok {
+			capacity = gsi.Capacity
+		}
+
+		update.GlobalSecondaryIndexUpdates = append(update.GlobalSecondaryIndexUpdates, types.GlobalSecondaryIndexUpdate{
+			Update: &types.UpdateGlobalSecondaryIndexAction{
+				IndexName:             val.IndexName,
+				ProvisionedThroughput: capacity.throughput(),
+			},
+		})
+	}
+
+	return &update
+}
+
+// Apply applies the online drifts one at a time, waiting for the table to become active after each one.
+// Indexes which are not defined are only deleted when prune is true.
+func Apply(ctx context.Context, db TableUpdater, table string, drifts []Drift, prune bool) ([]Drift, error) {
+	applied := []Drift{}
+
+	for _, val := range drifts {
+		if !val.Online || val.update == nil || (val.Prune && !prune) {
+			continue
+		}
+
+		_, err := db.UpdateTable(ctx, val.update)
+		if err != nil {
+			return applied, fmt.Errorf("error applying %s: %w", val, err)
+		}
+
+		// a table accepts a single index change at a time
+		if err := migration.WaitForTable(ctx, db, table); err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, val)
+	}
+
+	return applied, nil
+}
+
+// keyNames
+func keyNames(index Index) []string {
+	if index.SortKey == "" {
+		return []string{index.PartitionKey}
+	}
+
+	return []string{index.PartitionKey, index.SortKey}
+}
+
+func (c Capacity) String() string {
+	return fmt.Sprintf("read=%d write=%d", c.Read, c.Write)
+}
+
+func (p Projection) String() string {
+	return formatProjection(p.projection())
+}
+
+// formatKeySchema
+func formatKeySchema(keys []types.KeySchemaElement) string {
+	parts := []string{}
+	for _, val := range keys {
+		parts = append(parts, fmt.Sprintf("%s %s", aws.ToString(val.AttributeName), val.KeyType))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// formatProjection, non key attributes are sorted as their order is not significant
+func formatProjection(p *types.Projection) string {
+	if p == nil {
+		return ""
+	}
+
+	attrs := append([]string{}, p.NonKeyAttributes...)
+	sort.Strings(attrs)
+
+	if len(attrs) == 0 {
+		return string(p.ProjectionType)
+	}
+
+	return fmt.Sprintf("%s [%s]", p.ProjectionType, strings.Join(attrs, ", "))
+}
+
+// formatCapacity
+func formatCapacity(p *types.ProvisionedThroughputDescription) string {
+	if p == nil {
+		return Capacity{}.String()
+	}
+
+	return Capacity{Read: aws.ToInt64(p.ReadCapacityUnits), Write: aws.ToInt64(p.WriteCapacityUnits)}.String()
+}
+
+// Reconcile compares the existing table with the definition and applies the online drifts,
+// it returns every drift found and the ones which were applied
+func Reconcile(ctx context.Context, db TableUpdater, t *Table, prune bool) ([]Drift, []Drift, error) {
+	res, err := db.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(t.Name),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	drifts := Diff(t, res.Table)
+
+	applied, err := Apply(ctx, db, t.Name, drifts, prune)
+
+	return drifts, applied, err
+}
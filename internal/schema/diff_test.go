@@ -0,0 +1,305 @@
+package schema_test
+
+import (
+	"article-tag/internal/mocks"
+	"article-tag/internal/schema"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// describe returns the description of a table created from the definition
+func describe(table *schema.Table) *types.TableDescription {
+	input := table.CreateTableInput()
+
+	desc := types.TableDescription{
+		TableStatus: types.TableStatusActive,
+		TableName:   input.TableName,
+		KeySchema:   input.KeySchema,
+	}
+
+	if input.ProvisionedThroughput != nil {
+		desc.ProvisionedThroughput = &types.ProvisionedThroughputDescription{
+			ReadCapacityUnits:  input.ProvisionedThroughput.ReadCapacityUnits,
+			WriteCapacityUnits: input.ProvisionedThroughput.WriteCapacityUnits,
+		}
+	} else {
+		desc.BillingModeSummary = &types.BillingModeSummary{BillingMode: types.BillingModePayPerRequest}
+	}
+
+	for _, val := range input.GlobalSecondaryIndexes {
+		gsi := types.GlobalSecondaryIndexDescription{
+			IndexStatus: types.IndexStatusActive,
+			IndexName:   val.IndexName,
+			KeySchema:   val.KeySchema,
+			Projection:  val.Projection,
+		}
+
+		if val.ProvisionedThroughput != nil {
+			gsi.ProvisionedThroughput = &types.ProvisionedThroughputDescription{
+				ReadCapacityUnits:  val.ProvisionedThroughput.ReadCapacityUnits,
+				WriteCapacityUnits: val.ProvisionedThroughput.WriteCapacityUnits,
+			}
+		}
+
+		desc.GlobalSecondaryIndexes = append(desc.GlobalSecondaryIndexes, gsi)
+	}
+
+	for _, val := range input.LocalSecondaryIndexes {
+		desc.LocalSecondaryIndexes = append(desc.LocalSecondaryIndexes, types.LocalSecondaryIndexDescription{
+			IndexName:  val.IndexName,
+			KeySchema:  val.KeySchema,
+			Projection: val.Projection,
+		})
+	}
+
+	return &desc
+}
+
+func Test_Diff(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(table *schema.Table, desc *types.TableDescription)
+		want   []schema.Drift
+	}{
+		{
+			name:   "no drift",
+			modify: func(table *schema.Table, desc *types.TableDescription) {},
+			want:   []schema.Drift{},
+		},
+		{
+			name: "projection attributes in a different order",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				desc.GlobalSecondaryIndexes[0].Projection = &types.Projection{
+					ProjectionType:   types.ProjectionTypeInclude,
+					NonKeyAttributes: []string{"TagName", "TagID"},
+				}
+			},
+			want: []schema.Drift{},
+		},
+		{
+			name: "table capacity",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.Capacity.Read = 20
+			},
+			want: []schema.Drift{{Resource: "table", Field: "capacity", Expected: "read=20 write=5", Actual: "read=10 write=5", Online: true}},
+		},
+		{
+			name: "billing mode, index capacity is not compared",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.BillingMode = types.BillingModePayPerRequest
+			},
+			want: []schema.Drift{{Resource: "table", Field: "billing mode", Expected: "PAY_PER_REQUEST", Actual: "PROVISIONED", Online: true}},
+		},
+		{
+			name: "capacity is managed by autoscaling",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.Autoscaling = true
+				table.Capacity.Read = 20
+				table.GlobalIndex[0].Capacity.Write = 1
+			},
+			want: []schema.Drift{},
+		},
+		{
+			name: "billing mode to provisioned",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				desc.BillingModeSummary = &types.BillingModeSummary{BillingMode: types.BillingModePayPerRequest}
+				desc.ProvisionedThroughput = nil
+			},
+			want: []schema.Drift{{Resource: "table", Field: "billing mode", Expected: "PROVISIONED", Actual: "PAY_PER_REQUEST", Online: true}},
+		},
+		{
+			name: "table key schema",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.SortKey = ""
+			},
+			want: []schema.Drift{{Resource: "table", Field: "key schema", Expected: "PK HASH", Actual: "PK HASH, SK RANGE"}},
+		},
+		{
+			name: "missing global index",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				desc.GlobalSecondaryIndexes = nil
+			},
+			want: []schema.Drift{{Resource: "index TagIndex", Field: "existence", Expected: "present", Actual: "missing", Online: true}},
+		},
+		{
+			name: "unexpected global index",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.GlobalIndex = nil
+			},
+			want: []schema.Drift{{Resource: "index TagIndex", Field: "existence", Expected: "missing", Actual: "present", Online: true, Prune: true}},
+		},
+		{
+			name: "global index projection",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.GlobalIndex[0].Projection = schema.Projection{Type: types.ProjectionTypeAll}
+			},
+			want: []schema.Drift{{Resource: "index TagIndex", Field: "projection", Expected: "ALL", Actual: "INCLUDE [TagID, TagName]"}},
+		},
+		{
+			name: "global index capacity",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.GlobalIndex[0].Capacity.Write = 1
+			},
+			want: []schema.Drift{{Resource: "index TagIndex", Field: "capacity", Expected: "read=5 write=1", Actual: "read=5 write=5", Online: true}},
+		},
+		{
+			name: "local index key schema",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.LocalIndex[0].SortKey = "TagName"
+			},
+			want: []schema.Drift{{Resource: "index LSI2", Field: "key schema", Expected: "PK HASH, TagName RANGE", Actual: "PK HASH, CreatedAt RANGE"}},
+		},
+		{
+			name: "missing local index",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				desc.LocalSecondaryIndexes = nil
+			},
+			want: []schema.Drift{{Resource: "index LSI2", Field: "existence", Expected: "present", Actual: "missing"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := testTable()
+			desc := describe(testTable())
+			tt.modify(table, desc)
+
+			got := schema.Diff(table, desc)
+
+			// the update is unexported, compare the reported fields only
+			for i := range got {
+				got[i] = schema.Drift{
+					Resource: got[i].Resource,
+					Field:    got[i].Field,
+					Expected: got[i].Expected,
+					Actual:   got[i].Actual,
+					Online:   got[i].Online,
+					Prune:    got[i].Prune,
+				}
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Apply(t *testing.T) {
+	active := &dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableStatus: types.TableStatusActive}}
+
+	tests := []struct {
+		name        string
+		modify      func(table *schema.Table, desc *types.TableDescription)
+		prune       bool
+		mockDB      func(db *mocks.TableUpdater)
+		wantApplied int
+		wantErr     bool
+	}{
+		{
+			name: "online changes are applied",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.Capacity.Read = 20
+				desc.GlobalSecondaryIndexes = nil
+			},
+			mockDB: func(db *mocks.TableUpdater) {
+				db.EXPECT().UpdateTable(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateTableInput) bool {
+					return aws.ToInt64(in.ProvisionedThroughput.ReadCapacityUnits) == 20
+				})).Return(&dynamodb.UpdateTableOutput{}, nil).Once()
+				db.EXPECT().UpdateTable(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateTableInput) bool {
+					return len(in.GlobalSecondaryIndexUpdates) == 1 && in.GlobalSecondaryIndexUpdates[0].Create != nil &&
+						len(in.AttributeDefinitions) == 2
+				})).Return(&dynamodb.UpdateTableOutput{}, nil).Once()
+				db.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(active, nil).Times(2)
+			},
+			wantApplied: 2,
+		},
+		{
+			name: "offline changes and unexpected indexes are skipped without prune",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.GlobalIndex = nil
+				table.LocalIndex[0].SortKey = "TagName"
+			},
+			mockDB:      func(db *mocks.TableUpdater) {},
+			wantApplied: 0,
+		},
+		{
+			name: "unexpected indexes are deleted with prune",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.GlobalIndex = nil
+			},
+			prune: true,
+			mockDB: func(db *mocks.TableUpdater) {
+				db.EXPECT().UpdateTable(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateTableInput) bool {
+					return in.GlobalSecondaryIndexUpdates[0].Delete != nil
+				})).Return(&dynamodb.UpdateTableOutput{}, nil).Once()
+				db.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(active, nil).Once()
+			},
+			wantApplied: 1,
+		},
+		{
+			name: "error updating the table",
+			modify: func(table *schema.Table, desc *types.TableDescription) {
+				table.Capacity.Read = 20
+			},
+			mockDB: func(db *mocks.TableUpdater) {
+				db.EXPECT().UpdateTable(mock.Anything, mock.Anything).Return(nil, errors.New("some error")).Once()
+			},
+			wantApplied: 0,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := mocks.NewTableUpdater(t)
+			tt.mockDB(db)
+
+			table := testTable()
+			desc := describe(testTable())
+			tt.modify(table, desc)
+
+			applied, err := schema.Apply(context.Background(), db, table.Name, schema.Diff(table, desc), tt.prune)
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Len(t, applied, tt.wantApplied)
+		})
+	}
+}
+
+func Test_Reconcile(t *testing.T) {
+	t.Run("switching to provisioned sets the capacity of every index", func(t *testing.T) {
+		table := testTable()
+		desc := describe(testTable())
+		desc.BillingModeSummary = &types.BillingModeSummary{BillingMode: types.BillingModePayPerRequest}
+
+		db := mocks.NewTableUpdater(t)
+		db.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(&dynamodb.DescribeTableOutput{Table: desc}, nil).Times(2)
+		db.EXPECT().UpdateTable(mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateTableInput) bool {
+			return in.BillingMode == types.BillingModeProvisioned &&
+				aws.ToInt64(in.ProvisionedThroughput.ReadCapacityUnits) == 10 &&
+				len(in.GlobalSecondaryIndexUpdates) == 1 &&
+				aws.ToInt64(in.GlobalSecondaryIndexUpdates[0].Update.ProvisionedThroughput.WriteCapacityUnits) == 5
+		})).Return(&dynamodb.UpdateTableOutput{}, nil).Once()
+
+		drifts, applied, err := schema.Reconcile(context.Background(), db, table, false)
+
+		assert.NoError(t, err)
+		assert.Len(t, drifts, 1)
+		assert.Len(t, applied, 1)
+	})
+
+	t.Run("error describing the table", func(t *testing.T) {
+		db := mocks.NewTableUpdater(t)
+		db.EXPECT().DescribeTable(mock.Anything, mock.Anything).Return(nil, errors.New("some error")).Once()
+
+		_, _, err := schema.Reconcile(context.Background(), db, testTable(), false)
+
+		assert.Error(t, err)
+	})
+}
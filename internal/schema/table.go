@@ -0,0 +1,173 @@
+package schema
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Capacity is the provisioned throughput, ignored when the table is billed per request
+type Capacity struct {
+	Read  int64
+	Write int64
+}
+
+// Projection
+type Projection struct {
+	Type             types.ProjectionType
+	NonKeyAttributes []string
+}
+
+// Index is a global or local secondary index
+type Index struct {
+	Name         string
+	PartitionKey string
+	SortKey      string
+	Projection   Projection
+	Capacity     Capacity
+}
+
+// Table is the declarative definition of a table, it is used to create
+// the table and to detect the drift of an existing one
+type Table struct {
+	Name         string
+	Attributes   map[string]types.ScalarAttributeType
+	PartitionKey string
+	SortKey      string
+	BillingMode  types.BillingMode
+	Capacity     Capacity
+	// Autoscaling means the capacities are managed by application autoscaling once
+	// the table exists, they are used on creation but not reported as drift
+	Autoscaling bool
+	GlobalIndex []Index
+	LocalIndex  []Index
+}
+
+// provisioned
+func (t *Table) provisioned() bool {
+	return t.BillingMode != types.BillingModePayPerRequest
+}
+
+// CreateTableInput
+func (t *Table) CreateTableInput() *dynamodb.CreateTableInput {
+	input := dynamodb.CreateTableInput{
+		TableName:            aws.String(t.Name),
+		AttributeDefinitions: t.attributeDefinitions(t.keyAttributes()),
+		KeySchema:            keySchema(t.PartitionKey, t.SortKey),
+	}
+
+	if t.provisioned() {
+		input.ProvisionedThroughput = t.Capacity.throughput()
+	} else {
+		input.BillingMode = types.BillingModePayPerRequest
+	}
+
+	for _, val := range t.GlobalIndex {
+		gsi := types.GlobalSecondaryIndex{
+			IndexName:  aws.String(val.Name),
+			KeySchema:  keySchema(val.PartitionKey, val.SortKey),
+			Projection: val.Projection.projection(),
+		}
+
+		if t.provisioned() {
+			gsi.ProvisionedThroughput = val.Capacity.throughput()
+		}
+
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, gsi)
+	}
+
+	for _, val := range t.LocalIndex {
+		input.LocalSecondaryIndexes = append(input.LocalSecondaryIndexes, types.LocalSecondaryIndex{
+			IndexName:  aws.String(val.Name),
+			KeySchema:  keySchema(val.PartitionKey, val.SortKey),
+			Projection: val.Projection.projection(),
+		})
+	}
+
+	return &input
+}
+
+// globalIndex returns the global secondary index by name
+func (t *Table) globalIndex(name string) (Index, bool) {
+	for _, val := range t.GlobalIndex {
+		if val.Name == name {
+			return val, true
+		}
+	}
+
+	return Index{}, false
+}
+
+// keyAttributes returns the key attributes of the table and its indexes, in order of their first use
+func (t *Table) keyAttributes() []string {
+	names := []string{}
+	seen := map[string]bool{}
+
+	add := func(keys ...string) {
+		for _, k := range keys {
+			if k != "" && !seen[k] {
+				seen[k] = true
+				names = append(names, k)
+			}
+		}
+	}
+
+	add(t.PartitionKey, t.SortKey)
+	for _, val := range t.GlobalIndex {
+		add(val.PartitionKey, val.SortKey)
+	}
+
+	for _, val := range t.LocalIndex {
+		add(val.PartitionKey, val.SortKey)
+	}
+
+	return names
+}
+
+// attributeDefinitions
+func (t *Table) attributeDefinitions(names []string) []types.AttributeDefinition {
+	defs := []types.AttributeDefinition{}
+	for _, name := range names {
+		defs = append(defs, types.AttributeDefinition{
+			AttributeName: aws.String(name),
+			AttributeType: t.Attributes[name],
+		})
+	}
+
+	return defs
+}
+
+// throughput
+func (c Capacity) throughput() *types.ProvisionedThroughput {
+	return &types.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(c.Read),
+		WriteCapacityUnits: aws.Int64(c.Write),
+	}
+}
+
+// projection
+func (p Projection) projection() *types.Projection {
+	projection := types.Projection{ProjectionType: p.Type}
+	if len(p.NonKeyAttributes) > 0 {
+		projection.NonKeyAttributes = p.NonKeyAttributes
+	}
+
+	return &projection
+}
+
+// keySchema
+func keySchema(partitionKey, sortKey string) []types.KeySchemaElement {
+	keys := []types.KeySchemaElement{{
+		AttributeName: aws.String(partitionKey),
+		KeyType:       types.KeyTypeHash,
+	}}
+
+	if sortKey != "" {
+		keys = append(keys, types.KeySchemaElement{
+			AttributeName: aws.String(sortKey),
+			KeyType:       types.KeyTypeRange,
+		})
+	}
+
+	return keys
+}
@@ -0,0 +1,95 @@
+package schema_test
+
+import (
+	"article-tag/internal/schema"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testTable
+func testTable() *schema.Table {
+	return &schema.Table{
+		Name: "test-table",
+		Attributes: map[string]types.ScalarAttributeType{
+			"PK":        types.ScalarAttributeTypeS,
+			"SK":        types.ScalarAttributeTypeS,
+			"TagCount":  types.ScalarAttributeTypeN,
+			"CreatedAt": types.ScalarAttributeTypeS,
+		},
+		PartitionKey: "PK",
+		SortKey:      "SK",
+		Capacity:     schema.Capacity{Read: 10, Write: 5},
+		GlobalIndex: []schema.Index{{
+			Name:         "TagIndex",
+			PartitionKey: "PK",
+			SortKey:      "TagCount",
+			Projection:   schema.Projection{Type: types.ProjectionTypeInclude, NonKeyAttributes: []string{"TagID", "TagName"}},
+			Capacity:     schema.Capacity{Read: 5, Write: 5},
+		}},
+		LocalIndex: []schema.Index{{
+			Name:         "LSI2",
+			PartitionKey: "PK",
+			SortKey:      "CreatedAt",
+			Projection:   schema.Projection{Type: types.ProjectionTypeKeysOnly},
+		}},
+	}
+}
+
+func Test_CreateTableInput(t *testing.T) {
+	tests := []struct {
+		name            string
+		billingMode     types.BillingMode
+		wantBillingMode types.BillingMode
+		wantThroughput  bool
+	}{
+		{
+			name:           "provisioned",
+			billingMode:    types.BillingModeProvisioned,
+			wantThroughput: true,
+		},
+		{
+			name:           "default billing mode is provisioned",
+			wantThroughput: true,
+		},
+		{
+			name:            "pay per request",
+			billingMode:     types.BillingModePayPerRequest,
+			wantBillingMode: types.BillingModePayPerRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := testTable()
+			table.BillingMode = tt.billingMode
+
+			got := table.CreateTableInput()
+
+			assert.Equal(t, "test-table", aws.ToString(got.TableName))
+			assert.Equal(t, tt.wantBillingMode, got.BillingMode)
+
+			names := []string{}
+			for _, val := range got.AttributeDefinitions {
+				names = append(names, aws.ToString(val.AttributeName))
+			}
+			assert.Equal(t, []string{"PK", "SK", "TagCount", "CreatedAt"}, names)
+			assert.Equal(t, types.ScalarAttributeTypeN, got.AttributeDefinitions[2].AttributeType)
+
+			assert.Len(t, got.KeySchema, 2)
+			assert.Len(t, got.GlobalSecondaryIndexes, 1)
+			assert.Len(t, got.LocalSecondaryIndexes, 1)
+			assert.Nil(t, got.LocalSecondaryIndexes[0].Projection.NonKeyAttributes)
+
+			if tt.wantThroughput {
+				assert.Equal(t, int64(10), aws.ToInt64(got.ProvisionedThroughput.ReadCapacityUnits))
+				assert.Equal(t, int64(5), aws.ToInt64(got.GlobalSecondaryIndexes[0].ProvisionedThroughput.WriteCapacityUnits))
+			} else {
+				assert.Nil(t, got.ProvisionedThroughput)
+				assert.Nil(t, got.GlobalSecondaryIndexes[0].ProvisionedThroughput)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package types
+
+// AliasRequest maps the alias ids and names to the canonical tag of the path
+type AliasRequest struct {
+	Publication string   `json:"publication" validate:"required,oneof=RS AK ST BC"`
+	TagID       string   `json:"tag_id" validate:"required,numeric"`
+	TagName     string   `json:"tag_name" validate:"required"`
+	AliasIDs    []string `json:"alias_ids" validate:"dive,numeric"`
+	AliasNames  []string `json:"alias_names" validate:"dive,required"`
+}
+
+type AliasResponse struct {
+	TagID      string   `json:"tag_id"`
+	TagName    string   `json:"tag_name"`
+	AliasIDs   []string `json:"alias_ids"`
+	AliasNames []string `json:"alias_names"`
+}
+
+type MergeRequest struct {
+	Publication string `json:"publication" validate:"required,oneof=RS AK ST BC"`
+	TagID       string `json:"tag_id" validate:"required,numeric"`
+}
+
+type MergeResponse struct {
+	// TagID is the merged alias tag
+	TagID         string `json:"tag_id"`
+	CanonicalID   string `json:"canonical_id"`
+	CanonicalName string `json:"canonical_name"`
+	Moved         int    `json:"moved"`
+	Duplicates    int    `json:"duplicates"`
+}
@@ -0,0 +1,27 @@
+package types
+
+type AuditQueryRequest struct {
+	Username    string `json:"username" validate:"required_without=Publication"`
+	Publication string `json:"publication" validate:"required_with=TagID,omitempty,oneof=RS AK ST BC"`
+	TagID       string `json:"tag_id" validate:"omitempty,numeric"`
+	From        string `json:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To          string `json:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Limit       int    `json:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+type AuditQueryResponse struct {
+	Records []AuditEntry `json:"records"`
+}
+
+type AuditEntry struct {
+	Actor       string `json:"actor"`
+	Action      string `json:"action"`
+	Username    string `json:"username,omitempty"`
+	Publication string `json:"publication,omitempty"`
+	TagID       string `json:"tag_id,omitempty"`
+	TagName     string `json:"tag_name,omitempty"`
+	RequestID   string `json:"request_id,omitempty"`
+	SourceIP    string `json:"source_ip,omitempty"`
+	Detail      string `json:"detail,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
@@ -0,0 +1,12 @@
+package types
+
+type CacheStatsResponse struct {
+	Enabled bool                  `json:"enabled"`
+	Caches  map[string]CacheStats `json:"caches"`
+}
+
+type CacheStats struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
@@ -10,10 +10,16 @@ type GetTagRequest struct {
 	Username    string `json:"username" validate:"required"`
 	Publication string `json:"publication" validate:"required,oneof=RS AK ST BC"`
 	Order       string `json:"order" validate:"omitempty,oneof=createdatdesc createdatasc tagname"`
+	// Include implicit adds the tags followed through an ancestor
+	Include string `json:"include" validate:"omitempty,oneof=implicit"`
 }
 
 type GetTagResponse struct {
 	Tags []Tag `json:"tags"`
+	// Version of the follow set, the If-Match of the writes
+	Version int64 `json:"version"`
+	// Implicit are the descendants of the tags followed with their descendants
+	Implicit []Tag `json:"implicit,omitempty"`
 }
 
 type DeleteTagRequest struct {
@@ -22,6 +28,27 @@ type DeleteTagRequest struct {
 	Tags        []Tag  `json:"tags" validate:"required,dive"`
 }
 
+type UndoTagRequest struct {
+	Username    string `json:"username" validate:"required"`
+	Publication string `json:"publication" validate:"required,oneof=RS AK ST BC"`
+	Tags        []Tag  `json:"tags" validate:"required,dive"`
+}
+
+// ReplaceTagRequest lists every tag the user follows, an empty list unfollows all of them
+type ReplaceTagRequest struct {
+	Username    string `json:"username" validate:"required"`
+	Publication string `json:"publication" validate:"required,oneof=RS AK ST BC"`
+	Tags        []Tag  `json:"tags" validate:"required,dive"`
+}
+
+type ReplaceTagResponse struct {
+	Added   []Tag `json:"added"`
+	Removed []Tag `json:"removed"`
+	// Updated lists the followed tags whose descendants flag changed
+	Updated []Tag `json:"updated"`
+	Version int64 `json:"version"`
+}
+
 type GetPopularTagRequest struct {
 	Username    string `json:"username"`
 	Publication string `json:"publication" validate:"required,oneof=RS AK ST BC"`
@@ -30,4 +57,59 @@ type GetPopularTagRequest struct {
 type Tag struct {
 	TagID   string `json:"tag_id" validate:"required,numeric"`
 	TagName string `json:"tag_name" validate:"required"`
+	// Descendants follows the descendants of the tag in the taxonomy as well
+	Descendants bool `json:"descendants,omitempty"`
+}
+
+type PutTagRequest struct {
+	Username    string `json:"username" validate:"required"`
+	Publication string `json:"publication" validate:"required,oneof=RS AK ST BC"`
+	TagID       string `json:"tag_id" validate:"required,numeric"`
+	TagName     string `json:"tag_name" validate:"required"`
+	// Descendants follows the descendants of the tag in the taxonomy as well
+	Descendants bool `json:"descendants"`
+}
+
+type UserTagRequest struct {
+	Username    string `json:"username" validate:"required"`
+	Publication string `json:"publication" validate:"required,oneof=RS AK ST BC"`
+	TagID       string `json:"tag_id" validate:"required,numeric"`
+}
+
+type PublicationTagRequest struct {
+	Publication string `json:"publication" validate:"required,oneof=RS AK ST BC"`
+	TagID       string `json:"tag_id" validate:"required,numeric"`
+}
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQLResponse documents the response of the graphql endpoint, errors carry their code as an extension
+type GraphQLResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []GraphQLError         `json:"errors,omitempty"`
+}
+
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+type LeaderboardResponse struct {
+	Tags      []LeaderboardTag `json:"tags"`
+	UpdatedAt string           `json:"updated_at,omitempty"`
+}
+
+type LeaderboardTag struct {
+	TagID   string `json:"tag_id"`
+	TagName string `json:"tag_name"`
+	Count   int    `json:"count"`
+	Rank    int    `json:"rank"`
+	// Delta is the previous rank minus the rank, positive when the tag moved up
+	Delta int  `json:"delta"`
+	New   bool `json:"new"`
 }
@@ -0,0 +1,25 @@
+package types
+
+// TaxonomyTagRequest adds a tag to the taxonomy of the publication, without parent it is a top level tag
+type TaxonomyTagRequest struct {
+	Publication string `json:"publication" validate:"required,oneof=RS AK ST BC"`
+	TagID       string `json:"tag_id" validate:"required,numeric"`
+	TagName     string `json:"tag_name" validate:"required"`
+	ParentID    string `json:"parent_id" validate:"omitempty,numeric"`
+}
+
+type SubtreeRequest struct {
+	Publication string `json:"publication" validate:"required,oneof=RS AK ST BC"`
+	TagID       string `json:"tag_id" validate:"required,numeric"`
+}
+
+type SubtreeResponse struct {
+	// Tags are the tag followed by its descendants, level by level
+	Tags []TaxonomyTag `json:"tags"`
+}
+
+type TaxonomyTag struct {
+	TagID    string `json:"tag_id"`
+	TagName  string `json:"tag_name"`
+	ParentID string `json:"parent_id,omitempty"`
+}
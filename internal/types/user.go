@@ -0,0 +1,41 @@
+package types
+
+type UserDataRequest struct {
+	Username string `json:"username" validate:"required"`
+}
+
+type UserDataResponse struct {
+	Username string       `json:"username"`
+	Follows  []UserFollow `json:"follows"`
+	Audit    []AuditEntry `json:"audit"`
+}
+
+type UserFollow struct {
+	Publication string `json:"publication"`
+	TagID       string `json:"tag_id"`
+	TagName     string `json:"tag_name"`
+	CreatedAt   string `json:"created_at"`
+	DeletedAt   string `json:"deleted_at,omitempty"`
+}
+
+type EraseUserResponse struct {
+	Username    string `json:"username"`
+	Erased      int    `json:"erased"`
+	AuditErased int    `json:"audit_erased"`
+}
+
+// FollowLimitRequest overrides the follow limit of the publication for the user, 0 removes the override
+type FollowLimitRequest struct {
+	Username    string `json:"username" validate:"required"`
+	Publication string `json:"publication" validate:"required,oneof=RS AK ST BC"`
+	Limit       int    `json:"limit" validate:"min=0"`
+}
+
+type FollowLimitResponse struct {
+	Username    string `json:"username"`
+	Publication string `json:"publication"`
+	Follows     int    `json:"follows"`
+	// Limit in effect for the user, 0 when the follows are not limited
+	Limit      int  `json:"limit"`
+	Overridden bool `json:"overridden"`
+}
@@ -0,0 +1,33 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// New returns the validator of the requests, shared by the rest and grpc apis.
+// Fields are named by their json name, used as the path of invalid params.
+func New() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name, _, _ := strings.Cut(fld.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	return validate
+}
+
+// FieldPath removes the struct name from the namespace of the field, e.g. tags[0].tag_id
+func FieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+
+	return namespace
+}